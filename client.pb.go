@@ -2,7 +2,7 @@
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.9
+// 	protoc-gen-go v1.36.11
 // 	protoc        v4.22.3
 // source: client.proto
 
@@ -30,7 +30,19 @@ type LogClient struct {
 	// @gotags: db:"DBPolicy"
 	DBPolicy int32 `protobuf:"varint,2,opt,name=DBPolicy,proto3" json:"DBPolicy,omitempty" db:"DBPolicy"`
 	// @gotags: db:"Level"
-	Level         LogLevel `protobuf:"varint,3,opt,name=Level,proto3,enum=logs.LogLevel" json:"Level,omitempty" db:"Level"`
+	Level LogLevel `protobuf:"varint,3,opt,name=Level,proto3,enum=logs.LogLevel" json:"Level,omitempty" db:"Level"`
+	// Days to keep entries, partitions older than that are dropped by the retention janitor, 0 keeps forever
+	// @gotags: db:"RetentionDays"
+	RetentionDays int32 `protobuf:"varint,4,opt,name=RetentionDays,proto3" json:"RetentionDays,omitempty" db:"RetentionDays"`
+	// Entries per second accepted from the client, 0 is unlimited
+	// @gotags: db:"RateLimit"
+	RateLimit int32 `protobuf:"varint,5,opt,name=RateLimit,proto3" json:"RateLimit,omitempty" db:"RateLimit"`
+	// Entries accepted at once after being idle, defaults to RateLimit
+	// @gotags: db:"RateBurst"
+	RateBurst int32 `protobuf:"varint,6,opt,name=RateBurst,proto3" json:"RateBurst,omitempty" db:"RateBurst"`
+	// Bytes accepted per UTC day, 0 is unlimited
+	// @gotags: db:"DailyQuota"
+	DailyQuota    int64 `protobuf:"varint,7,opt,name=DailyQuota,proto3" json:"DailyQuota,omitempty" db:"DailyQuota"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -86,6 +98,1031 @@ func (x *LogClient) GetLevel() LogLevel {
 	return LogLevel_Verbose
 }
 
+func (x *LogClient) GetRetentionDays() int32 {
+	if x != nil {
+		return x.RetentionDays
+	}
+	return 0
+}
+
+func (x *LogClient) GetRateLimit() int32 {
+	if x != nil {
+		return x.RateLimit
+	}
+	return 0
+}
+
+func (x *LogClient) GetRateBurst() int32 {
+	if x != nil {
+		return x.RateBurst
+	}
+	return 0
+}
+
+func (x *LogClient) GetDailyQuota() int64 {
+	if x != nil {
+		return x.DailyQuota
+	}
+	return 0
+}
+
+type LogClientUsage struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ClientID string                 `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	// UTC day of the counters, yyyy-MM-dd
+	Day string `protobuf:"bytes,2,opt,name=Day,proto3" json:"Day,omitempty"`
+	// Entries and bytes accepted today
+	Entries int64 `protobuf:"varint,3,opt,name=Entries,proto3" json:"Entries,omitempty"`
+	Bytes   int64 `protobuf:"varint,4,opt,name=Bytes,proto3" json:"Bytes,omitempty"`
+	// Entries rejected today by the rate limit or the quota
+	Rejected int64 `protobuf:"varint,5,opt,name=Rejected,proto3" json:"Rejected,omitempty"`
+	// Tokens left in the rate limit bucket as of the last write
+	Tokens        int64 `protobuf:"varint,6,opt,name=Tokens,proto3" json:"Tokens,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogClientUsage) Reset() {
+	*x = LogClientUsage{}
+	mi := &file_client_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogClientUsage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogClientUsage) ProtoMessage() {}
+
+func (x *LogClientUsage) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogClientUsage.ProtoReflect.Descriptor instead.
+func (*LogClientUsage) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *LogClientUsage) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *LogClientUsage) GetDay() string {
+	if x != nil {
+		return x.Day
+	}
+	return ""
+}
+
+func (x *LogClientUsage) GetEntries() int64 {
+	if x != nil {
+		return x.Entries
+	}
+	return 0
+}
+
+func (x *LogClientUsage) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *LogClientUsage) GetRejected() int64 {
+	if x != nil {
+		return x.Rejected
+	}
+	return 0
+}
+
+func (x *LogClientUsage) GetTokens() int64 {
+	if x != nil {
+		return x.Tokens
+	}
+	return 0
+}
+
+type LogClientUsagesResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	Usages        []*LogClientUsage      `protobuf:"bytes,2,rep,name=Usages,proto3" json:"Usages,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogClientUsagesResult) Reset() {
+	*x = LogClientUsagesResult{}
+	mi := &file_client_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogClientUsagesResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogClientUsagesResult) ProtoMessage() {}
+
+func (x *LogClientUsagesResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogClientUsagesResult.ProtoReflect.Descriptor instead.
+func (*LogClientUsagesResult) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *LogClientUsagesResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogClientUsagesResult) GetUsages() []*LogClientUsage {
+	if x != nil {
+		return x.Usages
+	}
+	return nil
+}
+
+type LogClientKey struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Public prefix of the key, "<ID>.<secret>" is sent in the authorization metadata
+	ID string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	// SHA-256 of the whole key in hex, never returned by the API
+	Hash string `protobuf:"bytes,2,opt,name=Hash,proto3" json:"Hash,omitempty"`
+	// Unix milliseconds
+	CreatedOnUtc int64 `protobuf:"varint,3,opt,name=CreatedOnUtc,proto3" json:"CreatedOnUtc,omitempty"`
+	// Unix milliseconds after which the key is rejected, 0 never expires
+	ExpiresOnUtc  int64 `protobuf:"varint,4,opt,name=ExpiresOnUtc,proto3" json:"ExpiresOnUtc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogClientKey) Reset() {
+	*x = LogClientKey{}
+	mi := &file_client_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogClientKey) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogClientKey) ProtoMessage() {}
+
+func (x *LogClientKey) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogClientKey.ProtoReflect.Descriptor instead.
+func (*LogClientKey) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *LogClientKey) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *LogClientKey) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *LogClientKey) GetCreatedOnUtc() int64 {
+	if x != nil {
+		return x.CreatedOnUtc
+	}
+	return 0
+}
+
+func (x *LogClientKey) GetExpiresOnUtc() int64 {
+	if x != nil {
+		return x.ExpiresOnUtc
+	}
+	return 0
+}
+
+type LogClientKeysResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	Keys          []*LogClientKey        `protobuf:"bytes,2,rep,name=Keys,proto3" json:"Keys,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogClientKeysResult) Reset() {
+	*x = LogClientKeysResult{}
+	mi := &file_client_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogClientKeysResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogClientKeysResult) ProtoMessage() {}
+
+func (x *LogClientKeysResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogClientKeysResult.ProtoReflect.Descriptor instead.
+func (*LogClientKeysResult) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *LogClientKeysResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogClientKeysResult) GetKeys() []*LogClientKey {
+	if x != nil {
+		return x.Keys
+	}
+	return nil
+}
+
+type IssueLogClientKeyCommand struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ClientID string                 `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	// Seconds until the new key expires, 0 never expires
+	ExpiresIn int64 `protobuf:"varint,2,opt,name=ExpiresIn,proto3" json:"ExpiresIn,omitempty"`
+	// Expire the existing keys of the client Overlap seconds later, so callers can switch to the new key
+	Rotate        bool  `protobuf:"varint,3,opt,name=Rotate,proto3" json:"Rotate,omitempty"`
+	Overlap       int64 `protobuf:"varint,4,opt,name=Overlap,proto3" json:"Overlap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueLogClientKeyCommand) Reset() {
+	*x = IssueLogClientKeyCommand{}
+	mi := &file_client_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueLogClientKeyCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueLogClientKeyCommand) ProtoMessage() {}
+
+func (x *IssueLogClientKeyCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueLogClientKeyCommand.ProtoReflect.Descriptor instead.
+func (*IssueLogClientKeyCommand) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *IssueLogClientKeyCommand) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *IssueLogClientKeyCommand) GetExpiresIn() int64 {
+	if x != nil {
+		return x.ExpiresIn
+	}
+	return 0
+}
+
+func (x *IssueLogClientKeyCommand) GetRotate() bool {
+	if x != nil {
+		return x.Rotate
+	}
+	return false
+}
+
+func (x *IssueLogClientKeyCommand) GetOverlap() int64 {
+	if x != nil {
+		return x.Overlap
+	}
+	return 0
+}
+
+type IssueLogClientKeyResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	Key     *LogClientKey          `protobuf:"bytes,2,opt,name=Key,proto3" json:"Key,omitempty"`
+	// The key to send, only returned once
+	Secret        string `protobuf:"bytes,3,opt,name=Secret,proto3" json:"Secret,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *IssueLogClientKeyResult) Reset() {
+	*x = IssueLogClientKeyResult{}
+	mi := &file_client_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *IssueLogClientKeyResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*IssueLogClientKeyResult) ProtoMessage() {}
+
+func (x *IssueLogClientKeyResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use IssueLogClientKeyResult.ProtoReflect.Descriptor instead.
+func (*IssueLogClientKeyResult) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *IssueLogClientKeyResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *IssueLogClientKeyResult) GetKey() *LogClientKey {
+	if x != nil {
+		return x.Key
+	}
+	return nil
+}
+
+func (x *IssueLogClientKeyResult) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+type RevokeLogClientKeyCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientID      string                 `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	KeyID         string                 `protobuf:"bytes,2,opt,name=KeyID,proto3" json:"KeyID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeLogClientKeyCommand) Reset() {
+	*x = RevokeLogClientKeyCommand{}
+	mi := &file_client_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeLogClientKeyCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeLogClientKeyCommand) ProtoMessage() {}
+
+func (x *RevokeLogClientKeyCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeLogClientKeyCommand.ProtoReflect.Descriptor instead.
+func (*RevokeLogClientKeyCommand) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *RevokeLogClientKeyCommand) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *RevokeLogClientKeyCommand) GetKeyID() string {
+	if x != nil {
+		return x.KeyID
+	}
+	return ""
+}
+
+type AlertRule struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Generated when empty on save
+	ID       string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	ClientID string `protobuf:"bytes,2,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	Name     string `protobuf:"bytes,3,opt,name=Name,proto3" json:"Name,omitempty"`
+	// Search expression entries must match, see host/search, empty matches all entries
+	Filter string `protobuf:"bytes,4,opt,name=Filter,proto3" json:"Filter,omitempty"`
+	// Fires when more than Threshold entries match within Window seconds, 0 fires on any match
+	Threshold int64 `protobuf:"varint,5,opt,name=Threshold,proto3" json:"Threshold,omitempty"`
+	Window    int64 `protobuf:"varint,6,opt,name=Window,proto3" json:"Window,omitempty"`
+	// Min seconds between two firing notifications
+	Cooldown int64 `protobuf:"varint,7,opt,name=Cooldown,proto3" json:"Cooldown,omitempty"`
+	// Receives a POST on firing and on resolved
+	WebhookURL string `protobuf:"bytes,8,opt,name=WebhookURL,proto3" json:"WebhookURL,omitempty"`
+	// Go text/template of the POST body executed with the alert event, JSON of the event when empty
+	BodyTemplate  string `protobuf:"bytes,9,opt,name=BodyTemplate,proto3" json:"BodyTemplate,omitempty"`
+	Disabled      bool   `protobuf:"varint,10,opt,name=Disabled,proto3" json:"Disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_client_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AlertRule) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *AlertRule) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *AlertRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AlertRule) GetFilter() string {
+	if x != nil {
+		return x.Filter
+	}
+	return ""
+}
+
+func (x *AlertRule) GetThreshold() int64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWindow() int64 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *AlertRule) GetCooldown() int64 {
+	if x != nil {
+		return x.Cooldown
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWebhookURL() string {
+	if x != nil {
+		return x.WebhookURL
+	}
+	return ""
+}
+
+func (x *AlertRule) GetBodyTemplate() string {
+	if x != nil {
+		return x.BodyTemplate
+	}
+	return ""
+}
+
+func (x *AlertRule) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type AlertRuleQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRuleQuery) Reset() {
+	*x = AlertRuleQuery{}
+	mi := &file_client_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRuleQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRuleQuery) ProtoMessage() {}
+
+func (x *AlertRuleQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRuleQuery.ProtoReflect.Descriptor instead.
+func (*AlertRuleQuery) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *AlertRuleQuery) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+type AlertRulesQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All clients when empty
+	ClientID      string `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRulesQuery) Reset() {
+	*x = AlertRulesQuery{}
+	mi := &file_client_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRulesQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRulesQuery) ProtoMessage() {}
+
+func (x *AlertRulesQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRulesQuery.ProtoReflect.Descriptor instead.
+func (*AlertRulesQuery) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *AlertRulesQuery) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+type AlertRuleResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	AlertRule     *AlertRule             `protobuf:"bytes,2,opt,name=AlertRule,proto3" json:"AlertRule,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRuleResult) Reset() {
+	*x = AlertRuleResult{}
+	mi := &file_client_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRuleResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRuleResult) ProtoMessage() {}
+
+func (x *AlertRuleResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRuleResult.ProtoReflect.Descriptor instead.
+func (*AlertRuleResult) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *AlertRuleResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AlertRuleResult) GetAlertRule() *AlertRule {
+	if x != nil {
+		return x.AlertRule
+	}
+	return nil
+}
+
+type AlertRulesResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	AlertRules    []*AlertRule           `protobuf:"bytes,2,rep,name=AlertRules,proto3" json:"AlertRules,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertRulesResult) Reset() {
+	*x = AlertRulesResult{}
+	mi := &file_client_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRulesResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRulesResult) ProtoMessage() {}
+
+func (x *AlertRulesResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRulesResult.ProtoReflect.Descriptor instead.
+func (*AlertRulesResult) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *AlertRulesResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AlertRulesResult) GetAlertRules() []*AlertRule {
+	if x != nil {
+		return x.AlertRules
+	}
+	return nil
+}
+
+type AlertState struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	RuleID   string                 `protobuf:"bytes,1,opt,name=RuleID,proto3" json:"RuleID,omitempty"`
+	ClientID string                 `protobuf:"bytes,2,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	// "firing" or "resolved", empty if never fired
+	State string `protobuf:"bytes,3,opt,name=State,proto3" json:"State,omitempty"`
+	// Matching entries in the current window
+	Count int64 `protobuf:"varint,4,opt,name=Count,proto3" json:"Count,omitempty"`
+	// Unix milliseconds
+	FiredOnUtc    int64 `protobuf:"varint,5,opt,name=FiredOnUtc,proto3" json:"FiredOnUtc,omitempty"`
+	ResolvedOnUtc int64 `protobuf:"varint,6,opt,name=ResolvedOnUtc,proto3" json:"ResolvedOnUtc,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertState) Reset() {
+	*x = AlertState{}
+	mi := &file_client_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertState) ProtoMessage() {}
+
+func (x *AlertState) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertState.ProtoReflect.Descriptor instead.
+func (*AlertState) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *AlertState) GetRuleID() string {
+	if x != nil {
+		return x.RuleID
+	}
+	return ""
+}
+
+func (x *AlertState) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *AlertState) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *AlertState) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *AlertState) GetFiredOnUtc() int64 {
+	if x != nil {
+		return x.FiredOnUtc
+	}
+	return 0
+}
+
+func (x *AlertState) GetResolvedOnUtc() int64 {
+	if x != nil {
+		return x.ResolvedOnUtc
+	}
+	return 0
+}
+
+type AlertStatesResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	AlertStates   []*AlertState          `protobuf:"bytes,2,rep,name=AlertStates,proto3" json:"AlertStates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AlertStatesResult) Reset() {
+	*x = AlertStatesResult{}
+	mi := &file_client_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertStatesResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertStatesResult) ProtoMessage() {}
+
+func (x *AlertStatesResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertStatesResult.ProtoReflect.Descriptor instead.
+func (*AlertStatesResult) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *AlertStatesResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *AlertStatesResult) GetAlertStates() []*AlertState {
+	if x != nil {
+		return x.AlertStates
+	}
+	return nil
+}
+
+type LogClientQuery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ID            string                 `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogClientQuery) Reset() {
+	*x = LogClientQuery{}
+	mi := &file_client_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogClientQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogClientQuery) ProtoMessage() {}
+
+func (x *LogClientQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogClientQuery.ProtoReflect.Descriptor instead.
+func (*LogClientQuery) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *LogClientQuery) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+type LogClientResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	LogClient     *LogClient             `protobuf:"bytes,2,opt,name=LogClient,proto3" json:"LogClient,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogClientResult) Reset() {
+	*x = LogClientResult{}
+	mi := &file_client_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogClientResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogClientResult) ProtoMessage() {}
+
+func (x *LogClientResult) ProtoReflect() protoreflect.Message {
+	mi := &file_client_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogClientResult.ProtoReflect.Descriptor instead.
+func (*LogClientResult) Descriptor() ([]byte, []int) {
+	return file_client_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *LogClientResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogClientResult) GetLogClient() *LogClient {
+	if x != nil {
+		return x.LogClient
+	}
+	return nil
+}
+
 type LogClientsQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Keyword       string                 `protobuf:"bytes,1,opt,name=Keyword,proto3" json:"Keyword,omitempty"`
@@ -95,7 +1132,7 @@ type LogClientsQuery struct {
 
 func (x *LogClientsQuery) Reset() {
 	*x = LogClientsQuery{}
-	mi := &file_client_proto_msgTypes[1]
+	mi := &file_client_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -107,7 +1144,7 @@ func (x *LogClientsQuery) String() string {
 func (*LogClientsQuery) ProtoMessage() {}
 
 func (x *LogClientsQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[1]
+	mi := &file_client_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -120,7 +1157,7 @@ func (x *LogClientsQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogClientsQuery.ProtoReflect.Descriptor instead.
 func (*LogClientsQuery) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{1}
+	return file_client_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *LogClientsQuery) GetKeyword() string {
@@ -140,7 +1177,7 @@ type LogClientsResult struct {
 
 func (x *LogClientsResult) Reset() {
 	*x = LogClientsResult{}
-	mi := &file_client_proto_msgTypes[2]
+	mi := &file_client_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -152,7 +1189,7 @@ func (x *LogClientsResult) String() string {
 func (*LogClientsResult) ProtoMessage() {}
 
 func (x *LogClientsResult) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[2]
+	mi := &file_client_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -165,7 +1202,7 @@ func (x *LogClientsResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogClientsResult.ProtoReflect.Descriptor instead.
 func (*LogClientsResult) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{2}
+	return file_client_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *LogClientsResult) GetMessage() string {
@@ -191,7 +1228,7 @@ type Database struct {
 
 func (x *Database) Reset() {
 	*x = Database{}
-	mi := &file_client_proto_msgTypes[3]
+	mi := &file_client_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -203,7 +1240,7 @@ func (x *Database) String() string {
 func (*Database) ProtoMessage() {}
 
 func (x *Database) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[3]
+	mi := &file_client_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -216,7 +1253,7 @@ func (x *Database) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Database.ProtoReflect.Descriptor instead.
 func (*Database) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{3}
+	return file_client_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *Database) GetName() string {
@@ -235,7 +1272,7 @@ type DatabasesQuery struct {
 
 func (x *DatabasesQuery) Reset() {
 	*x = DatabasesQuery{}
-	mi := &file_client_proto_msgTypes[4]
+	mi := &file_client_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -247,7 +1284,7 @@ func (x *DatabasesQuery) String() string {
 func (*DatabasesQuery) ProtoMessage() {}
 
 func (x *DatabasesQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[4]
+	mi := &file_client_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -260,7 +1297,7 @@ func (x *DatabasesQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatabasesQuery.ProtoReflect.Descriptor instead.
 func (*DatabasesQuery) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{4}
+	return file_client_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *DatabasesQuery) GetClientID() string {
@@ -280,7 +1317,7 @@ type DatabasesResult struct {
 
 func (x *DatabasesResult) Reset() {
 	*x = DatabasesResult{}
-	mi := &file_client_proto_msgTypes[5]
+	mi := &file_client_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -292,7 +1329,7 @@ func (x *DatabasesResult) String() string {
 func (*DatabasesResult) ProtoMessage() {}
 
 func (x *DatabasesResult) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[5]
+	mi := &file_client_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -305,7 +1342,7 @@ func (x *DatabasesResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DatabasesResult.ProtoReflect.Descriptor instead.
 func (*DatabasesResult) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{5}
+	return file_client_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *DatabasesResult) GetMessage() string {
@@ -331,7 +1368,7 @@ type Table struct {
 
 func (x *Table) Reset() {
 	*x = Table{}
-	mi := &file_client_proto_msgTypes[6]
+	mi := &file_client_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -343,7 +1380,7 @@ func (x *Table) String() string {
 func (*Table) ProtoMessage() {}
 
 func (x *Table) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[6]
+	mi := &file_client_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -356,7 +1393,7 @@ func (x *Table) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Table.ProtoReflect.Descriptor instead.
 func (*Table) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{6}
+	return file_client_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *Table) GetName() string {
@@ -375,7 +1412,7 @@ type TablesQuery struct {
 
 func (x *TablesQuery) Reset() {
 	*x = TablesQuery{}
-	mi := &file_client_proto_msgTypes[7]
+	mi := &file_client_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -387,7 +1424,7 @@ func (x *TablesQuery) String() string {
 func (*TablesQuery) ProtoMessage() {}
 
 func (x *TablesQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[7]
+	mi := &file_client_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -400,7 +1437,7 @@ func (x *TablesQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TablesQuery.ProtoReflect.Descriptor instead.
 func (*TablesQuery) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{7}
+	return file_client_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *TablesQuery) GetDatabase() string {
@@ -420,7 +1457,7 @@ type TablesResult struct {
 
 func (x *TablesResult) Reset() {
 	*x = TablesResult{}
-	mi := &file_client_proto_msgTypes[8]
+	mi := &file_client_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -432,7 +1469,7 @@ func (x *TablesResult) String() string {
 func (*TablesResult) ProtoMessage() {}
 
 func (x *TablesResult) ProtoReflect() protoreflect.Message {
-	mi := &file_client_proto_msgTypes[8]
+	mi := &file_client_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -445,7 +1482,7 @@ func (x *TablesResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TablesResult.ProtoReflect.Descriptor instead.
 func (*TablesResult) Descriptor() ([]byte, []int) {
-	return file_client_proto_rawDescGZIP(), []int{8}
+	return file_client_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *TablesResult) GetMessage() string {
@@ -467,11 +1504,91 @@ var File_client_proto protoreflect.FileDescriptor
 const file_client_proto_rawDesc = "" +
 	"\n" +
 	"\fclient.proto\x12\x04logs\x1a\n" +
-	"logs.proto\"]\n" +
+	"logs.proto\"\xdf\x01\n" +
 	"\tLogClient\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x1a\n" +
 	"\bDBPolicy\x18\x02 \x01(\x05R\bDBPolicy\x12$\n" +
-	"\x05Level\x18\x03 \x01(\x0e2\x0e.logs.LogLevelR\x05Level\"+\n" +
+	"\x05Level\x18\x03 \x01(\x0e2\x0e.logs.LogLevelR\x05Level\x12$\n" +
+	"\rRetentionDays\x18\x04 \x01(\x05R\rRetentionDays\x12\x1c\n" +
+	"\tRateLimit\x18\x05 \x01(\x05R\tRateLimit\x12\x1c\n" +
+	"\tRateBurst\x18\x06 \x01(\x05R\tRateBurst\x12\x1e\n" +
+	"\n" +
+	"DailyQuota\x18\a \x01(\x03R\n" +
+	"DailyQuota\"\xa2\x01\n" +
+	"\x0eLogClientUsage\x12\x1a\n" +
+	"\bClientID\x18\x01 \x01(\tR\bClientID\x12\x10\n" +
+	"\x03Day\x18\x02 \x01(\tR\x03Day\x12\x18\n" +
+	"\aEntries\x18\x03 \x01(\x03R\aEntries\x12\x14\n" +
+	"\x05Bytes\x18\x04 \x01(\x03R\x05Bytes\x12\x1a\n" +
+	"\bRejected\x18\x05 \x01(\x03R\bRejected\x12\x16\n" +
+	"\x06Tokens\x18\x06 \x01(\x03R\x06Tokens\"_\n" +
+	"\x15LogClientUsagesResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x12,\n" +
+	"\x06Usages\x18\x02 \x03(\v2\x14.logs.LogClientUsageR\x06Usages\"z\n" +
+	"\fLogClientKey\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x12\n" +
+	"\x04Hash\x18\x02 \x01(\tR\x04Hash\x12\"\n" +
+	"\fCreatedOnUtc\x18\x03 \x01(\x03R\fCreatedOnUtc\x12\"\n" +
+	"\fExpiresOnUtc\x18\x04 \x01(\x03R\fExpiresOnUtc\"W\n" +
+	"\x13LogClientKeysResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x12&\n" +
+	"\x04Keys\x18\x02 \x03(\v2\x12.logs.LogClientKeyR\x04Keys\"\x86\x01\n" +
+	"\x18IssueLogClientKeyCommand\x12\x1a\n" +
+	"\bClientID\x18\x01 \x01(\tR\bClientID\x12\x1c\n" +
+	"\tExpiresIn\x18\x02 \x01(\x03R\tExpiresIn\x12\x16\n" +
+	"\x06Rotate\x18\x03 \x01(\bR\x06Rotate\x12\x18\n" +
+	"\aOverlap\x18\x04 \x01(\x03R\aOverlap\"q\n" +
+	"\x17IssueLogClientKeyResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x12$\n" +
+	"\x03Key\x18\x02 \x01(\v2\x12.logs.LogClientKeyR\x03Key\x12\x16\n" +
+	"\x06Secret\x18\x03 \x01(\tR\x06Secret\"M\n" +
+	"\x19RevokeLogClientKeyCommand\x12\x1a\n" +
+	"\bClientID\x18\x01 \x01(\tR\bClientID\x12\x14\n" +
+	"\x05KeyID\x18\x02 \x01(\tR\x05KeyID\"\x95\x02\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x1a\n" +
+	"\bClientID\x18\x02 \x01(\tR\bClientID\x12\x12\n" +
+	"\x04Name\x18\x03 \x01(\tR\x04Name\x12\x16\n" +
+	"\x06Filter\x18\x04 \x01(\tR\x06Filter\x12\x1c\n" +
+	"\tThreshold\x18\x05 \x01(\x03R\tThreshold\x12\x16\n" +
+	"\x06Window\x18\x06 \x01(\x03R\x06Window\x12\x1a\n" +
+	"\bCooldown\x18\a \x01(\x03R\bCooldown\x12\x1e\n" +
+	"\n" +
+	"WebhookURL\x18\b \x01(\tR\n" +
+	"WebhookURL\x12\"\n" +
+	"\fBodyTemplate\x18\t \x01(\tR\fBodyTemplate\x12\x1a\n" +
+	"\bDisabled\x18\n" +
+	" \x01(\bR\bDisabled\" \n" +
+	"\x0eAlertRuleQuery\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\"-\n" +
+	"\x0fAlertRulesQuery\x12\x1a\n" +
+	"\bClientID\x18\x01 \x01(\tR\bClientID\"Z\n" +
+	"\x0fAlertRuleResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x12-\n" +
+	"\tAlertRule\x18\x02 \x01(\v2\x0f.logs.AlertRuleR\tAlertRule\"]\n" +
+	"\x10AlertRulesResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x12/\n" +
+	"\n" +
+	"AlertRules\x18\x02 \x03(\v2\x0f.logs.AlertRuleR\n" +
+	"AlertRules\"\xb2\x01\n" +
+	"\n" +
+	"AlertState\x12\x16\n" +
+	"\x06RuleID\x18\x01 \x01(\tR\x06RuleID\x12\x1a\n" +
+	"\bClientID\x18\x02 \x01(\tR\bClientID\x12\x14\n" +
+	"\x05State\x18\x03 \x01(\tR\x05State\x12\x14\n" +
+	"\x05Count\x18\x04 \x01(\x03R\x05Count\x12\x1e\n" +
+	"\n" +
+	"FiredOnUtc\x18\x05 \x01(\x03R\n" +
+	"FiredOnUtc\x12$\n" +
+	"\rResolvedOnUtc\x18\x06 \x01(\x03R\rResolvedOnUtc\"a\n" +
+	"\x11AlertStatesResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x122\n" +
+	"\vAlertStates\x18\x02 \x03(\v2\x10.logs.AlertStateR\vAlertStates\" \n" +
+	"\x0eLogClientQuery\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\"Z\n" +
+	"\x0fLogClientResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x12-\n" +
+	"\tLogClient\x18\x02 \x01(\v2\x0f.logs.LogClientR\tLogClient\"+\n" +
 	"\x0fLogClientsQuery\x12\x18\n" +
 	"\aKeyword\x18\x01 \x01(\tR\aKeyword\"L\n" +
 	"\x10LogClientsResult\x12\x18\n" +
@@ -492,12 +1609,24 @@ const file_client_proto_rawDesc = "" +
 	"\bDatabase\x18\x01 \x01(\tR\bDatabase\"@\n" +
 	"\fTablesResult\x12\x18\n" +
 	"\aMessage\x18\x01 \x01(\tR\aMessage\x12\x16\n" +
-	"\x06Tables\x18\x02 \x03(\tR\x06Tables2\xc0\x01\n" +
-	"\x10LogClientService\x12;\n" +
+	"\x06Tables\x18\x02 \x03(\tR\x06Tables2\xca\a\n" +
+	"\x10LogClientService\x128\n" +
+	"\tGetClient\x12\x14.logs.LogClientQuery\x1a\x15.logs.LogClientResult\x126\n" +
+	"\fCreateClient\x12\x0f.logs.LogClient\x1a\x15.logs.LogClientResult\x126\n" +
+	"\fUpdateClient\x12\x0f.logs.LogClient\x1a\x15.logs.LogClientResult\x12;\n" +
+	"\fDeleteClient\x12\x14.logs.LogClientQuery\x1a\x15.logs.LogClientResult\x12;\n" +
 	"\n" +
 	"GetClients\x12\x15.logs.LogClientsQuery\x1a\x16.logs.LogClientsResult\x12;\n" +
 	"\fGetDatabases\x12\x14.logs.DatabasesQuery\x1a\x15.logs.DatabasesResult\x122\n" +
-	"\tGetTables\x12\x11.logs.TablesQuery\x1a\x12.logs.TablesResultB\x1dZ\x1bgithub.com/DreamvatLab/logsb\x06proto3"
+	"\tGetTables\x12\x11.logs.TablesQuery\x1a\x12.logs.TablesResult\x12@\n" +
+	"\rGetClientKeys\x12\x14.logs.LogClientQuery\x1a\x19.logs.LogClientKeysResult\x12O\n" +
+	"\x0eIssueClientKey\x12\x1e.logs.IssueLogClientKeyCommand\x1a\x1d.logs.IssueLogClientKeyResult\x12M\n" +
+	"\x0fRevokeClientKey\x12\x1f.logs.RevokeLogClientKeyCommand\x1a\x19.logs.LogClientKeysResult\x12D\n" +
+	"\x0fGetClientUsages\x12\x14.logs.LogClientQuery\x1a\x1b.logs.LogClientUsagesResult\x12>\n" +
+	"\rGetAlertRules\x12\x15.logs.AlertRulesQuery\x1a\x16.logs.AlertRulesResult\x127\n" +
+	"\rSaveAlertRule\x12\x0f.logs.AlertRule\x1a\x15.logs.AlertRuleResult\x12>\n" +
+	"\x0fDeleteAlertRule\x12\x14.logs.AlertRuleQuery\x1a\x15.logs.AlertRuleResult\x12@\n" +
+	"\x0eGetAlertStates\x12\x15.logs.AlertRulesQuery\x1a\x17.logs.AlertStatesResultB\x1dZ\x1bgithub.com/DreamvatLab/logsb\x06proto3"
 
 var (
 	file_client_proto_rawDescOnce sync.Once
@@ -511,32 +1640,79 @@ func file_client_proto_rawDescGZIP() []byte {
 	return file_client_proto_rawDescData
 }
 
-var file_client_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_client_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
 var file_client_proto_goTypes = []any{
-	(*LogClient)(nil),        // 0: logs.LogClient
-	(*LogClientsQuery)(nil),  // 1: logs.LogClientsQuery
-	(*LogClientsResult)(nil), // 2: logs.LogClientsResult
-	(*Database)(nil),         // 3: logs.Database
-	(*DatabasesQuery)(nil),   // 4: logs.DatabasesQuery
-	(*DatabasesResult)(nil),  // 5: logs.DatabasesResult
-	(*Table)(nil),            // 6: logs.Table
-	(*TablesQuery)(nil),      // 7: logs.TablesQuery
-	(*TablesResult)(nil),     // 8: logs.TablesResult
-	(LogLevel)(0),            // 9: logs.LogLevel
+	(*LogClient)(nil),                 // 0: logs.LogClient
+	(*LogClientUsage)(nil),            // 1: logs.LogClientUsage
+	(*LogClientUsagesResult)(nil),     // 2: logs.LogClientUsagesResult
+	(*LogClientKey)(nil),              // 3: logs.LogClientKey
+	(*LogClientKeysResult)(nil),       // 4: logs.LogClientKeysResult
+	(*IssueLogClientKeyCommand)(nil),  // 5: logs.IssueLogClientKeyCommand
+	(*IssueLogClientKeyResult)(nil),   // 6: logs.IssueLogClientKeyResult
+	(*RevokeLogClientKeyCommand)(nil), // 7: logs.RevokeLogClientKeyCommand
+	(*AlertRule)(nil),                 // 8: logs.AlertRule
+	(*AlertRuleQuery)(nil),            // 9: logs.AlertRuleQuery
+	(*AlertRulesQuery)(nil),           // 10: logs.AlertRulesQuery
+	(*AlertRuleResult)(nil),           // 11: logs.AlertRuleResult
+	(*AlertRulesResult)(nil),          // 12: logs.AlertRulesResult
+	(*AlertState)(nil),                // 13: logs.AlertState
+	(*AlertStatesResult)(nil),         // 14: logs.AlertStatesResult
+	(*LogClientQuery)(nil),            // 15: logs.LogClientQuery
+	(*LogClientResult)(nil),           // 16: logs.LogClientResult
+	(*LogClientsQuery)(nil),           // 17: logs.LogClientsQuery
+	(*LogClientsResult)(nil),          // 18: logs.LogClientsResult
+	(*Database)(nil),                  // 19: logs.Database
+	(*DatabasesQuery)(nil),            // 20: logs.DatabasesQuery
+	(*DatabasesResult)(nil),           // 21: logs.DatabasesResult
+	(*Table)(nil),                     // 22: logs.Table
+	(*TablesQuery)(nil),               // 23: logs.TablesQuery
+	(*TablesResult)(nil),              // 24: logs.TablesResult
+	(LogLevel)(0),                     // 25: logs.LogLevel
 }
 var file_client_proto_depIdxs = []int32{
-	9, // 0: logs.LogClient.Level:type_name -> logs.LogLevel
-	1, // 1: logs.LogClientService.GetClients:input_type -> logs.LogClientsQuery
-	4, // 2: logs.LogClientService.GetDatabases:input_type -> logs.DatabasesQuery
-	7, // 3: logs.LogClientService.GetTables:input_type -> logs.TablesQuery
-	2, // 4: logs.LogClientService.GetClients:output_type -> logs.LogClientsResult
-	5, // 5: logs.LogClientService.GetDatabases:output_type -> logs.DatabasesResult
-	8, // 6: logs.LogClientService.GetTables:output_type -> logs.TablesResult
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	25, // 0: logs.LogClient.Level:type_name -> logs.LogLevel
+	1,  // 1: logs.LogClientUsagesResult.Usages:type_name -> logs.LogClientUsage
+	3,  // 2: logs.LogClientKeysResult.Keys:type_name -> logs.LogClientKey
+	3,  // 3: logs.IssueLogClientKeyResult.Key:type_name -> logs.LogClientKey
+	8,  // 4: logs.AlertRuleResult.AlertRule:type_name -> logs.AlertRule
+	8,  // 5: logs.AlertRulesResult.AlertRules:type_name -> logs.AlertRule
+	13, // 6: logs.AlertStatesResult.AlertStates:type_name -> logs.AlertState
+	0,  // 7: logs.LogClientResult.LogClient:type_name -> logs.LogClient
+	15, // 8: logs.LogClientService.GetClient:input_type -> logs.LogClientQuery
+	0,  // 9: logs.LogClientService.CreateClient:input_type -> logs.LogClient
+	0,  // 10: logs.LogClientService.UpdateClient:input_type -> logs.LogClient
+	15, // 11: logs.LogClientService.DeleteClient:input_type -> logs.LogClientQuery
+	17, // 12: logs.LogClientService.GetClients:input_type -> logs.LogClientsQuery
+	20, // 13: logs.LogClientService.GetDatabases:input_type -> logs.DatabasesQuery
+	23, // 14: logs.LogClientService.GetTables:input_type -> logs.TablesQuery
+	15, // 15: logs.LogClientService.GetClientKeys:input_type -> logs.LogClientQuery
+	5,  // 16: logs.LogClientService.IssueClientKey:input_type -> logs.IssueLogClientKeyCommand
+	7,  // 17: logs.LogClientService.RevokeClientKey:input_type -> logs.RevokeLogClientKeyCommand
+	15, // 18: logs.LogClientService.GetClientUsages:input_type -> logs.LogClientQuery
+	10, // 19: logs.LogClientService.GetAlertRules:input_type -> logs.AlertRulesQuery
+	8,  // 20: logs.LogClientService.SaveAlertRule:input_type -> logs.AlertRule
+	9,  // 21: logs.LogClientService.DeleteAlertRule:input_type -> logs.AlertRuleQuery
+	10, // 22: logs.LogClientService.GetAlertStates:input_type -> logs.AlertRulesQuery
+	16, // 23: logs.LogClientService.GetClient:output_type -> logs.LogClientResult
+	16, // 24: logs.LogClientService.CreateClient:output_type -> logs.LogClientResult
+	16, // 25: logs.LogClientService.UpdateClient:output_type -> logs.LogClientResult
+	16, // 26: logs.LogClientService.DeleteClient:output_type -> logs.LogClientResult
+	18, // 27: logs.LogClientService.GetClients:output_type -> logs.LogClientsResult
+	21, // 28: logs.LogClientService.GetDatabases:output_type -> logs.DatabasesResult
+	24, // 29: logs.LogClientService.GetTables:output_type -> logs.TablesResult
+	4,  // 30: logs.LogClientService.GetClientKeys:output_type -> logs.LogClientKeysResult
+	6,  // 31: logs.LogClientService.IssueClientKey:output_type -> logs.IssueLogClientKeyResult
+	4,  // 32: logs.LogClientService.RevokeClientKey:output_type -> logs.LogClientKeysResult
+	2,  // 33: logs.LogClientService.GetClientUsages:output_type -> logs.LogClientUsagesResult
+	12, // 34: logs.LogClientService.GetAlertRules:output_type -> logs.AlertRulesResult
+	11, // 35: logs.LogClientService.SaveAlertRule:output_type -> logs.AlertRuleResult
+	11, // 36: logs.LogClientService.DeleteAlertRule:output_type -> logs.AlertRuleResult
+	14, // 37: logs.LogClientService.GetAlertStates:output_type -> logs.AlertStatesResult
+	23, // [23:38] is the sub-list for method output_type
+	8,  // [8:23] is the sub-list for method input_type
+	8,  // [8:8] is the sub-list for extension type_name
+	8,  // [8:8] is the sub-list for extension extendee
+	0,  // [0:8] is the sub-list for field type_name
 }
 
 func init() { file_client_proto_init() }
@@ -551,7 +1727,7 @@ func file_client_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_client_proto_rawDesc), len(file_client_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   9,
+			NumMessages:   25,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,18 +21,44 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LogClientService_GetClients_FullMethodName   = "/logs.LogClientService/GetClients"
-	LogClientService_GetDatabases_FullMethodName = "/logs.LogClientService/GetDatabases"
-	LogClientService_GetTables_FullMethodName    = "/logs.LogClientService/GetTables"
+	LogClientService_GetClient_FullMethodName       = "/logs.LogClientService/GetClient"
+	LogClientService_CreateClient_FullMethodName    = "/logs.LogClientService/CreateClient"
+	LogClientService_UpdateClient_FullMethodName    = "/logs.LogClientService/UpdateClient"
+	LogClientService_DeleteClient_FullMethodName    = "/logs.LogClientService/DeleteClient"
+	LogClientService_GetClients_FullMethodName      = "/logs.LogClientService/GetClients"
+	LogClientService_GetDatabases_FullMethodName    = "/logs.LogClientService/GetDatabases"
+	LogClientService_GetTables_FullMethodName       = "/logs.LogClientService/GetTables"
+	LogClientService_GetClientKeys_FullMethodName   = "/logs.LogClientService/GetClientKeys"
+	LogClientService_IssueClientKey_FullMethodName  = "/logs.LogClientService/IssueClientKey"
+	LogClientService_RevokeClientKey_FullMethodName = "/logs.LogClientService/RevokeClientKey"
+	LogClientService_GetClientUsages_FullMethodName = "/logs.LogClientService/GetClientUsages"
+	LogClientService_GetAlertRules_FullMethodName   = "/logs.LogClientService/GetAlertRules"
+	LogClientService_SaveAlertRule_FullMethodName   = "/logs.LogClientService/SaveAlertRule"
+	LogClientService_DeleteAlertRule_FullMethodName = "/logs.LogClientService/DeleteAlertRule"
+	LogClientService_GetAlertStates_FullMethodName  = "/logs.LogClientService/GetAlertStates"
 )
 
 // LogClientServiceClient is the client API for LogClientService service.
 //
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LogClientServiceClient interface {
+	GetClient(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientResult, error)
+	CreateClient(ctx context.Context, in *LogClient, opts ...grpc.CallOption) (*LogClientResult, error)
+	UpdateClient(ctx context.Context, in *LogClient, opts ...grpc.CallOption) (*LogClientResult, error)
+	DeleteClient(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientResult, error)
 	GetClients(ctx context.Context, in *LogClientsQuery, opts ...grpc.CallOption) (*LogClientsResult, error)
 	GetDatabases(ctx context.Context, in *DatabasesQuery, opts ...grpc.CallOption) (*DatabasesResult, error)
 	GetTables(ctx context.Context, in *TablesQuery, opts ...grpc.CallOption) (*TablesResult, error)
+	GetClientKeys(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientKeysResult, error)
+	IssueClientKey(ctx context.Context, in *IssueLogClientKeyCommand, opts ...grpc.CallOption) (*IssueLogClientKeyResult, error)
+	RevokeClientKey(ctx context.Context, in *RevokeLogClientKeyCommand, opts ...grpc.CallOption) (*LogClientKeysResult, error)
+	// Usage counters of this host, all clients when ID is empty
+	GetClientUsages(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientUsagesResult, error)
+	GetAlertRules(ctx context.Context, in *AlertRulesQuery, opts ...grpc.CallOption) (*AlertRulesResult, error)
+	// Creates the rule when ID is empty, otherwise replaces it
+	SaveAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRuleResult, error)
+	DeleteAlertRule(ctx context.Context, in *AlertRuleQuery, opts ...grpc.CallOption) (*AlertRuleResult, error)
+	GetAlertStates(ctx context.Context, in *AlertRulesQuery, opts ...grpc.CallOption) (*AlertStatesResult, error)
 }
 
 type logClientServiceClient struct {
@@ -43,6 +69,46 @@ func NewLogClientServiceClient(cc grpc.ClientConnInterface) LogClientServiceClie
 	return &logClientServiceClient{cc}
 }
 
+func (c *logClientServiceClient) GetClient(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogClientResult)
+	err := c.cc.Invoke(ctx, LogClientService_GetClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) CreateClient(ctx context.Context, in *LogClient, opts ...grpc.CallOption) (*LogClientResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogClientResult)
+	err := c.cc.Invoke(ctx, LogClientService_CreateClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) UpdateClient(ctx context.Context, in *LogClient, opts ...grpc.CallOption) (*LogClientResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogClientResult)
+	err := c.cc.Invoke(ctx, LogClientService_UpdateClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) DeleteClient(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogClientResult)
+	err := c.cc.Invoke(ctx, LogClientService_DeleteClient_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *logClientServiceClient) GetClients(ctx context.Context, in *LogClientsQuery, opts ...grpc.CallOption) (*LogClientsResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LogClientsResult)
@@ -73,13 +139,107 @@ func (c *logClientServiceClient) GetTables(ctx context.Context, in *TablesQuery,
 	return out, nil
 }
 
+func (c *logClientServiceClient) GetClientKeys(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientKeysResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogClientKeysResult)
+	err := c.cc.Invoke(ctx, LogClientService_GetClientKeys_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) IssueClientKey(ctx context.Context, in *IssueLogClientKeyCommand, opts ...grpc.CallOption) (*IssueLogClientKeyResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(IssueLogClientKeyResult)
+	err := c.cc.Invoke(ctx, LogClientService_IssueClientKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) RevokeClientKey(ctx context.Context, in *RevokeLogClientKeyCommand, opts ...grpc.CallOption) (*LogClientKeysResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogClientKeysResult)
+	err := c.cc.Invoke(ctx, LogClientService_RevokeClientKey_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) GetClientUsages(ctx context.Context, in *LogClientQuery, opts ...grpc.CallOption) (*LogClientUsagesResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogClientUsagesResult)
+	err := c.cc.Invoke(ctx, LogClientService_GetClientUsages_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) GetAlertRules(ctx context.Context, in *AlertRulesQuery, opts ...grpc.CallOption) (*AlertRulesResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRulesResult)
+	err := c.cc.Invoke(ctx, LogClientService_GetAlertRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) SaveAlertRule(ctx context.Context, in *AlertRule, opts ...grpc.CallOption) (*AlertRuleResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResult)
+	err := c.cc.Invoke(ctx, LogClientService_SaveAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) DeleteAlertRule(ctx context.Context, in *AlertRuleQuery, opts ...grpc.CallOption) (*AlertRuleResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResult)
+	err := c.cc.Invoke(ctx, LogClientService_DeleteAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logClientServiceClient) GetAlertStates(ctx context.Context, in *AlertRulesQuery, opts ...grpc.CallOption) (*AlertStatesResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertStatesResult)
+	err := c.cc.Invoke(ctx, LogClientService_GetAlertStates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // LogClientServiceServer is the server API for LogClientService service.
 // All implementations should embed UnimplementedLogClientServiceServer
 // for forward compatibility.
 type LogClientServiceServer interface {
+	GetClient(context.Context, *LogClientQuery) (*LogClientResult, error)
+	CreateClient(context.Context, *LogClient) (*LogClientResult, error)
+	UpdateClient(context.Context, *LogClient) (*LogClientResult, error)
+	DeleteClient(context.Context, *LogClientQuery) (*LogClientResult, error)
 	GetClients(context.Context, *LogClientsQuery) (*LogClientsResult, error)
 	GetDatabases(context.Context, *DatabasesQuery) (*DatabasesResult, error)
 	GetTables(context.Context, *TablesQuery) (*TablesResult, error)
+	GetClientKeys(context.Context, *LogClientQuery) (*LogClientKeysResult, error)
+	IssueClientKey(context.Context, *IssueLogClientKeyCommand) (*IssueLogClientKeyResult, error)
+	RevokeClientKey(context.Context, *RevokeLogClientKeyCommand) (*LogClientKeysResult, error)
+	// Usage counters of this host, all clients when ID is empty
+	GetClientUsages(context.Context, *LogClientQuery) (*LogClientUsagesResult, error)
+	GetAlertRules(context.Context, *AlertRulesQuery) (*AlertRulesResult, error)
+	// Creates the rule when ID is empty, otherwise replaces it
+	SaveAlertRule(context.Context, *AlertRule) (*AlertRuleResult, error)
+	DeleteAlertRule(context.Context, *AlertRuleQuery) (*AlertRuleResult, error)
+	GetAlertStates(context.Context, *AlertRulesQuery) (*AlertStatesResult, error)
 }
 
 // UnimplementedLogClientServiceServer should be embedded to have
@@ -89,6 +249,18 @@ type LogClientServiceServer interface {
 // pointer dereference when methods are called.
 type UnimplementedLogClientServiceServer struct{}
 
+func (UnimplementedLogClientServiceServer) GetClient(context.Context, *LogClientQuery) (*LogClientResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClient not implemented")
+}
+func (UnimplementedLogClientServiceServer) CreateClient(context.Context, *LogClient) (*LogClientResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateClient not implemented")
+}
+func (UnimplementedLogClientServiceServer) UpdateClient(context.Context, *LogClient) (*LogClientResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateClient not implemented")
+}
+func (UnimplementedLogClientServiceServer) DeleteClient(context.Context, *LogClientQuery) (*LogClientResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteClient not implemented")
+}
 func (UnimplementedLogClientServiceServer) GetClients(context.Context, *LogClientsQuery) (*LogClientsResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetClients not implemented")
 }
@@ -98,6 +270,30 @@ func (UnimplementedLogClientServiceServer) GetDatabases(context.Context, *Databa
 func (UnimplementedLogClientServiceServer) GetTables(context.Context, *TablesQuery) (*TablesResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTables not implemented")
 }
+func (UnimplementedLogClientServiceServer) GetClientKeys(context.Context, *LogClientQuery) (*LogClientKeysResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClientKeys not implemented")
+}
+func (UnimplementedLogClientServiceServer) IssueClientKey(context.Context, *IssueLogClientKeyCommand) (*IssueLogClientKeyResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method IssueClientKey not implemented")
+}
+func (UnimplementedLogClientServiceServer) RevokeClientKey(context.Context, *RevokeLogClientKeyCommand) (*LogClientKeysResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevokeClientKey not implemented")
+}
+func (UnimplementedLogClientServiceServer) GetClientUsages(context.Context, *LogClientQuery) (*LogClientUsagesResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetClientUsages not implemented")
+}
+func (UnimplementedLogClientServiceServer) GetAlertRules(context.Context, *AlertRulesQuery) (*AlertRulesResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlertRules not implemented")
+}
+func (UnimplementedLogClientServiceServer) SaveAlertRule(context.Context, *AlertRule) (*AlertRuleResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SaveAlertRule not implemented")
+}
+func (UnimplementedLogClientServiceServer) DeleteAlertRule(context.Context, *AlertRuleQuery) (*AlertRuleResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlertRule not implemented")
+}
+func (UnimplementedLogClientServiceServer) GetAlertStates(context.Context, *AlertRulesQuery) (*AlertStatesResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlertStates not implemented")
+}
 func (UnimplementedLogClientServiceServer) testEmbeddedByValue() {}
 
 // UnsafeLogClientServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -118,6 +314,78 @@ func RegisterLogClientServiceServer(s grpc.ServiceRegistrar, srv LogClientServic
 	s.RegisterService(&LogClientService_ServiceDesc, srv)
 }
 
+func _LogClientService_GetClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogClientQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).GetClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_GetClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).GetClient(ctx, req.(*LogClientQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_CreateClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogClient)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).CreateClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_CreateClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).CreateClient(ctx, req.(*LogClient))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_UpdateClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogClient)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).UpdateClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_UpdateClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).UpdateClient(ctx, req.(*LogClient))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_DeleteClient_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogClientQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).DeleteClient(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_DeleteClient_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).DeleteClient(ctx, req.(*LogClientQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _LogClientService_GetClients_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LogClientsQuery)
 	if err := dec(in); err != nil {
@@ -172,6 +440,150 @@ func _LogClientService_GetTables_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogClientService_GetClientKeys_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogClientQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).GetClientKeys(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_GetClientKeys_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).GetClientKeys(ctx, req.(*LogClientQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_IssueClientKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IssueLogClientKeyCommand)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).IssueClientKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_IssueClientKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).IssueClientKey(ctx, req.(*IssueLogClientKeyCommand))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_RevokeClientKey_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeLogClientKeyCommand)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).RevokeClientKey(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_RevokeClientKey_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).RevokeClientKey(ctx, req.(*RevokeLogClientKeyCommand))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_GetClientUsages_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogClientQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).GetClientUsages(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_GetClientUsages_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).GetClientUsages(ctx, req.(*LogClientQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_GetAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRulesQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).GetAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_GetAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).GetAlertRules(ctx, req.(*AlertRulesQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_SaveAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRule)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).SaveAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_SaveAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).SaveAlertRule(ctx, req.(*AlertRule))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_DeleteAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRuleQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).DeleteAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_DeleteAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).DeleteAlertRule(ctx, req.(*AlertRuleQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogClientService_GetAlertStates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AlertRulesQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogClientServiceServer).GetAlertStates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogClientService_GetAlertStates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogClientServiceServer).GetAlertStates(ctx, req.(*AlertRulesQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // LogClientService_ServiceDesc is the grpc.ServiceDesc for LogClientService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -179,6 +591,22 @@ var LogClientService_ServiceDesc = grpc.ServiceDesc{
 	ServiceName: "logs.LogClientService",
 	HandlerType: (*LogClientServiceServer)(nil),
 	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetClient",
+			Handler:    _LogClientService_GetClient_Handler,
+		},
+		{
+			MethodName: "CreateClient",
+			Handler:    _LogClientService_CreateClient_Handler,
+		},
+		{
+			MethodName: "UpdateClient",
+			Handler:    _LogClientService_UpdateClient_Handler,
+		},
+		{
+			MethodName: "DeleteClient",
+			Handler:    _LogClientService_DeleteClient_Handler,
+		},
 		{
 			MethodName: "GetClients",
 			Handler:    _LogClientService_GetClients_Handler,
@@ -191,6 +619,38 @@ var LogClientService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetTables",
 			Handler:    _LogClientService_GetTables_Handler,
 		},
+		{
+			MethodName: "GetClientKeys",
+			Handler:    _LogClientService_GetClientKeys_Handler,
+		},
+		{
+			MethodName: "IssueClientKey",
+			Handler:    _LogClientService_IssueClientKey_Handler,
+		},
+		{
+			MethodName: "RevokeClientKey",
+			Handler:    _LogClientService_RevokeClientKey_Handler,
+		},
+		{
+			MethodName: "GetClientUsages",
+			Handler:    _LogClientService_GetClientUsages_Handler,
+		},
+		{
+			MethodName: "GetAlertRules",
+			Handler:    _LogClientService_GetAlertRules_Handler,
+		},
+		{
+			MethodName: "SaveAlertRule",
+			Handler:    _LogClientService_SaveAlertRule_Handler,
+		},
+		{
+			MethodName: "DeleteAlertRule",
+			Handler:    _LogClientService_DeleteAlertRule_Handler,
+		},
+		{
+			MethodName: "GetAlertStates",
+			Handler:    _LogClientService_GetAlertStates_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "client.proto",
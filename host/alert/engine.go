@@ -0,0 +1,347 @@
+// Package alert evaluates alert rules on ingested entries and notifies webhooks when they fire and resolve.
+package alert
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xlog"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/search"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	StateFiring   = "firing"
+	StateResolved = "resolved"
+
+	_WEBHOOK_TIMEOUT = time.Second * 10
+)
+
+var (
+	_templateFuncs = template.FuncMap{
+		"json": func(v any) (string, error) {
+			jsonBytes, err := json.Marshal(v)
+			return string(jsonBytes), err
+		},
+	}
+)
+
+// Event is the data of a notification, it is also the body when the rule has no BodyTemplate
+type Event struct {
+	RuleID    string
+	RuleName  string
+	ClientID  string
+	State     string
+	Count     int64
+	Threshold int64
+	Window    int64
+	// Unix milliseconds
+	OccurredOnUtc int64
+	// The latest matching entry
+	Sample *logs.LogEntry
+}
+
+type Engine struct {
+	loader     func() ([]*logs.AlertRule, error)
+	locker     sync.Mutex
+	rules      map[string]*rule
+	byClient   map[string][]*rule
+	httpClient *http.Client
+}
+
+// NewEngine creates an engine loading rules with loader, call Run to start it
+func NewEngine(loader func() ([]*logs.AlertRule, error)) *Engine {
+	return &Engine{
+		loader:     loader,
+		rules:      make(map[string]*rule),
+		byClient:   make(map[string][]*rule),
+		httpClient: &http.Client{Timeout: _WEBHOOK_TIMEOUT},
+	}
+}
+
+// ValidateRule checks the filter, template and webhook of a rule
+func ValidateRule(in *logs.AlertRule) error {
+	if in == nil {
+		return xerr.New("rule cannot be nil")
+	}
+	if in.Window <= 0 {
+		return xerr.New("Window must be greater than 0")
+	}
+	if in.Threshold < 0 || in.Cooldown < 0 {
+		return xerr.New("Threshold and Cooldown must not be negative")
+	}
+	u, err := url.Parse(in.WebhookURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return xerr.Errorf("invalid WebhookURL '%s'", in.WebhookURL)
+	}
+	_, err = newRule(in)
+	return err
+}
+
+// Run reloads rules and resolves alerts whose window has passed every interval, it never returns
+func (o *Engine) Run(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		o.Reload()
+
+		var notifications []*notification
+		o.locker.Lock()
+		now := time.Now()
+		for _, x := range o.rules {
+			if n := o.check(x, now); n != nil {
+				notifications = append(notifications, n)
+			}
+		}
+		o.locker.Unlock()
+		for _, x := range notifications {
+			o.notify(x)
+		}
+
+		<-ticker.C
+	}
+}
+
+// Reload applies rule changes, counters and states of unchanged rules are kept
+func (o *Engine) Reload() {
+	rules, err := o.loader()
+	if xerr.LogError(err) {
+		return
+	}
+
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	newRules := make(map[string]*rule, len(rules))
+	byClient := make(map[string][]*rule)
+	for _, x := range rules {
+		if x.Disabled {
+			continue
+		}
+
+		r := o.rules[x.ID]
+		if r == nil || !proto.Equal(r.AlertRule, x) {
+			changed, err := newRule(x)
+			if xerr.LogError(err) {
+				continue
+			}
+			// Counters are kept while they count the same entries, a rule with another filter or client starts over
+			if r != nil && r.Filter == x.Filter && r.ClientID == x.ClientID {
+				changed.buckets, changed.count = r.buckets, r.count
+				changed.state, changed.firedOn, changed.resolvedOn, changed.sample = r.state, r.firedOn, r.resolvedOn, r.sample
+			}
+			r = changed
+		}
+
+		newRules[x.ID] = r
+		byClient[x.ClientID] = append(byClient[x.ClientID], r)
+	}
+
+	o.rules = newRules
+	o.byClient = byClient
+}
+
+// Observe counts stored entries of a client against its rules
+func (o *Engine) Observe(clientID string, entries []*logs.LogEntry) {
+	var notifications []*notification
+	o.locker.Lock()
+	rules := o.byClient[clientID]
+	now := time.Now()
+	for _, r := range rules {
+		var count int64
+		for _, x := range entries {
+			if search.Match(r.filter, x) {
+				count++
+				r.sample = x
+			}
+		}
+		if count > 0 {
+			r.add(now, count)
+			if n := o.check(r, now); n != nil {
+				notifications = append(notifications, n)
+			}
+		}
+	}
+	o.locker.Unlock()
+
+	// Templates are rendered out of the lock, so that a slow template does not hold up writing
+	for _, x := range notifications {
+		o.notify(x)
+	}
+}
+
+// States returns the states of the rules of a client, or all rules when clientID is empty
+func (o *Engine) States(clientID string) []*logs.AlertState {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	now := time.Now()
+	r := make([]*logs.AlertState, 0, len(o.rules))
+	for _, x := range o.rules {
+		if clientID != "" && x.ClientID != clientID {
+			continue
+		}
+		x.prune(now)
+		state := &logs.AlertState{
+			RuleID:   x.ID,
+			ClientID: x.ClientID,
+			State:    x.state,
+			Count:    x.count,
+		}
+		if !x.firedOn.IsZero() {
+			state.FiredOnUtc = x.firedOn.UnixMilli()
+		}
+		if !x.resolvedOn.IsZero() {
+			state.ResolvedOnUtc = x.resolvedOn.UnixMilli()
+		}
+		r = append(r, state)
+	}
+
+	return r
+}
+
+// check moves a rule between firing and resolved and returns the notification of a change, must be called with the lock held
+func (o *Engine) check(r *rule, now time.Time) *notification {
+	r.prune(now)
+
+	if r.count > r.Threshold {
+		if r.state != StateFiring && now.Sub(r.firedOn) >= time.Second*time.Duration(r.Cooldown) {
+			r.state = StateFiring
+			r.firedOn = now
+			return r.notification(now)
+		}
+	} else if r.state == StateFiring {
+		r.state = StateResolved
+		r.resolvedOn = now
+		return r.notification(now)
+	}
+	return nil
+}
+
+// notify renders the body of a notification and posts it, must be called without the lock
+func (o *Engine) notify(n *notification) {
+	body, err := n.body()
+	if xerr.LogError(err) {
+		return
+	}
+
+	go o.post(n.webhookURL, body)
+}
+
+func (o *Engine) post(webhookURL string, body []byte) {
+	rs, err := o.httpClient.Post(webhookURL, "application/json", bytes.NewReader(body))
+	if xerr.LogError(err) {
+		return
+	}
+	defer rs.Body.Close()
+	io.Copy(io.Discard, rs.Body)
+
+	if rs.StatusCode >= 300 {
+		xlog.Warnf("Webhook '%s' responded %d", webhookURL, rs.StatusCode)
+	}
+}
+
+// ************************************************************************************************
+
+// notification is a state change of a rule, what is needed to render and post it is copied from the rule
+type notification struct {
+	event      *Event
+	template   *template.Template
+	webhookURL string
+}
+
+func (o *notification) body() ([]byte, error) {
+	if o.template == nil {
+		return json.Marshal(o.event)
+	}
+	buffer := new(bytes.Buffer)
+	err := o.template.Execute(buffer, o.event)
+	return buffer.Bytes(), err
+}
+
+type bucket struct {
+	second int64
+	count  int64
+}
+
+type rule struct {
+	*logs.AlertRule
+	filter   search.Node
+	template *template.Template
+
+	// Matches per second within the window, oldest first
+	buckets    []bucket
+	count      int64
+	state      string
+	firedOn    time.Time
+	resolvedOn time.Time
+	sample     *logs.LogEntry
+}
+
+func newRule(in *logs.AlertRule) (*rule, error) {
+	r := &rule{AlertRule: proto.Clone(in).(*logs.AlertRule)}
+
+	var err error
+	r.filter, err = search.Parse(in.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	if in.BodyTemplate != "" {
+		r.template, err = template.New(in.ID).Funcs(_templateFuncs).Parse(in.BodyTemplate)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+	}
+
+	return r, nil
+}
+
+func (o *rule) notification(now time.Time) *notification {
+	return &notification{
+		event: &Event{
+			RuleID:        o.ID,
+			RuleName:      o.Name,
+			ClientID:      o.ClientID,
+			State:         o.state,
+			Count:         o.count,
+			Threshold:     o.Threshold,
+			Window:        o.Window,
+			OccurredOnUtc: now.UnixMilli(),
+			Sample:        o.sample,
+		},
+		template:   o.template,
+		webhookURL: o.WebhookURL,
+	}
+}
+
+func (o *rule) add(now time.Time, count int64) {
+	second := now.Unix()
+	if n := len(o.buckets); n > 0 && o.buckets[n-1].second == second {
+		o.buckets[n-1].count += count
+	} else {
+		o.buckets = append(o.buckets, bucket{second: second, count: count})
+	}
+	o.count += count
+}
+
+// prune drops buckets out of the window
+func (o *rule) prune(now time.Time) {
+	start := now.Unix() - o.Window
+	i := 0
+	for ; i < len(o.buckets) && o.buckets[i].second <= start; i++ {
+		o.count -= o.buckets[i].count
+	}
+	if i > 0 {
+		o.buckets = append(o.buckets[:0], o.buckets[i:]...)
+	}
+}
@@ -0,0 +1,174 @@
+package alert
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DreamvatLab/logs"
+	"google.golang.org/protobuf/proto"
+)
+
+func mustRule(t *testing.T, in *logs.AlertRule) *rule {
+	t.Helper()
+	r, err := newRule(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+func TestRuleWindow(t *testing.T) {
+	r := mustRule(t, &logs.AlertRule{ID: "r1", Window: 10})
+	start := time.Unix(1000, 0)
+
+	r.add(start, 1)
+	r.add(start.Add(time.Millisecond*500), 2) // same second, same bucket
+	r.add(start.Add(time.Second*5), 3)
+	if len(r.buckets) != 2 || r.count != 6 {
+		t.Fatalf("buckets = %v, count = %d, want 2 buckets of 6", r.buckets, r.count)
+	}
+
+	cases := []struct {
+		after time.Duration
+		want  int64
+	}{
+		{time.Second * 9, 6},
+		{time.Second * 10, 3}, // the first second leaves the window
+		{time.Second * 14, 3},
+		{time.Second * 15, 0},
+	}
+	for _, x := range cases {
+		r.prune(start.Add(x.after))
+		if r.count != x.want {
+			t.Errorf("count after %v = %d, want %d", x.after, r.count, x.want)
+		}
+	}
+	if len(r.buckets) != 0 {
+		t.Errorf("buckets = %v, want none", r.buckets)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	engine := NewEngine(nil)
+	r := mustRule(t, &logs.AlertRule{ID: "r1", Window: 10, Threshold: 2, Cooldown: 60})
+	now := time.Unix(1000, 0)
+
+	r.add(now, 2)
+	if n := engine.check(r, now); n != nil || r.state != "" {
+		t.Fatalf("check at the threshold = %v, state %q, want nothing", n, r.state)
+	}
+
+	r.add(now, 1)
+	n := engine.check(r, now)
+	if n == nil || n.event.State != StateFiring || n.event.Count != 3 || r.state != StateFiring {
+		t.Fatalf("check over the threshold = %v, state %q, want firing", n, r.state)
+	}
+	r.add(now.Add(time.Second), 5)
+	if n = engine.check(r, now.Add(time.Second)); n != nil {
+		t.Errorf("check of a firing rule = %v, want nothing", n.event)
+	}
+
+	// Resolves when the window has passed
+	now = now.Add(time.Second * 11)
+	n = engine.check(r, now)
+	if n == nil || n.event.State != StateResolved || r.state != StateResolved || !r.resolvedOn.Equal(now) {
+		t.Fatalf("check after the window = %v, state %q, want resolved", n, r.state)
+	}
+
+	// Does not fire again within the cooldown
+	r.add(now, 3)
+	if n = engine.check(r, now); n != nil || r.state != StateResolved {
+		t.Errorf("check within the cooldown = %v, state %q, want nothing", n, r.state)
+	}
+	now = time.Unix(1060, 0)
+	r.add(now, 3)
+	if n = engine.check(r, now); n == nil || n.event.State != StateFiring {
+		t.Errorf("check after the cooldown = %v, want firing", n)
+	}
+}
+
+func TestReload(t *testing.T) {
+	rules := []*logs.AlertRule{{ID: "r1", ClientID: "c1", Filter: "level>=error", Window: 60, WebhookURL: "http://localhost/a"}}
+	engine := NewEngine(func() ([]*logs.AlertRule, error) { return rules, nil })
+	engine.Reload()
+
+	now := time.Now()
+	r := engine.rules["r1"]
+	r.add(now, 5)
+	if n := engine.check(r, now); n == nil || n.event.State != StateFiring {
+		t.Fatalf("check = %v, want firing", n)
+	}
+
+	cases := []struct {
+		name      string
+		change    func(x *logs.AlertRule)
+		wantState string
+		wantCount int64
+	}{
+		{"Webhook", func(x *logs.AlertRule) { x.WebhookURL = "http://localhost/b" }, StateFiring, 5},
+		{"Template", func(x *logs.AlertRule) { x.Name = "errors"; x.BodyTemplate = "{{.State}}" }, StateFiring, 5},
+		{"Filter", func(x *logs.AlertRule) { x.Filter = "level>=fatal" }, "", 0},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			changed := proto.Clone(rules[0]).(*logs.AlertRule)
+			x.change(changed)
+			rules = []*logs.AlertRule{changed}
+			engine.Reload()
+
+			r := engine.rules["r1"]
+			if r.AlertRule.WebhookURL != changed.WebhookURL || r.state != x.wantState || r.count != x.wantCount {
+				t.Fatalf("rule = %s %q %d, want %s %q %d", r.WebhookURL, r.state, r.count, changed.WebhookURL, x.wantState, x.wantCount)
+			}
+			// An edited rule must not resolve by itself
+			if n := engine.check(r, now); n != nil {
+				t.Errorf("check after reload = %v, want nothing", n.event)
+			}
+		})
+	}
+
+	rules = nil
+	engine.Reload()
+	if len(engine.rules) != 0 || len(engine.byClient) != 0 {
+		t.Errorf("rules = %v, want none after removal", engine.rules)
+	}
+}
+
+func TestObserve(t *testing.T) {
+	bodies := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies <- string(body)
+	}))
+	defer server.Close()
+
+	engine := NewEngine(func() ([]*logs.AlertRule, error) {
+		return []*logs.AlertRule{{
+			ID: "r1", ClientID: "c1", Name: "errors", Filter: "level>=error", Window: 60, Threshold: 1,
+			WebhookURL: server.URL, BodyTemplate: `{{.RuleName}} {{.State}} {{.Count}} {{json .Sample.Message}}`,
+		}}, nil
+	})
+	engine.Reload()
+
+	engine.Observe("c2", []*logs.LogEntry{{Level: logs.LogLevel_Error}, {Level: logs.LogLevel_Error}})
+	engine.Observe("c1", []*logs.LogEntry{{Level: logs.LogLevel_Error, Message: "one"}, {Level: logs.LogLevel_Warning, Message: "skipped"}})
+	if states := engine.States("c1"); len(states) != 1 || states[0].Count != 1 || states[0].State != "" {
+		t.Fatalf("states = %v, want a count of 1", states)
+	}
+
+	engine.Observe("c1", []*logs.LogEntry{{Level: logs.LogLevel_Fatal, Message: "two"}})
+	select {
+	case body := <-bodies:
+		if body != `errors firing 2 "two"` {
+			t.Errorf("body = %s, want the rendered template", body)
+		}
+	case <-time.After(time.Second * 5):
+		t.Fatal("the webhook was not called")
+	}
+	if states := engine.States(""); len(states) != 1 || states[0].State != StateFiring || states[0].FiredOnUtc == 0 {
+		t.Errorf("states = %v, want firing", states)
+	}
+}
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"strings"
+
 	"github.com/DreamvatLab/go/xconfig"
 	"github.com/DreamvatLab/go/xerr"
 	"github.com/DreamvatLab/go/xlog"
@@ -24,3 +26,17 @@ func Init() {
 
 	WebConfigProvider = xconfig.NewJsonConfigProvider("web.json")
 }
+
+// IsClientDatabase reports whether a database is named by the partitioning of a client, LOG_<ID> or LOG_<ID>_<digits>.
+// Databases of clients whose ids start with the id, e.g. LOG_<ID>x, are not.
+func IsClientDatabase(clientID, dbName string) bool {
+	suffix, found := strings.CutPrefix(dbName, LOG_DB_PREFIX+clientID)
+	if !found {
+		return false
+	}
+	if suffix == "" {
+		return true
+	}
+	suffix, found = strings.CutPrefix(suffix, "_")
+	return found && suffix != "" && strings.Trim(suffix, "0123456789") == ""
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+)
+
+// Cursor is a keyset position in the list of entries ordered by (CreatedOnUtc, ID) descending
+type Cursor struct {
+	CreatedOnUtc int64
+	ID           string
+	// Backward cursors page to newer entries
+	Backward bool
+}
+
+func ParseCursor(s string) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, xerr.New("invalid cursor")
+	}
+
+	parts := strings.SplitN(string(data), "|", 3)
+	if len(parts) != 3 || (parts[0] != "n" && parts[0] != "p") {
+		return nil, xerr.New("invalid cursor")
+	}
+
+	createdOnUtc, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return nil, xerr.New("invalid cursor")
+	}
+
+	return &Cursor{
+		CreatedOnUtc: createdOnUtc,
+		ID:           parts[2],
+		Backward:     parts[0] == "p",
+	}, nil
+}
+
+func (o *Cursor) String() string {
+	dir := "n"
+	if o.Backward {
+		dir = "p"
+	}
+	return base64.RawURLEncoding.EncodeToString([]byte(dir + "|" + strconv.FormatInt(o.CreatedOnUtc, 10) + "|" + o.ID))
+}
+
+// PageCursors returns cursors of the pages next to a result list.
+// In cursor mode a full page is assumed to have more entries after it.
+func PageCursors(query *logs.LogEntriesQuery, list []*logs.LogEntry, totalCount int64) (next, prev string) {
+	if len(list) == 0 {
+		return "", ""
+	}
+
+	var hasNext, hasPrev bool
+	if query.Cursor == "" {
+		hasNext = int64(query.PageIndex)*int64(query.PageSize) < totalCount
+		hasPrev = query.PageIndex > 1
+	} else if cursor, err := ParseCursor(query.Cursor); err == nil && cursor.Backward {
+		hasNext = true
+		hasPrev = len(list) >= int(query.PageSize)
+	} else {
+		hasNext = len(list) >= int(query.PageSize)
+		hasPrev = true
+	}
+
+	if hasNext {
+		last := list[len(list)-1]
+		next = (&Cursor{CreatedOnUtc: last.CreatedOnUtc, ID: last.ID}).String()
+	}
+	if hasPrev {
+		first := list[0]
+		prev = (&Cursor{CreatedOnUtc: first.CreatedOnUtc, ID: first.ID, Backward: true}).String()
+	}
+	return next, prev
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/DreamvatLab/go/xconfig"
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xlog"
+)
+
+// NewTLSConfig reads the TLS section of a config file, returns nil when TLS.CertFile is empty.
+// nextProtos are the ALPN protocols of the listener, e.g. "h2" for gRPC.
+//
+//	"TLS": {
+//		"CertFile": "server.crt",
+//		"KeyFile": "server.key",
+//		"ClientCAFile": "ca.crt",    // Optional, enables mTLS, CN of client certificates are client ids
+//		"RequireClientCert": false,  // Reject connections without a client certificate
+//		"ReloadInterval": 10         // Seconds between checks of file changes
+//	}
+func NewTLSConfig(cp xconfig.IConfigProvider, nextProtos ...string) (*tls.Config, error) {
+	certFile := cp.GetString("TLS.CertFile")
+	if certFile == "" {
+		return nil, nil
+	}
+
+	reloader := &certReloader{
+		certFile:          certFile,
+		keyFile:           cp.GetString("TLS.KeyFile"),
+		clientCAFile:      cp.GetString("TLS.ClientCAFile"),
+		requireClientCert: cp.GetBool("TLS.RequireClientCert"),
+		nextProtos:        nextProtos,
+	}
+	if reloader.requireClientCert && reloader.clientCAFile == "" {
+		return nil, xerr.New("TLS.RequireClientCert needs TLS.ClientCAFile")
+	}
+
+	err := reloader.load()
+	if err != nil {
+		return nil, err
+	}
+
+	reloadInterval := cp.GetInt("TLS.ReloadInterval")
+	if reloadInterval <= 0 {
+		reloadInterval = 10
+	}
+	go reloader.watch(time.Second * time.Duration(reloadInterval))
+
+	return &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		GetConfigForClient: reloader.getConfigForClient,
+	}, nil
+}
+
+// ClientCertID returns the CN of the verified client certificate, empty if there is none
+func ClientCertID(state *tls.ConnectionState) string {
+	if state == nil || len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return ""
+	}
+	return state.VerifiedChains[0][0].Subject.CommonName
+}
+
+// ************************************************************************************************
+
+type certReloader struct {
+	certFile          string
+	keyFile           string
+	clientCAFile      string
+	requireClientCert bool
+	nextProtos        []string
+
+	locker   sync.RWMutex
+	config   *tls.Config
+	modTimes []time.Time
+}
+
+func (o *certReloader) files() []string {
+	if o.clientCAFile == "" {
+		return []string{o.certFile, o.keyFile}
+	}
+	return []string{o.certFile, o.keyFile, o.clientCAFile}
+}
+
+func (o *certReloader) load() error {
+	modTimes := make([]time.Time, 0, 3)
+	for _, x := range o.files() {
+		info, err := os.Stat(x)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+		modTimes = append(modTimes, info.ModTime())
+	}
+
+	cert, err := tls.LoadX509KeyPair(o.certFile, o.keyFile)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	config := &tls.Config{
+		MinVersion:   tls.VersionTLS12,
+		Certificates: []tls.Certificate{cert},
+		NextProtos:   o.nextProtos,
+	}
+	if o.clientCAFile != "" {
+		pemBytes, err := os.ReadFile(o.clientCAFile)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+		config.ClientCAs = x509.NewCertPool()
+		if !config.ClientCAs.AppendCertsFromPEM(pemBytes) {
+			return xerr.Errorf("no certificate found in '%s'", o.clientCAFile)
+		}
+		config.ClientAuth = tls.VerifyClientCertIfGiven
+		if o.requireClientCert {
+			config.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	o.config = config
+	o.modTimes = modTimes
+
+	return nil
+}
+
+func (o *certReloader) changed() bool {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+
+	for i, x := range o.files() {
+		info, err := os.Stat(x)
+		// A missing file is being replaced, check again later
+		if err == nil && !info.ModTime().Equal(o.modTimes[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (o *certReloader) watch(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if !o.changed() {
+			continue
+		}
+
+		// The old certificate is kept if the new files are broken, e.g. half written
+		err := o.load()
+		if xerr.LogError(err) {
+			continue
+		}
+		xlog.Infof("Certificate '%s' reloaded", o.certFile)
+	}
+}
+
+func (o *certReloader) getConfigForClient(*tls.ClientHelloInfo) (*tls.Config, error) {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+	return o.config, nil
+}
@@ -1,7 +1,10 @@
 package ch
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,6 +17,7 @@ import (
 	"github.com/DreamvatLab/go/xtask"
 	"github.com/DreamvatLab/logs"
 	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
 	"github.com/jmoiron/sqlx"
 )
 
@@ -25,6 +29,8 @@ var (
 		},
 	}
 	_dbLocker = new(sync.RWMutex)
+
+	_likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 )
 
 func Init() {
@@ -45,6 +51,28 @@ func Init() {
 
 type ClickHouseDAL struct{}
 
+// insertArgs returns values in the column order of _SQL_INSERT
+func insertArgs(logEntry *logs.LogEntry) []any {
+	labels := logEntry.Labels
+	if labels == nil {
+		labels = make(map[string]string)
+	}
+
+	return []any{
+		logEntry.ID,
+		logEntry.TraceNo,
+		logEntry.User,
+		logEntry.Message,
+		logEntry.Error,
+		logEntry.StackTrace,
+		logEntry.Payload,
+		int32(logEntry.Level),
+		logEntry.Flags,
+		logEntry.CreatedOnUtc,
+		labels,
+	}
+}
+
 func ensureDBTableExsits(err error, dbName, tableName string) error {
 	var sql string
 	if err != nil {
@@ -72,6 +100,12 @@ func ensureDBTableExsits(err error, dbName, tableName string) error {
 				if err != nil {
 					return xerr.WithStack(err)
 				}
+			} else if innerErr.Code == 16 { // 16: Column not exists, table created by an older version
+				sql = fmt.Sprintf(_SQL_ADD_LABELS, dbName, tableName)
+				_, err = _db.Exec(sql)
+				if err != nil {
+					return xerr.WithStack(err)
+				}
 			}
 		} else {
 			return xerr.WithStack(err)
@@ -88,18 +122,7 @@ func (o *ClickHouseDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.
 
 	sqlStr := fmt.Sprintf(_SQL_INSERT, dbName, tableName)
 	_dbLocker.RLock()
-	_, err := _db.Exec(sqlStr,
-		logEntry.ID,
-		logEntry.TraceNo,
-		logEntry.User,
-		logEntry.Message,
-		logEntry.Error,
-		logEntry.StackTrace,
-		logEntry.Payload,
-		int32(logEntry.Level),
-		logEntry.Flags,
-		logEntry.CreatedOnUtc,
-	)
+	_, err := _db.Exec(sqlStr, insertArgs(logEntry)...)
 	_dbLocker.RUnlock()
 	if err != nil {
 		err = ensureDBTableExsits(err, dbName, tableName) // Ensure db and table are exist
@@ -108,18 +131,7 @@ func (o *ClickHouseDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.
 		}
 
 		// Retry
-		_, err = _db.Exec(sqlStr,
-			logEntry.ID,
-			logEntry.TraceNo,
-			logEntry.User,
-			logEntry.Message,
-			logEntry.Error,
-			logEntry.StackTrace,
-			logEntry.Payload,
-			int32(logEntry.Level),
-			logEntry.Flags,
-			logEntry.CreatedOnUtc,
-		)
+		_, err = _db.Exec(sqlStr, insertArgs(logEntry)...)
 
 		if err != nil {
 			return xerr.WithStack(err)
@@ -128,6 +140,55 @@ func (o *ClickHouseDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.
 
 	return nil
 }
+func (o *ClickHouseDAL) InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error {
+	if len(logEntries) == 0 {
+		return nil
+	}
+
+	sqlStr := fmt.Sprintf(_SQL_INSERT, dbName, tableName)
+	_dbLocker.RLock()
+	err := insertBatch(sqlStr, logEntries)
+	_dbLocker.RUnlock()
+	if err != nil {
+		err = ensureDBTableExsits(err, dbName, tableName) // Ensure db and table are exist
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+
+		// Retry
+		err = insertBatch(sqlStr, logEntries)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+// insertBatch sends entries as one native batch, the driver collects rows between Prepare and Commit
+func insertBatch(sqlStr string, logEntries []*logs.LogEntry) error {
+	tx, err := _db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(sqlStr)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, x := range logEntries {
+		_, err = stmt.Exec(insertArgs(x)...)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
 func (o *ClickHouseDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error) {
 	r := new(logs.LogEntry)
 
@@ -138,42 +199,28 @@ func (o *ClickHouseDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry,
 	_dbLocker.RUnlock()
 
 	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		return nil, xerr.WithStack(err)
 	}
 
 	return r, nil
 }
-func (o *ClickHouseDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error) {
-	if query == nil || query.DBName == "" || query.TableName == "" {
-		return nil, 0, xerr.New("query, DBName and TableName cannot be nil or empty")
-	}
-
-	if query.PageIndex < 1 {
-		return nil, 0, xerr.New("page index must be greater than 0")
-	}
-
-	if query.PageSize < 1 {
-		return nil, 0, xerr.New("page size must be greater than 0")
-	}
-
-	where := _wherePool.Get().(*strings.Builder)
-	defer func() {
-		where.Reset()
-		_wherePool.Put(where)
-	}()
 
+// buildWhere writes the filters of a query as " AND ..." conditions and returns the arguments of their placeholders
+func buildWhere(where *strings.Builder, query *logs.LogEntriesQuery) ([]any, error) {
 	if query.StartTime != "" {
 		t, err := time.ParseInLocation(time.RFC3339, query.StartTime, time.UTC)
 		if err != nil {
-			return nil, 0, xerr.WithStack(err)
+			return nil, xerr.WithStack(err)
 		}
 		where.WriteString(" AND `CreatedOnUtc` >= " + xconv.ToString(t.UnixMilli()))
 	}
 	if query.EndTime != "" {
 		t, err := time.ParseInLocation(time.RFC3339, query.EndTime, time.UTC)
-		t = t.Add(time.Hour * 24)
 		if err != nil {
-			return nil, 0, xerr.WithStack(err)
+			return nil, xerr.WithStack(err)
 		}
 		where.WriteString(" AND `CreatedOnUtc` <= " + xconv.ToString(t.UnixMilli()))
 	}
@@ -181,30 +228,86 @@ func (o *ClickHouseDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogE
 		where.WriteString(" AND `Level` = " + strconv.FormatInt(int64(query.Level), 10))
 	}
 
-	// TODO: Prevent sql injection
-	if query.User != "" {
-		likeSql := " AND `User` LIKE '"
-		if query.Flags&1 == 1 { // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
-			likeSql += "%"
+	var r []any
+	fuzzy := query.Flags&1 == 1 // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
+	for _, x := range [][2]string{{"User", query.User}, {"TraceNo", query.TraceNo}, {"Message", query.Message}, {"Error", query.Error}, {"StackTrace", query.StackTrace}} {
+		if x[1] == "" {
+			continue
 		}
-		likeSql += query.User + "%'"
-		where.WriteString(likeSql)
+		pattern := _likeReplacer.Replace(x[1]) + "%"
+		if fuzzy {
+			pattern = "%" + pattern
+		}
+		where.WriteString(" AND `" + x[0] + "` ILIKE ?")
+		r = append(r, pattern)
+	}
+	for _, x := range query.Labels {
+		if x.Prefix {
+			where.WriteString(" AND startsWith(`Labels`[?], ?)")
+		} else {
+			where.WriteString(" AND `Labels`[?] = ?")
+		}
+		r = append(r, x.Key, x.Value)
+	}
+
+	if query.Query != "" {
+		node, err := search.Parse(query.Query)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			condition, args := search.ToSQL(node, search.DialectClickHouse)
+			where.WriteString(" AND " + condition)
+			r = append(r, args...)
+		}
+	}
+
+	return r, nil
+}
+
+func (o *ClickHouseDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, 0, xerr.New("query, DBName and TableName cannot be nil or empty")
 	}
-	if query.TraceNo != "" {
-		likeSql := " AND `TraceNo` LIKE '"
-		if query.Flags&1 == 1 { // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
-			likeSql += "%"
+
+	var cursor *core.Cursor
+	if query.Cursor != "" {
+		var err error
+		cursor, err = core.ParseCursor(query.Cursor)
+		if err != nil {
+			return nil, 0, err
 		}
-		likeSql += query.TraceNo + "%'"
-		where.WriteString(likeSql)
+	} else if query.PageIndex < 1 {
+		return nil, 0, xerr.New("page index must be greater than 0")
 	}
-	if query.Message != "" {
-		likeSql := " AND `Message` LIKE '"
-		if query.Flags&1 == 1 { // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
-			likeSql += "%"
+
+	if query.PageSize < 1 {
+		return nil, 0, xerr.New("page size must be greater than 0")
+	}
+
+	where := _wherePool.Get().(*strings.Builder)
+	defer func() {
+		where.Reset()
+		_wherePool.Put(where)
+	}()
+
+	args, err := buildWhere(where, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Keyset pagination, the cursor condition only applies to the list
+	listWhere := where.String()
+	listArgs := slices.Clip(args)
+	ord := "`CreatedOnUtc` DESC, `ID` DESC"
+	if cursor != nil {
+		op := "<"
+		if cursor.Backward {
+			op = ">"
+			ord = "`CreatedOnUtc` ASC, `ID` ASC"
 		}
-		likeSql += query.Message + "%'"
-		where.WriteString(likeSql)
+		listWhere += " AND (`CreatedOnUtc` " + op + " ? OR (`CreatedOnUtc` = ? AND `ID` " + op + " ?))"
+		listArgs = append(listArgs, cursor.CreatedOnUtc, cursor.CreatedOnUtc, cursor.ID)
 	}
 
 	_dbLocker.RLock()
@@ -215,26 +318,32 @@ func (o *ClickHouseDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogE
 		func() (interface{}, error) {
 			var totalCount int64
 			countSql := fmt.Sprintf("SELECT COUNT(0) FROM `%s`.`%s` WHERE 0 = 0 %s", query.DBName, query.TableName, where.String())
-			err := _db.Get(&totalCount, countSql)
+			err := _db.Get(&totalCount, countSql, args...)
 			if err != nil {
 				return nil, xerr.WithStack(err)
 			}
 			return totalCount, nil
 		},
 		func() (interface{}, error) {
-			start := (query.PageIndex - 1) * query.PageSize
-			end := start + query.PageSize
+			// LIMIT offset, count
+			limit := fmt.Sprintf("%d, %d", (query.PageIndex-1)*query.PageSize, query.PageSize)
+			if cursor != nil {
+				limit = strconv.FormatInt(int64(query.PageSize), 10)
+			}
 
-			listSql := fmt.Sprintf("SELECT * FROM `%s`.`%s` WHERE 0 = 0 %s ORDER BY `CreatedOnUtc` DESC LIMIT %d, %d", query.DBName, query.TableName, where.String(), start, end)
+			listSql := fmt.Sprintf("SELECT * FROM `%s`.`%s` WHERE 0 = 0 %s ORDER BY %s LIMIT %s", query.DBName, query.TableName, listWhere, ord, limit)
 
 			var r []*logs.LogEntry
-			err := _db.Select(&r, listSql)
+			err := _db.Select(&r, listSql, listArgs...)
 			if err != nil {
 				return nil, xerr.WithStack(err)
 			}
 			if r == nil {
 				r = make([]*logs.LogEntry, 0)
 			}
+			if cursor != nil && cursor.Backward {
+				slices.Reverse(r)
+			}
 			return r, nil
 		},
 	)
@@ -245,7 +354,7 @@ func (o *ClickHouseDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogE
 	}
 
 	// Merge errors using xerr.JointErrors
-	err := xerr.JointErrors(results[0].Error, results[1].Error)
+	err = xerr.JointErrors(results[0].Error, results[1].Error)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -254,14 +363,57 @@ func (o *ClickHouseDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogE
 	list := results[1].Result.([]*logs.LogEntry)
 	return list, totalCount, nil
 }
+func (o *ClickHouseDAL) GetLogHistogram(query *logs.LogEntriesQuery, interval int64) ([]*logs.LogHistogramBucket, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, xerr.New("query, DBName and TableName cannot be nil or empty")
+	}
+
+	if interval < 1 {
+		return nil, xerr.New("interval must be greater than 0")
+	}
+
+	where := _wherePool.Get().(*strings.Builder)
+	defer func() {
+		where.Reset()
+		_wherePool.Put(where)
+	}()
+
+	args, err := buildWhere(where, query)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlStr := fmt.Sprintf(_SQL_HISTOGRAM, interval, query.DBName, query.TableName, where.String())
+
+	_dbLocker.RLock()
+	defer _dbLocker.RUnlock()
+
+	var r []*logs.LogHistogramBucket
+	err = _db.Select(&r, sqlStr, args...)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+	if r == nil {
+		r = make([]*logs.LogHistogramBucket, 0)
+	}
+
+	return r, nil
+}
+
 func (o *ClickHouseDAL) GetDatabases(clientID string) ([]string, error) {
-	var r []string
-	keyword := "LOG\\_" + clientID + "%"
-	err := _db.Select(&r, _SQL_SELECT_DATABASES, keyword)
+	var databases []string
+	keyword := _likeReplacer.Replace(core.LOG_DB_PREFIX+clientID) + "%"
+	err := _db.Select(&databases, _SQL_SELECT_DATABASES, keyword)
 	if err != nil {
 		return nil, xerr.WithStack(err)
 	}
 
+	r := make([]string, 0, len(databases))
+	for _, x := range databases {
+		if core.IsClientDatabase(clientID, x) {
+			r = append(r, x)
+		}
+	}
 	return r, nil
 }
 func (o *ClickHouseDAL) GetTables(database string) ([]string, error) {
@@ -273,3 +425,27 @@ func (o *ClickHouseDAL) GetTables(database string) ([]string, error) {
 
 	return r, nil
 }
+
+func (o *ClickHouseDAL) DropDatabase(database string) error {
+	_dbLocker.Lock()
+	defer _dbLocker.Unlock()
+
+	_, err := _db.Exec(fmt.Sprintf(_SQL_DROP_DB, database))
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
+
+func (o *ClickHouseDAL) DropTable(database, table string) error {
+	_dbLocker.Lock()
+	defer _dbLocker.Unlock()
+
+	_, err := _db.Exec(fmt.Sprintf(_SQL_DROP_TABLE, database, table))
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
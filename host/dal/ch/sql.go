@@ -4,6 +4,9 @@ const (
 	// _SQL_USE_DB = "use `%s`;" // ClickHouse does not need this
 
 	_SQL_CREATE_DB = "CREATE DATABASE IF NOT EXISTS `%s`;"
+	_SQL_DROP_DB   = "DROP DATABASE IF EXISTS `%s`;"
+
+	_SQL_DROP_TABLE = "DROP TABLE IF EXISTS `%s`.`%s`;"
 
 	_SQL_CREATE_TABLE = `CREATE TABLE IF NOT EXISTS ` + "`%s`" + `.` + "`%s`" + `(
 	  ` + "`ID`" + ` String,
@@ -15,15 +18,21 @@ const (
 	  ` + "`Payload`" + ` String,
 	  ` + "`Level`" + ` Int32,
 	  ` + "`Flags`" + ` Int64,
-	  ` + "`CreatedOnUtc`" + ` Int64
+	  ` + "`CreatedOnUtc`" + ` Int64,
+	  ` + "`Labels`" + ` Map(String, String)
 	) ENGINE = MergeTree
 	  PRIMARY KEY (` + "`ID`" + `)
 	  ORDER BY (` + "`ID`" + `, ` + "`CreatedOnUtc`" + `);`
 
-	_SQL_INSERT = "INSERT INTO `%s`.`%s` (*) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	_SQL_INSERT = "INSERT INTO `%s`.`%s` (`ID`, `TraceNo`, `User`, `Message`, `Error`, `StackTrace`, `Payload`, `Level`, `Flags`, `CreatedOnUtc`, `Labels`) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+
+	// Tables created before labels were introduced
+	_SQL_ADD_LABELS = "ALTER TABLE `%s`.`%s` ADD COLUMN IF NOT EXISTS `Labels` Map(String, String);"
 
 	_SQL_SELECT_ONE = "SELECT * FROM `%s`.`%s` WHERE ID = ? LIMIT 1"
 
+	_SQL_HISTOGRAM = "SELECT toUnixTimestamp64Milli(toStartOfInterval(fromUnixTimestamp64Milli(`CreatedOnUtc`), INTERVAL %d SECOND)) AS `StartTime`, `Level`, toInt64(count()) AS `Count` FROM `%s`.`%s` WHERE 0 = 0 %s GROUP BY `StartTime`, `Level` ORDER BY `StartTime`, `Level`"
+
 	_SQL_SELECT_DATABASES = "SELECT name FROM system.databases WHERE name LIKE ? ORDER BY name DESC;"
 	_SQL_SELECT_TABLES    = "SELECT name FROM system.tables WHERE database = ? ORDER BY name DESC;"
 )
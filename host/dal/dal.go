@@ -5,29 +5,70 @@ import (
 	"github.com/DreamvatLab/logs"
 	"github.com/DreamvatLab/logs/host/core"
 	"github.com/DreamvatLab/logs/host/dal/ch"
+	"github.com/DreamvatLab/logs/host/dal/memory"
 	"github.com/DreamvatLab/logs/host/dal/mongodb"
 	"github.com/DreamvatLab/logs/host/dal/mysql"
 	"github.com/DreamvatLab/logs/host/dal/redis"
+	"github.com/DreamvatLab/logs/host/dal/sqlite"
 )
 
+// ILogDAL stores entries in tables of databases, every implementation must pass daltest.TestLogDAL
 type ILogDAL interface {
+	// InsertLogEntry and InsertLogEntries create the database and table when missing, IDs are kept as they are
 	InsertLogEntry(dbName, tableName string, logEntry *logs.LogEntry) error
+	InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error
+	// GetLogEntry returns nil when the entry is not found
 	GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error)
+	// GetLogEntries returns a page of the entries of DBName.TableName matching all filters and the count of all of them,
+	// ordered by CreatedOnUtc and ID descending:
+	//   - StartTime and EndTime are RFC3339 and both inclusive
+	//   - Level matches one level, a negative level matches all
+	//   - User, TraceNo, Message, Error and StackTrace match case insensitive prefixes, substrings when Flags&1 is set
+	//   - Labels match values or case sensitive prefixes
+	//   - Query is a search expression, see search.Parse, field:value matches the whole value and '*' any sequence,
+	//     all its text terms, label terms included, are case insensitive, '%' and '_' are literal
+	//
+	// PageSize is required, the page is PageIndex from 1 or, when Cursor is set, the entries after the cursor.
+	// Backward cursors return the newer entries closest to the cursor.
 	GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error)
+	// GetLogHistogram counts entries matching the query per level in buckets of interval seconds since the epoch,
+	// ordered by StartTime and Level
+	GetLogHistogram(query *logs.LogEntriesQuery, interval int64) ([]*logs.LogHistogramBucket, error)
+	// GetDatabases returns the databases of a client in descending order, see core.IsClientDatabase
 	GetDatabases(clientID string) ([]string, error)
+	// GetTables returns the tables of a database in descending order, none when the database does not exist
 	GetTables(database string) ([]string, error)
+	// DropDatabase and DropTable succeed when there is nothing to drop
+	DropDatabase(database string) error
+	DropTable(database, table string) error
 }
 
+// IClientDAL stores clients, their keys and alert rules, every implementation must pass daltest.TestClientDAL
 type IClientDAL interface {
 	InsertClient(*logs.LogClient) error
+	// GetClient returns nil when the client is not found
 	GetClient(id string) (*logs.LogClient, error)
 	UpdateClient(*logs.LogClient) error
+	// DeleteClient deletes the keys of the client too
 	DeleteClient(id string) error
+	// GetClients returns all clients ordered by ID
 	GetClients(in *logs.LogClientsQuery) ([]*logs.LogClient, error)
+	// GetClientKeys returns the hashed API keys of a client
+	GetClientKeys(clientID string) ([]*logs.LogClientKey, error)
+	UpdateClientKeys(clientID string, keys []*logs.LogClientKey) error
+	// GetAlertRules returns all rules ordered by ClientID and ID
+	GetAlertRules() ([]*logs.AlertRule, error)
+	SaveAlertRule(*logs.AlertRule) error
+	DeleteAlertRule(id string) error
 }
 
 func NewLogDAL() ILogDAL {
 	provider := core.ServiceConfigProvider.GetStringDefault("DataAccess.Provider", "mongodb")
+	return NewLogDALFor(provider)
+}
+
+// NewLogDALFor connects to the store of a provider by its connection string in service.json
+func NewLogDALFor(provider string) ILogDAL {
 	if provider == "clickhouse" {
 		ch.Init()
 		r := new(ch.ClickHouseDAL)
@@ -40,14 +81,38 @@ func NewLogDAL() ILogDAL {
 		mysql.Init()
 		r := new(mysql.MySqlDAL)
 		return r
+	} else if provider == "sqlite" {
+		sqlite.Init()
+		r := new(sqlite.SQLiteDAL)
+		return r
+	} else if provider == "memory" {
+		r := new(memory.MemoryDAL)
+		return r
 	}
 
 	xlog.Fatalf("Provider '%s' is not supported", provider)
 	return nil
 }
 
+// NewClientDAL stores clients by DataAccess.ClientProvider, redis by default or the same as DataAccess.Provider when it is sqlite or memory
 func NewClientDAL() IClientDAL {
-	redisDAL := new(redis.RedisDAL)
-	redisDAL.Init(core.ServiceConfigProvider)
-	return redisDAL
+	defaultProvider := "redis"
+	if provider := core.ServiceConfigProvider.GetString("DataAccess.Provider"); provider == "sqlite" || provider == "memory" {
+		defaultProvider = provider
+	}
+	provider := core.ServiceConfigProvider.GetStringDefault("DataAccess.ClientProvider", defaultProvider)
+	if provider == "sqlite" {
+		sqliteDAL := new(sqlite.SQLiteClientDAL)
+		sqliteDAL.Init(core.ServiceConfigProvider)
+		return sqliteDAL
+	} else if provider == "redis" {
+		redisDAL := new(redis.RedisDAL)
+		redisDAL.Init(core.ServiceConfigProvider)
+		return redisDAL
+	} else if provider == "memory" {
+		return new(memory.MemoryClientDAL)
+	}
+
+	xlog.Fatalf("Client provider '%s' is not supported", provider)
+	return nil
 }
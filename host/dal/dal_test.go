@@ -0,0 +1,50 @@
+package dal_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DreamvatLab/go/xconfig"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/dal"
+	"github.com/DreamvatLab/logs/host/dal/daltest"
+	"github.com/DreamvatLab/logs/host/dal/redis"
+)
+
+// providers returns the providers to test against the servers of a service.json, e.g.
+// LOGS_TEST_CONFIG=service.json LOGS_TEST_PROVIDERS=mongodb,clickhouse,mysql,redis go test ./dal
+func providers(t *testing.T) []string {
+	configPath, list := os.Getenv("LOGS_TEST_CONFIG"), os.Getenv("LOGS_TEST_PROVIDERS")
+	if configPath == "" || list == "" {
+		t.Skip("LOGS_TEST_CONFIG and LOGS_TEST_PROVIDERS are not set")
+	}
+	if core.ServiceConfigProvider == nil {
+		core.ServiceConfigProvider = xconfig.NewJsonConfigProvider(configPath)
+	}
+	return strings.Split(list, ",")
+}
+
+func TestLogDALs(t *testing.T) {
+	for _, x := range providers(t) {
+		if x == "redis" {
+			continue // Clients only
+		}
+		t.Run(x, func(t *testing.T) {
+			daltest.TestLogDAL(t, dal.NewLogDALFor(x))
+		})
+	}
+}
+
+func TestClientDALs(t *testing.T) {
+	for _, x := range providers(t) {
+		if x != "redis" {
+			continue
+		}
+		t.Run(x, func(t *testing.T) {
+			redisDAL := new(redis.RedisDAL)
+			redisDAL.Init(core.ServiceConfigProvider)
+			daltest.TestClientDAL(t, redisDAL)
+		})
+	}
+}
@@ -0,0 +1,449 @@
+// Package daltest checks implementations of dal.ILogDAL and dal.IClientDAL against the semantics documented on them.
+// Tests of every implementation call TestLogDAL and TestClientDAL, the data is written under a new client id and removed after.
+package daltest
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/dal"
+)
+
+var (
+	_base = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// newClientID returns an id no other run uses, so suites can run against shared servers
+func newClientID() string {
+	return "t" + strconv.FormatInt(time.Now().UnixNano(), 36)
+}
+
+// at formats the time of seconds after the base for query filters
+func at(seconds int) string {
+	return _base.Add(time.Duration(seconds) * time.Second).Format(time.RFC3339)
+}
+
+func newEntry(id string, seconds int, level logs.LogLevel, user, traceNo, message, errorStr, stackTrace string, labels map[string]string) *logs.LogEntry {
+	return &logs.LogEntry{
+		ID:           id,
+		TraceNo:      traceNo,
+		User:         user,
+		Message:      message,
+		Error:        errorStr,
+		StackTrace:   stackTrace,
+		Payload:      `{"id":"` + id + `"}`,
+		Level:        level,
+		CreatedOnUtc: _base.Add(time.Duration(seconds) * time.Second).UnixMilli(),
+		Labels:       labels,
+	}
+}
+
+// Ordered by CreatedOnUtc ascending, e05 and e06 are at the same time
+func newEntries() []*logs.LogEntry {
+	return []*logs.LogEntry{
+		newEntry("e01", 0, logs.LogLevel_Verbose, "Alice", "T100", "Connection reset by peer", "", "", map[string]string{"env": "Prod"}),
+		newEntry("e02", 60, logs.LogLevel_Debug, "bob", "T101", "cache miss", "", "", map[string]string{"env": "prod-eu"}),
+		newEntry("e03", 120, logs.LogLevel_Infomation, "alice.smith", "X200", "User logged in", "", "", map[string]string{"env": "staging"}),
+		newEntry("e04", 180, logs.LogLevel_Warning, "carol", "X201", "Disk 90% full", "", "", nil),
+		newEntry("e05", 240, logs.LogLevel_Error, "dave", "X202", "Request failed", "Timeout after 30s", "at main.go:12", nil),
+		newEntry("e06", 240, logs.LogLevel_Fatal, "bob_admin", "X203", "Out of memory", "OOM killer", "at runtime.go:1", nil),
+		newEntry("e07", 3600, logs.LogLevel_Infomation, "erin", "X204", "connection RESET again", "", "", map[string]string{"env": "Prod"}),
+	}
+}
+
+func ids(logEntries []*logs.LogEntry) []string {
+	r := make([]string, 0, len(logEntries))
+	for _, x := range logEntries {
+		r = append(r, x.ID)
+	}
+	return r
+}
+
+// TestLogDAL checks filters, ordering, paging, cursors, totals, histograms, naming of databases and tables and drops
+func TestLogDAL(t *testing.T, logDAL dal.ILogDAL) {
+	clientID := newClientID()
+	dbName := core.LOG_DB_PREFIX + clientID + "_2024"
+	tableName := "01"
+	t.Cleanup(func() {
+		for _, x := range []string{dbName, core.LOG_DB_PREFIX + clientID + "_2023", core.LOG_DB_PREFIX + clientID, core.LOG_DB_PREFIX + clientID + "x_2024"} {
+			if err := logDAL.DropDatabase(x); err != nil {
+				t.Errorf("DropDatabase(%s): %v", x, err)
+			}
+		}
+	})
+
+	entries := newEntries()
+	if err := logDAL.InsertLogEntries(dbName, tableName, entries[:6]); err != nil {
+		t.Fatalf("InsertLogEntries: %v", err)
+	}
+	if err := logDAL.InsertLogEntry(dbName, tableName, entries[6]); err != nil {
+		t.Fatalf("InsertLogEntry: %v", err)
+	}
+
+	t.Run("GetLogEntry", func(t *testing.T) {
+		r, err := logDAL.GetLogEntry(&logs.LogEntryQuery{DBName: dbName, TableName: tableName, ID: "e05"})
+		if err != nil {
+			t.Fatalf("GetLogEntry: %v", err)
+		}
+		want := entries[4]
+		if r == nil || r.ID != want.ID || r.User != want.User || r.TraceNo != want.TraceNo || r.Message != want.Message ||
+			r.Error != want.Error || r.StackTrace != want.StackTrace || r.Payload != want.Payload ||
+			r.Level != want.Level || r.CreatedOnUtc != want.CreatedOnUtc {
+			t.Fatalf("GetLogEntry = %v, want %v", r, want)
+		}
+
+		r, err = logDAL.GetLogEntry(&logs.LogEntryQuery{DBName: dbName, TableName: tableName, ID: "e01"})
+		if err != nil || r == nil || r.Labels["env"] != "Prod" {
+			t.Fatalf("GetLogEntry labels = %v, %v, want env Prod", r, err)
+		}
+
+		r, err = logDAL.GetLogEntry(&logs.LogEntryQuery{DBName: dbName, TableName: tableName, ID: "missing"})
+		if err != nil || r != nil {
+			t.Fatalf("GetLogEntry of a missing entry = %v, %v, want nil, nil", r, err)
+		}
+	})
+
+	t.Run("Filters", func(t *testing.T) {
+		cases := []struct {
+			name  string
+			query *logs.LogEntriesQuery
+			want  []string
+		}{
+			{"All", &logs.LogEntriesQuery{Level: -1}, []string{"e07", "e06", "e05", "e04", "e03", "e02", "e01"}},
+			{"TimeInclusive", &logs.LogEntriesQuery{Level: -1, StartTime: at(60), EndTime: at(240)}, []string{"e06", "e05", "e04", "e03", "e02"}},
+			{"StartTime", &logs.LogEntriesQuery{Level: -1, StartTime: at(241)}, []string{"e07"}},
+			{"EndTime", &logs.LogEntriesQuery{Level: -1, EndTime: at(59)}, []string{"e01"}},
+			{"Level", &logs.LogEntriesQuery{Level: logs.LogLevel_Infomation}, []string{"e07", "e03"}},
+			{"LevelVerbose", &logs.LogEntriesQuery{Level: logs.LogLevel_Verbose}, []string{"e01"}},
+			{"UserPrefix", &logs.LogEntriesQuery{Level: -1, User: "ALICE"}, []string{"e03", "e01"}},
+			{"UserNotSubstring", &logs.LogEntriesQuery{Level: -1, User: "ob"}, []string{}},
+			{"UserSubstring", &logs.LogEntriesQuery{Level: -1, User: "ob", Flags: 1}, []string{"e06", "e02"}},
+			{"UserEscaped", &logs.LogEntriesQuery{Level: -1, User: "b_b"}, []string{}},
+			{"TraceNo", &logs.LogEntriesQuery{Level: -1, TraceNo: "t10"}, []string{"e02", "e01"}},
+			{"MessagePrefix", &logs.LogEntriesQuery{Level: -1, Message: "connection"}, []string{"e07", "e01"}},
+			{"MessageShort", &logs.LogEntriesQuery{Level: -1, Message: "ca"}, []string{"e02"}},
+			{"MessageNotSubstring", &logs.LogEntriesQuery{Level: -1, Message: "reset"}, []string{}},
+			{"MessageSubstring", &logs.LogEntriesQuery{Level: -1, Message: "reset", Flags: 1}, []string{"e07", "e01"}},
+			{"MessageEscaped", &logs.LogEntriesQuery{Level: -1, Message: "90%", Flags: 1}, []string{"e04"}},
+			{"MessageEscapedNone", &logs.LogEntriesQuery{Level: -1, Message: "disk 9%f"}, []string{}},
+			{"Error", &logs.LogEntriesQuery{Level: -1, Error: "timeout"}, []string{"e05"}},
+			{"ErrorSubstring", &logs.LogEntriesQuery{Level: -1, Error: "KILLER", Flags: 1}, []string{"e06"}},
+			{"StackTrace", &logs.LogEntriesQuery{Level: -1, StackTrace: "at "}, []string{"e06", "e05"}},
+			{"LabelValue", &logs.LogEntriesQuery{Level: -1, Labels: []*logs.LabelFilter{{Key: "env", Value: "Prod"}}}, []string{"e07", "e01"}},
+			{"LabelValueCase", &logs.LogEntriesQuery{Level: -1, Labels: []*logs.LabelFilter{{Key: "env", Value: "prod"}}}, []string{}},
+			{"LabelPrefix", &logs.LogEntriesQuery{Level: -1, Labels: []*logs.LabelFilter{{Key: "env", Value: "prod", Prefix: true}}}, []string{"e02"}},
+			{"LabelMissing", &logs.LogEntriesQuery{Level: -1, Labels: []*logs.LabelFilter{{Key: "region", Value: "eu"}}}, []string{}},
+			{"Query", &logs.LogEntriesQuery{Level: -1, Query: "level>=error"}, []string{"e06", "e05"}},
+			{"QueryNot", &logs.LogEntriesQuery{Level: -1, Query: `"reset" -user:erin`}, []string{"e01"}},
+			{"QueryExact", &logs.LogEntriesQuery{Level: -1, Query: "user:bob"}, []string{"e02"}},
+			{"QueryExactCase", &logs.LogEntriesQuery{Level: -1, Query: "user:ALICE"}, []string{"e01"}},
+			{"QueryExactID", &logs.LogEntriesQuery{Level: -1, Query: "id:E05"}, []string{"e05"}},
+			{"QueryExactQuoted", &logs.LogEntriesQuery{Level: -1, Query: `message:"disk 90% FULL"`}, []string{"e04"}},
+			{"QueryExactQuotedWhole", &logs.LogEntriesQuery{Level: -1, Query: `message:"disk 90%"`}, []string{}},
+			{"QueryWildcard", &logs.LogEntriesQuery{Level: -1, Query: "user:bob*"}, []string{"e06", "e02"}},
+			{"QueryWildcardCase", &logs.LogEntriesQuery{Level: -1, Query: "user:*SMITH"}, []string{"e03"}},
+			{"QueryWildcardInner", &logs.LogEntriesQuery{Level: -1, Query: "trace:x*3"}, []string{"e06"}},
+			{"QueryWildcardEscaped", &logs.LogEntriesQuery{Level: -1, Query: "user:bob_*"}, []string{"e06"}},
+			{"QueryWildcardEscapedNone", &logs.LogEntriesQuery{Level: -1, Query: "user:b_b*"}, []string{}},
+			{"QueryLabel", &logs.LogEntriesQuery{Level: -1, Query: "labels.env:prod"}, []string{"e07", "e01"}},
+			{"QueryLabelWildcard", &logs.LogEntriesQuery{Level: -1, Query: "label.env:PROD*"}, []string{"e07", "e02", "e01"}},
+			{"QueryLabelNot", &logs.LogEntriesQuery{Level: -1, Query: "-labels.env:prod*"}, []string{"e06", "e05", "e04", "e03"}},
+			{"Combined", &logs.LogEntriesQuery{Level: logs.LogLevel_Infomation, User: "alice", StartTime: at(0), EndTime: at(3600)}, []string{"e03"}},
+		}
+		for _, x := range cases {
+			t.Run(x.name, func(t *testing.T) {
+				x.query.DBName, x.query.TableName = dbName, tableName
+				x.query.PageIndex, x.query.PageSize = 1, 100
+				r, totalCount, err := logDAL.GetLogEntries(x.query)
+				if err != nil {
+					t.Fatalf("GetLogEntries: %v", err)
+				}
+				if got := ids(r); !slices.Equal(got, x.want) || totalCount != int64(len(x.want)) {
+					t.Errorf("GetLogEntries = %v (%d), want %v (%d)", got, totalCount, x.want, len(x.want))
+				}
+			})
+		}
+	})
+
+	t.Run("Paging", func(t *testing.T) {
+		cases := []struct {
+			pageIndex int32
+			want      []string
+		}{
+			{1, []string{"e07", "e06", "e05"}},
+			{2, []string{"e04", "e03", "e02"}},
+			{3, []string{"e01"}},
+			{4, []string{}},
+		}
+		for _, x := range cases {
+			r, totalCount, err := logDAL.GetLogEntries(&logs.LogEntriesQuery{DBName: dbName, TableName: tableName, Level: -1, PageIndex: x.pageIndex, PageSize: 3})
+			if err != nil {
+				t.Fatalf("GetLogEntries page %d: %v", x.pageIndex, err)
+			}
+			if got := ids(r); !slices.Equal(got, x.want) || totalCount != 7 {
+				t.Errorf("GetLogEntries page %d = %v (%d), want %v (7)", x.pageIndex, got, totalCount, x.want)
+			}
+		}
+
+		_, _, err := logDAL.GetLogEntries(&logs.LogEntriesQuery{DBName: dbName, TableName: tableName, Level: -1, PageIndex: 1})
+		if err == nil {
+			t.Errorf("GetLogEntries without page size should fail")
+		}
+	})
+
+	t.Run("Cursor", func(t *testing.T) {
+		cases := []struct {
+			name   string
+			cursor *core.Cursor
+			want   []string
+		}{
+			{"Forward", &core.Cursor{CreatedOnUtc: entries[5].CreatedOnUtc, ID: "e06"}, []string{"e05", "e04", "e03"}},
+			{"ForwardEnd", &core.Cursor{CreatedOnUtc: entries[1].CreatedOnUtc, ID: "e02"}, []string{"e01"}},
+			{"Backward", &core.Cursor{CreatedOnUtc: entries[1].CreatedOnUtc, ID: "e02", Backward: true}, []string{"e05", "e04", "e03"}},
+			{"BackwardSameTime", &core.Cursor{CreatedOnUtc: entries[4].CreatedOnUtc, ID: "e05", Backward: true}, []string{"e07", "e06"}},
+		}
+		for _, x := range cases {
+			t.Run(x.name, func(t *testing.T) {
+				r, totalCount, err := logDAL.GetLogEntries(&logs.LogEntriesQuery{DBName: dbName, TableName: tableName, Level: -1, PageSize: 3, Cursor: x.cursor.String()})
+				if err != nil {
+					t.Fatalf("GetLogEntries: %v", err)
+				}
+				if got := ids(r); !slices.Equal(got, x.want) || totalCount != 7 {
+					t.Errorf("GetLogEntries = %v (%d), want %v (7)", got, totalCount, x.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Histogram", func(t *testing.T) {
+		type bucket struct {
+			startTime int64
+			level     logs.LogLevel
+			count     int64
+		}
+		hour := _base.Add(time.Hour).UnixMilli()
+		cases := []struct {
+			name     string
+			query    *logs.LogEntriesQuery
+			interval int64
+			want     []bucket
+		}{
+			{"Hour", &logs.LogEntriesQuery{Level: -1}, 3600, []bucket{
+				{_base.UnixMilli(), logs.LogLevel_Verbose, 1},
+				{_base.UnixMilli(), logs.LogLevel_Debug, 1},
+				{_base.UnixMilli(), logs.LogLevel_Infomation, 1},
+				{_base.UnixMilli(), logs.LogLevel_Warning, 1},
+				{_base.UnixMilli(), logs.LogLevel_Error, 1},
+				{_base.UnixMilli(), logs.LogLevel_Fatal, 1},
+				{hour, logs.LogLevel_Infomation, 1},
+			}},
+			{"Day", &logs.LogEntriesQuery{Level: logs.LogLevel_Infomation}, 86400, []bucket{
+				{_base.UnixMilli(), logs.LogLevel_Infomation, 2},
+			}},
+			{"Filtered", &logs.LogEntriesQuery{Level: -1, User: "bob"}, 60, []bucket{
+				{_base.Add(time.Minute).UnixMilli(), logs.LogLevel_Debug, 1},
+				{_base.Add(4 * time.Minute).UnixMilli(), logs.LogLevel_Fatal, 1},
+			}},
+		}
+		for _, x := range cases {
+			t.Run(x.name, func(t *testing.T) {
+				x.query.DBName, x.query.TableName = dbName, tableName
+				r, err := logDAL.GetLogHistogram(x.query, x.interval)
+				if err != nil {
+					t.Fatalf("GetLogHistogram: %v", err)
+				}
+				got := make([]bucket, 0, len(r))
+				for _, y := range r {
+					got = append(got, bucket{y.StartTime, y.Level, y.Count})
+				}
+				if !slices.Equal(got, x.want) {
+					t.Errorf("GetLogHistogram = %v, want %v", got, x.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Databases", func(t *testing.T) {
+		entry := newEntry("d01", 0, logs.LogLevel_Infomation, "", "", "", "", "", nil)
+		// A client whose id starts with the id is not the same client
+		for _, x := range []string{core.LOG_DB_PREFIX + clientID + "_2023", core.LOG_DB_PREFIX + clientID, core.LOG_DB_PREFIX + clientID + "x_2024"} {
+			if err := logDAL.InsertLogEntry(x, "12", entry); err != nil {
+				t.Fatalf("InsertLogEntry(%s): %v", x, err)
+			}
+		}
+		if err := logDAL.InsertLogEntry(dbName, "02", entry); err != nil {
+			t.Fatalf("InsertLogEntry: %v", err)
+		}
+
+		want := []string{dbName, core.LOG_DB_PREFIX + clientID + "_2023", core.LOG_DB_PREFIX + clientID}
+		if got, err := logDAL.GetDatabases(clientID); err != nil || !slices.Equal(got, want) {
+			t.Errorf("GetDatabases = %v, %v, want %v", got, err, want)
+		}
+		if got, err := logDAL.GetTables(dbName); err != nil || !slices.Equal(got, []string{"02", tableName}) {
+			t.Errorf("GetTables = %v, %v, want [02 %s]", got, err, tableName)
+		}
+		if got, err := logDAL.GetTables(core.LOG_DB_PREFIX + clientID + "_1999"); err != nil || len(got) != 0 {
+			t.Errorf("GetTables of a missing database = %v, %v, want none", got, err)
+		}
+
+		if err := logDAL.DropTable(dbName, "02"); err != nil {
+			t.Fatalf("DropTable: %v", err)
+		}
+		if err := logDAL.DropTable(dbName, "99"); err != nil {
+			t.Errorf("DropTable of a missing table: %v", err)
+		}
+		if got, err := logDAL.GetTables(dbName); err != nil || !slices.Equal(got, []string{tableName}) {
+			t.Errorf("GetTables after DropTable = %v, %v, want [%s]", got, err, tableName)
+		}
+
+		if err := logDAL.DropDatabase(core.LOG_DB_PREFIX + clientID + "_2023"); err != nil {
+			t.Fatalf("DropDatabase: %v", err)
+		}
+		if err := logDAL.DropDatabase(core.LOG_DB_PREFIX + clientID + "_1999"); err != nil {
+			t.Errorf("DropDatabase of a missing database: %v", err)
+		}
+		want = []string{dbName, core.LOG_DB_PREFIX + clientID}
+		if got, err := logDAL.GetDatabases(clientID); err != nil || !slices.Equal(got, want) {
+			t.Errorf("GetDatabases after DropDatabase = %v, %v, want %v", got, err, want)
+		}
+	})
+}
+
+// TestClientDAL checks clients, their keys and alert rules
+func TestClientDAL(t *testing.T, clientDAL dal.IClientDAL) {
+	clientID := newClientID()
+	idA, idB := clientID+"a", clientID+"b"
+	t.Cleanup(func() {
+		for _, x := range []string{idA, idB} {
+			if err := clientDAL.DeleteClient(x); err != nil {
+				t.Errorf("DeleteClient(%s): %v", x, err)
+			}
+		}
+		for _, x := range []string{clientID + "r1", clientID + "r2"} {
+			if err := clientDAL.DeleteAlertRule(x); err != nil {
+				t.Errorf("DeleteAlertRule(%s): %v", x, err)
+			}
+		}
+	})
+
+	t.Run("Clients", func(t *testing.T) {
+		if r, err := clientDAL.GetClient(idA); err != nil || r != nil {
+			t.Fatalf("GetClient of a missing client = %v, %v, want nil, nil", r, err)
+		}
+
+		// Inserted out of order
+		for _, x := range []*logs.LogClient{{ID: idB, DBPolicy: 2}, {ID: idA, DBPolicy: 1}} {
+			if err := clientDAL.InsertClient(x); err != nil {
+				t.Fatalf("InsertClient: %v", err)
+			}
+		}
+		if err := clientDAL.UpdateClient(&logs.LogClient{ID: idA, DBPolicy: 3, Level: logs.LogLevel_Warning}); err != nil {
+			t.Fatalf("UpdateClient: %v", err)
+		}
+		if r, err := clientDAL.GetClient(idA); err != nil || r == nil || r.DBPolicy != 3 || r.Level != logs.LogLevel_Warning {
+			t.Fatalf("GetClient after UpdateClient = %v, %v", r, err)
+		}
+
+		all, err := clientDAL.GetClients(nil)
+		if err != nil {
+			t.Fatalf("GetClients: %v", err)
+		}
+		var got []string
+		for _, x := range all {
+			if x.ID == idA || x.ID == idB {
+				got = append(got, x.ID)
+			}
+		}
+		if !slices.Equal(got, []string{idA, idB}) {
+			t.Errorf("GetClients = %v, want %v", got, []string{idA, idB})
+		}
+		if !slices.IsSortedFunc(all, func(a, b *logs.LogClient) int { return strings.Compare(a.ID, b.ID) }) {
+			t.Errorf("GetClients is not ordered by ID")
+		}
+	})
+
+	t.Run("Keys", func(t *testing.T) {
+		keys := []*logs.LogClientKey{
+			{ID: "k1", Hash: "h1", CreatedOnUtc: _base.UnixMilli()},
+			{ID: "k2", Hash: "h2", CreatedOnUtc: _base.UnixMilli(), ExpiresOnUtc: _base.Add(time.Hour).UnixMilli()},
+		}
+		if err := clientDAL.UpdateClientKeys(idB, keys); err != nil {
+			t.Fatalf("UpdateClientKeys: %v", err)
+		}
+		r, err := clientDAL.GetClientKeys(idB)
+		if err != nil || len(r) != 2 || r[0].ID != "k1" || r[1].Hash != "h2" || r[1].ExpiresOnUtc != keys[1].ExpiresOnUtc {
+			t.Fatalf("GetClientKeys = %v, %v, want %v", r, err, keys)
+		}
+
+		if err := clientDAL.UpdateClientKeys(idB, nil); err != nil {
+			t.Fatalf("UpdateClientKeys without keys: %v", err)
+		}
+		if r, err := clientDAL.GetClientKeys(idB); err != nil || len(r) != 0 {
+			t.Errorf("GetClientKeys after removing all = %v, %v, want none", r, err)
+		}
+
+		if err := clientDAL.UpdateClientKeys(idB, keys); err != nil {
+			t.Fatalf("UpdateClientKeys: %v", err)
+		}
+		if err := clientDAL.DeleteClient(idB); err != nil {
+			t.Fatalf("DeleteClient: %v", err)
+		}
+		if r, err := clientDAL.GetClient(idB); err != nil || r != nil {
+			t.Errorf("GetClient after DeleteClient = %v, %v, want nil, nil", r, err)
+		}
+		if r, err := clientDAL.GetClientKeys(idB); err != nil || len(r) != 0 {
+			t.Errorf("GetClientKeys after DeleteClient = %v, %v, want none", r, err)
+		}
+	})
+
+	t.Run("AlertRules", func(t *testing.T) {
+		for _, x := range []*logs.AlertRule{
+			{ID: clientID + "r2", ClientID: idA, Name: "second", Filter: "level>=error", Threshold: 10, Window: 60},
+			{ID: clientID + "r1", ClientID: idA, Name: "first"},
+		} {
+			if err := clientDAL.SaveAlertRule(x); err != nil {
+				t.Fatalf("SaveAlertRule: %v", err)
+			}
+		}
+
+		rules := func() []*logs.AlertRule {
+			t.Helper()
+			all, err := clientDAL.GetAlertRules()
+			if err != nil {
+				t.Fatalf("GetAlertRules: %v", err)
+			}
+			if !slices.IsSortedFunc(all, func(a, b *logs.AlertRule) int {
+				if a.ClientID != b.ClientID {
+					return strings.Compare(a.ClientID, b.ClientID)
+				}
+				return strings.Compare(a.ID, b.ID)
+			}) {
+				t.Errorf("GetAlertRules is not ordered by ClientID and ID")
+			}
+			var r []*logs.AlertRule
+			for _, x := range all {
+				if x.ClientID == idA {
+					r = append(r, x)
+				}
+			}
+			return r
+		}
+
+		r := rules()
+		if len(r) != 2 || r[0].ID != clientID+"r1" || r[1].ID != clientID+"r2" || r[1].Filter != "level>=error" || r[1].Threshold != 10 {
+			t.Fatalf("GetAlertRules = %v", r)
+		}
+
+		if err := clientDAL.DeleteAlertRule(clientID + "r1"); err != nil {
+			t.Fatalf("DeleteAlertRule: %v", err)
+		}
+		if r = rules(); len(r) != 1 || r[0].ID != clientID+"r2" {
+			t.Errorf("GetAlertRules after DeleteAlertRule = %v", r)
+		}
+	})
+}
@@ -0,0 +1,313 @@
+// Package memory keeps entries, clients, keys and alert rules in process memory, everything is lost on exit.
+// It serves tests and trying the service out without a database.
+package memory
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
+	"google.golang.org/protobuf/proto"
+)
+
+// MemoryDAL stores copies of entries in tables of databases, the zero value is ready to use
+type MemoryDAL struct {
+	dbs    map[string]map[string][]*logs.LogEntry
+	locker sync.RWMutex
+}
+
+// compareEntries orders entries by CreatedOnUtc and ID descending
+func compareEntries(a, b *logs.LogEntry) int {
+	if a.CreatedOnUtc != b.CreatedOnUtc {
+		if a.CreatedOnUtc > b.CreatedOnUtc {
+			return -1
+		}
+		return 1
+	}
+	return -strings.Compare(a.ID, b.ID)
+}
+
+func cloneEntry(logEntry *logs.LogEntry) *logs.LogEntry {
+	return proto.Clone(logEntry).(*logs.LogEntry)
+}
+
+// matchText is the case insensitive LIKE of the databases, a prefix or, when fuzzy, a substring
+func matchText(value, filter string, fuzzy bool) bool {
+	if filter == "" {
+		return true
+	}
+	value, filter = strings.ToLower(value), strings.ToLower(filter)
+	if fuzzy {
+		return strings.Contains(value, filter)
+	}
+	return strings.HasPrefix(value, filter)
+}
+
+// filter returns the entries of a table matching all filters of a query, ordered by CreatedOnUtc and ID descending
+func (o *MemoryDAL) filter(query *logs.LogEntriesQuery) ([]*logs.LogEntry, error) {
+	var start, end int64
+	if query.StartTime != "" {
+		t, err := time.ParseInLocation(time.RFC3339, query.StartTime, time.UTC)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		start = t.UnixMilli()
+	}
+	if query.EndTime != "" {
+		t, err := time.ParseInLocation(time.RFC3339, query.EndTime, time.UTC)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		end = t.UnixMilli()
+	}
+
+	node, err := search.Parse(query.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	fuzzy := query.Flags&1 == 1 // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
+	var r []*logs.LogEntry
+	for _, x := range o.dbs[query.DBName][query.TableName] {
+		if (query.StartTime != "" && x.CreatedOnUtc < start) ||
+			(query.EndTime != "" && x.CreatedOnUtc > end) ||
+			(query.Level >= 0 && x.Level != query.Level) ||
+			!matchText(x.User, query.User, fuzzy) ||
+			!matchText(x.TraceNo, query.TraceNo, fuzzy) ||
+			!matchText(x.Message, query.Message, fuzzy) ||
+			!matchText(x.Error, query.Error, fuzzy) ||
+			!matchText(x.StackTrace, query.StackTrace, fuzzy) ||
+			!matchLabels(x, query.Labels) ||
+			!search.Match(node, x) {
+			continue
+		}
+		r = append(r, x)
+	}
+	slices.SortFunc(r, compareEntries)
+
+	return r, nil
+}
+
+func matchLabels(logEntry *logs.LogEntry, filters []*logs.LabelFilter) bool {
+	for _, x := range filters {
+		value, ok := logEntry.Labels[x.Key]
+		if !ok {
+			return false
+		}
+		if x.Prefix {
+			if !strings.HasPrefix(value, x.Value) {
+				return false
+			}
+		} else if value != x.Value {
+			return false
+		}
+	}
+	return true
+}
+
+// ************************************************************************************************
+
+func (o *MemoryDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.LogEntry) error {
+	if logEntry == nil {
+		return xerr.New("logEntry cannot be nil")
+	}
+	return o.InsertLogEntries(dbName, tableName, []*logs.LogEntry{logEntry})
+}
+
+func (o *MemoryDAL) InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error {
+	if dbName == "" || tableName == "" {
+		return xerr.New("dbName and tableName cannot be empty")
+	}
+	if len(logEntries) == 0 {
+		return nil
+	}
+
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	if o.dbs == nil {
+		o.dbs = make(map[string]map[string][]*logs.LogEntry)
+	}
+	db, ok := o.dbs[dbName]
+	if !ok {
+		db = make(map[string][]*logs.LogEntry)
+		o.dbs[dbName] = db
+	}
+	for _, x := range logEntries {
+		db[tableName] = append(db[tableName], cloneEntry(x))
+	}
+
+	return nil
+}
+
+func (o *MemoryDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error) {
+	if query == nil || query.ID == "" || query.DBName == "" {
+		return nil, xerr.New("query, ID and DBName cannot be nil or empty")
+	}
+
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+
+	for _, x := range o.dbs[query.DBName][query.TableName] {
+		if x.ID == query.ID {
+			return cloneEntry(x), nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (o *MemoryDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, 0, xerr.New("query, DBName and TableName cannot be nil or empty")
+	}
+
+	var cursor *core.Cursor
+	if query.Cursor != "" {
+		var err error
+		cursor, err = core.ParseCursor(query.Cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else if query.PageIndex < 1 {
+		return nil, 0, xerr.New("page index must be greater than 0")
+	}
+
+	if query.PageSize < 1 {
+		return nil, 0, xerr.New("page size must be greater than 0")
+	}
+
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+
+	list, err := o.filter(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	totalCount := int64(len(list))
+
+	pageSize := int(query.PageSize)
+	if cursor == nil {
+		start := min((int(query.PageIndex)-1)*pageSize, len(list))
+		list = list[start:min(start+pageSize, len(list))]
+	} else {
+		position := &logs.LogEntry{CreatedOnUtc: cursor.CreatedOnUtc, ID: cursor.ID}
+		if cursor.Backward {
+			// The newer entries closest to the cursor
+			end := sort.Search(len(list), func(i int) bool {
+				return compareEntries(list[i], position) >= 0
+			})
+			list = list[max(end-pageSize, 0):end]
+		} else {
+			start := sort.Search(len(list), func(i int) bool {
+				return compareEntries(list[i], position) > 0
+			})
+			list = list[start:min(start+pageSize, len(list))]
+		}
+	}
+
+	r := make([]*logs.LogEntry, 0, len(list))
+	for _, x := range list {
+		r = append(r, cloneEntry(x))
+	}
+
+	return r, totalCount, nil
+}
+
+func (o *MemoryDAL) GetLogHistogram(query *logs.LogEntriesQuery, interval int64) ([]*logs.LogHistogramBucket, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, xerr.New("query, DBName and TableName cannot be nil or empty")
+	}
+
+	if interval < 1 {
+		return nil, xerr.New("interval must be greater than 0")
+	}
+
+	o.locker.RLock()
+	list, err := o.filter(query)
+	o.locker.RUnlock()
+	if err != nil {
+		return nil, err
+	}
+
+	width := interval * 1000 // CreatedOnUtc is in milliseconds
+	type bucketKey struct {
+		startTime int64
+		level     logs.LogLevel
+	}
+	r := make([]*logs.LogHistogramBucket, 0)
+	buckets := make(map[bucketKey]*logs.LogHistogramBucket)
+	for _, x := range list {
+		startTime := x.CreatedOnUtc - x.CreatedOnUtc%width
+		if x.CreatedOnUtc%width < 0 {
+			startTime -= width // Floor before the epoch
+		}
+		key := bucketKey{startTime: startTime, level: x.Level}
+		bucket, ok := buckets[key]
+		if !ok {
+			bucket = &logs.LogHistogramBucket{StartTime: startTime, Level: x.Level}
+			buckets[key] = bucket
+			r = append(r, bucket)
+		}
+		bucket.Count++
+	}
+	slices.SortFunc(r, func(a, b *logs.LogHistogramBucket) int {
+		if a.StartTime != b.StartTime {
+			if a.StartTime < b.StartTime {
+				return -1
+			}
+			return 1
+		}
+		return int(a.Level) - int(b.Level)
+	})
+
+	return r, nil
+}
+
+func (o *MemoryDAL) GetDatabases(clientID string) ([]string, error) {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+
+	var r []string
+	for x := range o.dbs {
+		if core.IsClientDatabase(clientID, x) {
+			r = append(r, x)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(r)))
+
+	return r, nil
+}
+
+func (o *MemoryDAL) GetTables(database string) ([]string, error) {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+
+	var r []string
+	for x := range o.dbs[database] {
+		r = append(r, x)
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(r)))
+
+	return r, nil
+}
+
+func (o *MemoryDAL) DropDatabase(database string) error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	delete(o.dbs, database)
+	return nil
+}
+
+func (o *MemoryDAL) DropTable(database, table string) error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	delete(o.dbs[database], table)
+	return nil
+}
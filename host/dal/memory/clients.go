@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/DreamvatLab/logs"
+)
+
+// MemoryClientDAL stores clients, keys and alert rules in maps, the zero value is ready to use
+type MemoryClientDAL struct {
+	clientsMap map[string]*logs.LogClient
+	keysMap    map[string][]*logs.LogClientKey
+	alertsMap  map[string]*logs.AlertRule
+	locker     sync.RWMutex
+}
+
+func (o *MemoryClientDAL) InsertClient(in *logs.LogClient) error {
+	return o.UpdateClient(in)
+}
+func (o *MemoryClientDAL) GetClient(id string) (*logs.LogClient, error) {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+	return o.clientsMap[id], nil
+}
+func (o *MemoryClientDAL) UpdateClient(in *logs.LogClient) error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	if o.clientsMap == nil {
+		o.clientsMap = make(map[string]*logs.LogClient)
+	}
+	o.clientsMap[in.ID] = in
+	return nil
+}
+func (o *MemoryClientDAL) DeleteClient(id string) error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	delete(o.clientsMap, id)
+	delete(o.keysMap, id)
+	return nil
+}
+func (o *MemoryClientDAL) GetClients(*logs.LogClientsQuery) ([]*logs.LogClient, error) {
+	o.locker.RLock()
+	r := make([]*logs.LogClient, 0, len(o.clientsMap))
+	for _, x := range o.clientsMap {
+		r = append(r, x)
+	}
+	o.locker.RUnlock()
+
+	// Sort
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].ID < r[j].ID
+	})
+
+	return r, nil
+}
+
+func (o *MemoryClientDAL) GetClientKeys(clientID string) ([]*logs.LogClientKey, error) {
+	o.locker.RLock()
+	defer o.locker.RUnlock()
+	return o.keysMap[clientID], nil
+}
+func (o *MemoryClientDAL) UpdateClientKeys(clientID string, keys []*logs.LogClientKey) error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	if len(keys) == 0 {
+		delete(o.keysMap, clientID)
+		return nil
+	}
+	if o.keysMap == nil {
+		o.keysMap = make(map[string][]*logs.LogClientKey)
+	}
+	o.keysMap[clientID] = keys
+	return nil
+}
+
+// GetAlertRules is sorted by client and id
+func (o *MemoryClientDAL) GetAlertRules() ([]*logs.AlertRule, error) {
+	o.locker.RLock()
+	r := make([]*logs.AlertRule, 0, len(o.alertsMap))
+	for _, x := range o.alertsMap {
+		r = append(r, x)
+	}
+	o.locker.RUnlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].ClientID != r[j].ClientID {
+			return r[i].ClientID < r[j].ClientID
+		}
+		return r[i].ID < r[j].ID
+	})
+
+	return r, nil
+}
+func (o *MemoryClientDAL) SaveAlertRule(in *logs.AlertRule) error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	if o.alertsMap == nil {
+		o.alertsMap = make(map[string]*logs.AlertRule)
+	}
+	o.alertsMap[in.ID] = in
+	return nil
+}
+func (o *MemoryClientDAL) DeleteAlertRule(id string) error {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	delete(o.alertsMap, id)
+	return nil
+}
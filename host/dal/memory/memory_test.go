@@ -0,0 +1,16 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/DreamvatLab/logs/host/dal/daltest"
+	"github.com/DreamvatLab/logs/host/dal/memory"
+)
+
+func TestMemoryDAL(t *testing.T) {
+	daltest.TestLogDAL(t, new(memory.MemoryDAL))
+}
+
+func TestMemoryClientDAL(t *testing.T) {
+	daltest.TestClientDAL(t, new(memory.MemoryClientDAL))
+}
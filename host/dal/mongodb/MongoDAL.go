@@ -2,13 +2,18 @@ package mongodb
 
 import (
 	"context"
+	"errors"
+	"regexp"
+	"slices"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/DreamvatLab/go/xerr"
 	"github.com/DreamvatLab/go/xtask"
 	"github.com/DreamvatLab/logs"
 	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
@@ -165,12 +170,25 @@ func Init() {
 // ************************************************************************************************
 
 func (o *MongoDAL) GetDatabases(clientID string) ([]string, error) {
-	return _client.ListDatabaseNames(
+	databases, err := _client.ListDatabaseNames(
 		context.Background(),
 		// bson.M{"name": bson.M{"$regex": "LOG_" + clientID, "$options": "i"}},
-		bson.M{"name": bson.M{"$regex": core.LOG_DB_PREFIX + clientID}},
+		bson.M{"name": bson.M{"$regex": "^" + regexp.QuoteMeta(core.LOG_DB_PREFIX+clientID)}},
 		&options.ListDatabasesOptions{NameOnly: &_nameOnly},
 	)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+
+	r := make([]string, 0, len(databases))
+	for _, x := range databases {
+		if core.IsClientDatabase(clientID, x) {
+			r = append(r, x)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(r)))
+
+	return r, nil
 }
 
 func (o *MongoDAL) GetTables(database string) ([]string, error) {
@@ -183,8 +201,10 @@ func (o *MongoDAL) GetTables(database string) ([]string, error) {
 	for _, x := range tables {
 		go func(dbName string) {
 			table := db.Collection(dbName)
-			_, err = table.Indexes().CreateOne(context.Background(), mongo.IndexModel{
-				Keys: bson.M{"createdonutc": -1}, // Descending index
+			_, err := table.Indexes().CreateMany(context.Background(), []mongo.IndexModel{
+				{Keys: bson.M{"createdonutc": -1}}, // Descending index
+				// Keyset pagination orders by createdonutc and id
+				{Keys: bson.D{{Key: "createdonutc", Value: -1}, {Key: "id", Value: -1}}},
 			})
 			xerr.LogError(err)
 		}(x)
@@ -195,6 +215,24 @@ func (o *MongoDAL) GetTables(database string) ([]string, error) {
 	return tables, nil
 }
 
+func (o *MongoDAL) DropDatabase(database string) error {
+	err := _client.Database(database).Drop(context.Background())
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
+
+func (o *MongoDAL) DropTable(database, table string) error {
+	err := _client.Database(database).Collection(table).Drop(context.Background())
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
+
 func (o *MongoDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.LogEntry) error {
 	table := _client.Database(dbName).Collection(tableName)
 	_, err := table.InsertOne(context.Background(), logEntry)
@@ -205,12 +243,38 @@ func (o *MongoDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.LogEn
 	return nil
 }
 
+func (o *MongoDAL) InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error {
+	if len(logEntries) == 0 {
+		return nil
+	}
+
+	docs := make([]interface{}, 0, len(logEntries))
+	for _, x := range logEntries {
+		docs = append(docs, x)
+	}
+
+	table := _client.Database(dbName).Collection(tableName)
+	_, err := table.InsertMany(context.Background(), docs)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
+
 func (o *MongoDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error) {
+	if query == nil || query.ID == "" || query.DBName == "" {
+		return nil, xerr.New("query, ID and DBName cannot be nil or empty")
+	}
+
 	table := _client.Database(query.DBName).Collection(query.TableName)
 
 	rs := table.FindOne(context.Background(), bson.M{"id": query.ID})
 	err := rs.Err()
 	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
 		return nil, xerr.WithStack(err)
 	}
 
@@ -223,11 +287,34 @@ func (o *MongoDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error
 	return r, nil
 }
 
-func (o *MongoDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error) {
-	table := _client.Database(query.DBName).Collection(query.TableName)
-	// Find
-	match := bson.M{"$match": bson.M{}}
-	matchExp := match["$match"].(bson.M)
+// checkLabelKey rejects keys which would change the path of "labels.<key>" or start an operator
+func checkLabelKey(key string) error {
+	if strings.ContainsAny(key, "$.") {
+		return xerr.Errorf("label key '%s' cannot contain '$' or '.'", key)
+	}
+	return nil
+}
+
+// checkLabelKeys checks the label keys of the terms of a node
+func checkLabelKeys(node search.Node) error {
+	switch x := node.(type) {
+	case *search.AndNode:
+		return errors.Join(checkLabelKeys(x.Left), checkLabelKeys(x.Right))
+	case *search.OrNode:
+		return errors.Join(checkLabelKeys(x.Left), checkLabelKeys(x.Right))
+	case *search.NotNode:
+		return checkLabelKeys(x.Node)
+	case *search.TermNode:
+		if x.Field == "Labels" {
+			return checkLabelKey(x.LabelKey)
+		}
+	}
+	return nil
+}
+
+// buildMatch converts the filters of a query to a match expression
+func buildMatch(query *logs.LogEntriesQuery) (bson.M, error) {
+	matchExp := bson.M{}
 
 	// if query.Keyword != "" {
 	// 	matchExp["$or"] = []bson.M{
@@ -236,46 +323,120 @@ func (o *MongoDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 	// 	}
 	// }
 
+	createdOnUtc := bson.M{}
 	if query.StartTime != "" {
 		t, err := time.ParseInLocation(time.RFC3339, query.StartTime, time.UTC)
 		if err != nil {
-			return nil, 0, xerr.WithStack(err)
+			return nil, xerr.WithStack(err)
 		}
-		matchExp["createdonutc"] = bson.M{"$gte": t.UnixMilli()}
+		createdOnUtc["$gte"] = t.UnixMilli()
 	}
 	if query.EndTime != "" {
 		t, err := time.ParseInLocation(time.RFC3339, query.EndTime, time.UTC)
 		if err != nil {
-			return nil, 0, xerr.WithStack(err)
+			return nil, xerr.WithStack(err)
 		}
-		matchExp["createdonutc"] = bson.M{"$lte": t.UnixMilli()}
+		createdOnUtc["$lte"] = t.UnixMilli()
+	}
+	if len(createdOnUtc) > 0 {
+		matchExp["createdonutc"] = createdOnUtc
 	}
 	if query.Level >= 0 {
 		matchExp["level"] = bson.M{"$eq": query.Level}
 	}
 
-	if query.User != "" {
-		matchExp["user"] = bson.M{"$regex": query.User, "$options": "i"}
+	fuzzy := query.Flags&1 == 1 // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
+	for _, x := range [][2]string{{"user", query.User}, {"traceno", query.TraceNo}, {"message", query.Message}, {"error", query.Error}, {"stacktrace", query.StackTrace}} {
+		if x[1] == "" {
+			continue
+		}
+		pattern := regexp.QuoteMeta(x[1])
+		if !fuzzy {
+			pattern = "^" + pattern
+		}
+		matchExp[x[0]] = bson.M{"$regex": pattern, "$options": "i"}
+	}
+	// Labels are stored as a subdocument
+	for _, x := range query.Labels {
+		if err := checkLabelKey(x.Key); err != nil {
+			return nil, err
+		}
+		if x.Prefix {
+			matchExp["labels."+x.Key] = bson.M{"$regex": "^" + regexp.QuoteMeta(x.Value)}
+		} else {
+			matchExp["labels."+x.Key] = x.Value
+		}
+	}
+
+	if query.Query != "" {
+		node, err := search.Parse(query.Query)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkLabelKeys(node); err != nil {
+			return nil, err
+		}
+		if node != nil {
+			matchExp["$and"] = bson.A{search.ToBSON(node)}
+		}
+	}
+
+	return matchExp, nil
+}
+
+func (o *MongoDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, 0, xerr.New("query, DBName and TableName cannot be nil or empty")
 	}
-	if query.TraceNo != "" {
-		matchExp["traceno"] = bson.M{"$regex": query.TraceNo, "$options": "i"}
+	if query.Cursor == "" && query.PageIndex < 1 {
+		return nil, 0, xerr.New("page index must be greater than 0")
 	}
-	if query.Message != "" {
-		matchExp["message"] = bson.M{"$regex": query.Message, "$options": "i"}
+	if query.PageSize < 1 {
+		return nil, 0, xerr.New("page size must be greater than 0")
 	}
 
+	table := _client.Database(query.DBName).Collection(query.TableName)
+	matchExp, err := buildMatch(query)
+	if err != nil {
+		return nil, 0, err
+	}
+	match := bson.M{"$match": matchExp}
+
 	// TotalCount
 	count := bson.M{"$count": "totalcount"}
 
 	// Sort
 	sortDir := -1
-	sort := bson.M{"$sort": bson.M{"_id": sortDir}}
-
 	// Paginate
 	limit := bson.M{"$limit": query.PageSize}
 	// Skip
 	skip := bson.M{"$skip": (query.PageIndex - 1) * query.PageSize}
 
+	// Keyset pagination, the cursor condition only applies to the list
+	listMatch := match
+	var cursor *core.Cursor
+	if query.Cursor != "" {
+		cursor, err = core.ParseCursor(query.Cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		op := "$lt"
+		if cursor.Backward {
+			op = "$gt"
+			sortDir = 1
+		}
+		listMatch = bson.M{"$match": bson.M{"$and": []bson.M{
+			matchExp,
+			{"$or": []bson.M{
+				{"createdonutc": bson.M{op: cursor.CreatedOnUtc}},
+				{"createdonutc": cursor.CreatedOnUtc, "id": bson.M{op: cursor.ID}},
+			}},
+		}}}
+		skip = bson.M{"$skip": 0}
+	}
+	sort := bson.M{"$sort": bson.D{{Key: "createdonutc", Value: sortDir}, {Key: "id", Value: sortDir}}}
+
 	// Replace existing implementation with new ParallelRun function
 	results := xtask.ParallelRun(2,
 		func() (interface{}, error) {
@@ -299,7 +460,7 @@ func (o *MongoDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 		func() (interface{}, error) {
 			r := make([]*logs.LogEntry, 0, query.PageSize)
 			var rs *mongo.Cursor
-			rs, err := table.Aggregate(context.Background(), []bson.M{match, sort, skip, limit})
+			rs, err := table.Aggregate(context.Background(), []bson.M{listMatch, sort, skip, limit})
 			if err != nil {
 				return nil, xerr.WithStack(err)
 			}
@@ -307,12 +468,15 @@ func (o *MongoDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 			if err != nil {
 				return nil, xerr.WithStack(err)
 			}
+			if cursor != nil && cursor.Backward {
+				slices.Reverse(r)
+			}
 			return r, nil
 		},
 	)
 
 	// Merge errors using xutils.JointErrors
-	err := xerr.JointErrors(results[0].Error, results[1].Error)
+	err = xerr.JointErrors(results[0].Error, results[1].Error)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -323,3 +487,57 @@ func (o *MongoDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 
 	return list, totalCount, nil
 }
+
+func (o *MongoDAL) GetLogHistogram(query *logs.LogEntriesQuery, interval int64) ([]*logs.LogHistogramBucket, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, xerr.New("query, DBName and TableName cannot be nil or empty")
+	}
+
+	if interval < 1 {
+		return nil, xerr.New("interval must be greater than 0")
+	}
+
+	matchExp, err := buildMatch(query)
+	if err != nil {
+		return nil, err
+	}
+
+	width := interval * 1000 // createdonutc is in milliseconds
+	group := bson.M{"$group": bson.M{
+		"_id": bson.M{
+			"start": bson.M{"$subtract": bson.A{"$createdonutc", bson.M{"$mod": bson.A{"$createdonutc", width}}}},
+			"level": "$level",
+		},
+		"count": bson.M{"$sum": 1},
+	}}
+	sort := bson.M{"$sort": bson.D{{Key: "_id.start", Value: 1}, {Key: "_id.level", Value: 1}}}
+
+	table := _client.Database(query.DBName).Collection(query.TableName)
+	rs, err := table.Aggregate(context.Background(), []bson.M{{"$match": matchExp}, group, sort})
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+
+	var buckets []struct {
+		ID struct {
+			Start int64         `bson:"start"`
+			Level logs.LogLevel `bson:"level"`
+		} `bson:"_id"`
+		Count int64 `bson:"count"`
+	}
+	err = rs.All(context.Background(), &buckets)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+
+	r := make([]*logs.LogHistogramBucket, 0, len(buckets))
+	for _, x := range buckets {
+		r = append(r, &logs.LogHistogramBucket{
+			StartTime: x.ID.Start,
+			Level:     x.ID.Level,
+			Count:     x.Count,
+		})
+	}
+
+	return r, nil
+}
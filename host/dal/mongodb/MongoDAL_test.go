@@ -0,0 +1,29 @@
+package mongodb
+
+import (
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+)
+
+func TestBuildMatchLabelKeys(t *testing.T) {
+	cases := []struct {
+		name  string
+		query *logs.LogEntriesQuery
+		ok    bool
+	}{
+		{"Filter", &logs.LogEntriesQuery{Level: -1, Labels: []*logs.LabelFilter{{Key: "env", Value: "prod"}}}, true},
+		{"FilterDot", &logs.LogEntriesQuery{Level: -1, Labels: []*logs.LabelFilter{{Key: "env.x", Value: "prod"}}}, false},
+		{"FilterOperator", &logs.LogEntriesQuery{Level: -1, Labels: []*logs.LabelFilter{{Key: "$where", Value: "1"}}}, false},
+		{"Query", &logs.LogEntriesQuery{Level: -1, Query: "labels.env:prod"}, true},
+		{"QueryDot", &logs.LogEntriesQuery{Level: -1, Query: "user:bob OR -labels.env.x:prod"}, false},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			_, err := buildMatch(x.query)
+			if (err == nil) != x.ok {
+				t.Errorf("buildMatch error = %v, want ok %v", err, x.ok)
+			}
+		})
+	}
+}
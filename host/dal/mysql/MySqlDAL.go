@@ -2,8 +2,10 @@ package mysql
 
 import (
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
@@ -14,6 +16,7 @@ import (
 	"github.com/DreamvatLab/go/xtask"
 	"github.com/DreamvatLab/logs"
 	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
 
 	"github.com/go-sql-driver/mysql"
 	// _ "github.com/go-sql-driver/mysql"
@@ -34,6 +37,8 @@ var (
 	// _cacheLocker = new(sync.RWMutex)
 	_dbLocker = new(sync.RWMutex)
 	_db       *sqlx.DB
+
+	_likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
 )
 
 func Init() {
@@ -57,6 +62,34 @@ func Init() {
 type MySqlDAL struct {
 }
 
+// logEntryRow stores Labels in a JSON column
+type logEntryRow struct {
+	*logs.LogEntry
+	Labels sql.NullString `db:"Labels"`
+}
+
+func toRow(logEntry *logs.LogEntry) (*logEntryRow, error) {
+	r := &logEntryRow{LogEntry: logEntry}
+	if len(logEntry.Labels) > 0 {
+		jsonBytes, err := json.Marshal(logEntry.Labels)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		r.Labels = sql.NullString{String: string(jsonBytes), Valid: true}
+	}
+	return r, nil
+}
+
+func (o *logEntryRow) toLogEntry() (*logs.LogEntry, error) {
+	if o.Labels.Valid && o.Labels.String != "" {
+		err := json.Unmarshal([]byte(o.Labels.String), &o.LogEntry.Labels)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+	}
+	return o.LogEntry, nil
+}
+
 // ************************************************************************************************
 
 // func refreshCache() error {
@@ -189,6 +222,14 @@ func ensureDBTableExsits(err error, dbName, tableName string) error {
 			if err != nil {
 				return xerr.WithStack(err)
 			}
+		} else if ok && innerErr.Number == 1054 { // 1054: Unknown column, table created by an older version
+			_dbLocker.Lock()
+			defer _dbLocker.Unlock()
+			sql = fmt.Sprintf(_SQL_ADD_LABELS, dbName, tableName)
+			_, err = _db.Exec(sql)
+			if err != nil {
+				return xerr.WithStack(err)
+			}
 		} else {
 			return xerr.WithStack(err)
 		}
@@ -202,16 +243,21 @@ func (o *MySqlDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.LogEn
 		return xerr.New("logEntry cannot be nil")
 	}
 
+	row, err := toRow(logEntry)
+	if err != nil {
+		return err
+	}
+
 	sql := fmt.Sprintf(_SQL_INSERT, dbName, tableName)
 	_dbLocker.RLock()
-	_, err := _db.NamedExec(sql, logEntry)
+	_, err = _db.NamedExec(sql, row)
 	_dbLocker.RUnlock()
 
 	if err != nil {
 		err = ensureDBTableExsits(err, dbName, tableName) // Ensure db and table are exist
 		if err == nil {
 			// No error, retry
-			_, err := _db.NamedExec(sql, logEntry)
+			_, err := _db.NamedExec(sql, row)
 			if err != nil {
 				return xerr.WithStack(err)
 			}
@@ -224,6 +270,46 @@ func (o *MySqlDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.LogEn
 	return nil
 }
 
+func (o *MySqlDAL) InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error {
+	if len(logEntries) == 0 {
+		return nil
+	}
+
+	rows := make([]*logEntryRow, 0, len(logEntries))
+	for _, x := range logEntries {
+		row, err := toRow(x)
+		if err != nil {
+			return err
+		}
+		rows = append(rows, row)
+	}
+
+	sql := fmt.Sprintf(_SQL_INSERT, dbName, tableName)
+	for start := 0; start < len(rows); start += _INSERT_BATCH_SIZE {
+		batch := rows[start:min(start+_INSERT_BATCH_SIZE, len(rows))]
+
+		// sqlx expands the VALUES clause for each element of the slice
+		_dbLocker.RLock()
+		_, err := _db.NamedExec(sql, batch)
+		_dbLocker.RUnlock()
+
+		if err != nil {
+			err = ensureDBTableExsits(err, dbName, tableName) // Ensure db and table are exist
+			if err != nil {
+				return err
+			}
+
+			// No error, retry
+			_, err = _db.NamedExec(sql, batch)
+			if err != nil {
+				return xerr.WithStack(err)
+			}
+		}
+	}
+
+	return nil
+}
+
 func (o *MySqlDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error) {
 	if query == nil || query.ID == "" || query.DBName == "" {
 		return nil, xerr.New("query, ID and DBName cannot be nil or empty")
@@ -232,16 +318,77 @@ func (o *MySqlDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error
 	_dbLocker.RLock()
 	defer _dbLocker.RUnlock()
 
-	r := new(logs.LogEntry)
+	r := new(logEntryRow)
 	sqlSel := fmt.Sprintf(_SQL_SELECT_ONE, query.DBName, query.TableName)
 	err := _db.Get(r, sqlSel, query.ID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, xerr.New("log entry not found")
+			return nil, nil
 		}
 		return nil, xerr.WithStack(err)
 	}
 
+	return r.toLogEntry()
+}
+
+// buildWhere writes the filters of a query as " AND ..." conditions and returns the arguments of their placeholders
+func buildWhere(where *strings.Builder, query *logs.LogEntriesQuery) ([]any, error) {
+	if query.StartTime != "" {
+		t, err := time.ParseInLocation(time.RFC3339, query.StartTime, time.UTC)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		where.WriteString(" AND `CreatedOnUtc` >= " + xconv.ToString(t.UnixMilli()))
+	}
+	if query.EndTime != "" {
+		t, err := time.ParseInLocation(time.RFC3339, query.EndTime, time.UTC)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		where.WriteString(" AND `CreatedOnUtc` <= " + xconv.ToString(t.UnixMilli()))
+	}
+	if query.Level >= 0 {
+		where.WriteString(" AND `Level` = " + strconv.FormatInt(int64(query.Level), 10))
+	}
+
+	var r []any
+	fuzzy := query.Flags&1 == 1 // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
+	for _, x := range [][2]string{{"User", query.User}, {"TraceNo", query.TraceNo}, {"Message", query.Message}, {"Error", query.Error}, {"StackTrace", query.StackTrace}} {
+		if x[1] == "" {
+			continue
+		}
+		pattern := _likeReplacer.Replace(x[1]) + "%"
+		if fuzzy {
+			pattern = "%" + pattern
+		}
+		// Case insensitive under the default collation
+		where.WriteString(" AND `" + x[0] + "` LIKE ?")
+		r = append(r, pattern)
+	}
+	for _, x := range query.Labels {
+		// JSON values are compared binary, prefixes of labels are case sensitive
+		column := "JSON_UNQUOTE(JSON_EXTRACT(`Labels`, ?))"
+		if x.Prefix {
+			where.WriteString(" AND " + column + " LIKE ?")
+			r = append(r, "$."+strconv.Quote(x.Key), _likeReplacer.Replace(x.Value)+"%")
+		} else {
+			where.WriteString(" AND " + column + " = ?")
+			r = append(r, "$."+strconv.Quote(x.Key), x.Value)
+		}
+	}
+
+	if query.Query != "" {
+		node, err := search.Parse(query.Query)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			condition, args := search.ToSQL(node, search.DialectMySQL)
+			where.WriteString(" AND " + condition)
+			r = append(r, args...)
+		}
+	}
+
 	return r, nil
 }
 
@@ -250,7 +397,14 @@ func (o *MySqlDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 		return nil, 0, xerr.New("query, DBName and TableName cannot be nil or empty")
 	}
 
-	if query.PageIndex < 1 {
+	var cursor *core.Cursor
+	if query.Cursor != "" {
+		var err error
+		cursor, err = core.ParseCursor(query.Cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else if query.PageIndex < 1 {
 		return nil, 0, xerr.New("page index must be greater than 0")
 	}
 
@@ -265,50 +419,27 @@ func (o *MySqlDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 		_wherePool.Put(where)
 	}()
 
-	if query.StartTime != "" {
-		t, err := time.ParseInLocation(time.RFC3339, query.StartTime, time.UTC)
-		if err != nil {
-			return nil, 0, xerr.WithStack(err)
-		}
-		where.WriteString(" AND `CreatedOnUtc` >= " + xconv.ToString(t.UnixMilli()))
-	}
-	if query.EndTime != "" {
-		t, err := time.ParseInLocation(time.RFC3339, query.EndTime, time.UTC)
-		t = t.Add(time.Hour * 24)
-		if err != nil {
-			return nil, 0, xerr.WithStack(err)
-		}
-		where.WriteString(" AND `CreatedOnUtc` <= " + xconv.ToString(t.UnixMilli()))
-	}
-	if query.Level >= 0 {
-		where.WriteString(" AND `Level` = " + strconv.FormatInt(int64(query.Level), 10))
+	args, err := buildWhere(where, query)
+	if err != nil {
+		return nil, 0, err
 	}
 
-	// TODO: Prevent sql injection
-	if query.User != "" {
-		likeSql := " AND `User` LIKE '"
-		if query.Flags&1 == 1 { // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
-			likeSql += "%"
-		}
-		likeSql += query.User + "%'"
-		where.WriteString(likeSql)
-	}
-	if query.TraceNo != "" {
-		likeSql := " AND `TraceNo` LIKE '"
-		if query.Flags&1 == 1 { // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
-			likeSql += "%"
-		}
-		likeSql += query.TraceNo + "%'"
-		where.WriteString(likeSql)
-	}
-	if query.Message != "" {
-		likeSql := " AND `Message` LIKE '"
-		if query.Flags&1 == 1 { // Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
-			likeSql += "%"
+	// Keyset pagination, the cursor condition only applies to the list
+	pageIndex := query.PageIndex
+	listWhere := where.String()
+	listArgs := slices.Clip(args)
+	ord := "`CreatedOnUtc` DESC, `ID` DESC"
+	if cursor != nil {
+		pageIndex = 1
+		op := "<"
+		if cursor.Backward {
+			op = ">"
+			ord = "`CreatedOnUtc` ASC, `ID` ASC"
 		}
-		likeSql += query.Message + "%'"
-		where.WriteString(likeSql)
+		listWhere += " AND (`CreatedOnUtc` " + op + " ? OR (`CreatedOnUtc` = ? AND `ID` " + op + " ?))"
+		listArgs = append(listArgs, cursor.CreatedOnUtc, cursor.CreatedOnUtc, cursor.ID)
 	}
+	listArgs = append(listArgs, (pageIndex-1)*query.PageSize, query.PageSize)
 
 	_dbLocker.RLock()
 	defer _dbLocker.RUnlock()
@@ -317,33 +448,38 @@ func (o *MySqlDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 	results := xtask.ParallelRun(2,
 		func() (interface{}, error) {
 			var totalCount int64
-			countSql := fmt.Sprintf("CALL `%s`.`SYSSP_GetTotalCount` (?,?)", query.DBName)
-			table := fmt.Sprintf("`%s`.`%s`", query.DBName, query.TableName)
-			err := _db.Get(&totalCount, countSql, table, where.String())
+			countSql := fmt.Sprintf(_SQL_COUNT, query.DBName, query.TableName, where.String())
+			err := _db.Get(&totalCount, countSql, args...)
 			if err != nil {
 				return nil, xerr.WithStack(err)
 			}
 			return totalCount, nil
 		},
 		func() (interface{}, error) {
-			listSql := fmt.Sprintf("CALL `%s`.`SYSSP_GetPagedData` (?,?,?,?,?,?)", query.DBName)
-			table := fmt.Sprintf("`%s`.`%s`", query.DBName, query.TableName)
-			ord := "`CreatedOnUtc` DESC"
+			listSql := fmt.Sprintf(_SQL_PAGE, query.DBName, query.TableName, listWhere, ord)
 
-			var r []*logs.LogEntry
-			err := _db.Select(&r, listSql, query.PageSize, query.PageIndex, table, ord, "*", where.String())
+			var rows []*logEntryRow
+			err := _db.Select(&rows, listSql, listArgs...)
 			if err != nil {
 				return nil, xerr.WithStack(err)
 			}
-			if r == nil {
-				r = make([]*logs.LogEntry, 0)
+			r := make([]*logs.LogEntry, 0, len(rows))
+			for _, x := range rows {
+				logEntry, err := x.toLogEntry()
+				if err != nil {
+					return nil, err
+				}
+				r = append(r, logEntry)
+			}
+			if cursor != nil && cursor.Backward {
+				slices.Reverse(r)
 			}
 			return r, nil
 		},
 	)
 
 	// Merge errors
-	err := xerr.JointErrors(results[0].Error, results[1].Error)
+	err = xerr.JointErrors(results[0].Error, results[1].Error)
 	if err != nil {
 		return nil, 0, err
 	}
@@ -353,16 +489,60 @@ func (o *MySqlDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry,
 	return list, totalCount, nil
 }
 
+func (o *MySqlDAL) GetLogHistogram(query *logs.LogEntriesQuery, interval int64) ([]*logs.LogHistogramBucket, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, xerr.New("query, DBName and TableName cannot be nil or empty")
+	}
+
+	if interval < 1 {
+		return nil, xerr.New("interval must be greater than 0")
+	}
+
+	where := _wherePool.Get().(*strings.Builder)
+	defer func() {
+		where.Reset()
+		_wherePool.Put(where)
+	}()
+
+	args, err := buildWhere(where, query)
+	if err != nil {
+		return nil, err
+	}
+
+	width := interval * 1000 // CreatedOnUtc is in milliseconds
+	sqlStr := fmt.Sprintf(_SQL_HISTOGRAM, width, width, query.DBName, query.TableName, where.String())
+
+	_dbLocker.RLock()
+	defer _dbLocker.RUnlock()
+
+	var r []*logs.LogHistogramBucket
+	err = _db.Select(&r, sqlStr, args...)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+	if r == nil {
+		r = make([]*logs.LogHistogramBucket, 0)
+	}
+
+	return r, nil
+}
+
 func (o *MySqlDAL) GetDatabases(clientID string) ([]string, error) {
 	sqlStr := "SELECT `schema_name` FROM information_schema.schemata WHERE SCHEMA_NAME LIKE ? ORDER BY `schema_name` DESC;"
 
-	var r []string
-	keyword := "LOG\\_" + clientID + "%"
-	err := _db.Select(&r, sqlStr, keyword)
+	var databases []string
+	keyword := _likeReplacer.Replace(core.LOG_DB_PREFIX+clientID) + "%"
+	err := _db.Select(&databases, sqlStr, keyword)
 	if err != nil {
 		return nil, xerr.WithStack(err)
 	}
 
+	r := make([]string, 0, len(databases))
+	for _, x := range databases {
+		if core.IsClientDatabase(clientID, x) {
+			r = append(r, x)
+		}
+	}
 	return r, nil
 }
 
@@ -377,3 +557,27 @@ func (o *MySqlDAL) GetTables(database string) ([]string, error) {
 
 	return r, nil
 }
+
+func (o *MySqlDAL) DropDatabase(database string) error {
+	_dbLocker.Lock()
+	defer _dbLocker.Unlock()
+
+	_, err := _db.Exec(fmt.Sprintf(_SQL_DROP_DB, database))
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
+
+func (o *MySqlDAL) DropTable(database, table string) error {
+	_dbLocker.Lock()
+	defer _dbLocker.Unlock()
+
+	_, err := _db.Exec(fmt.Sprintf(_SQL_DROP_TABLE, database, table))
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
@@ -1,5 +1,10 @@
 package mysql
 
+const (
+	// Rows per multi-row INSERT, keeps placeholders under the 65535 limit
+	_INSERT_BATCH_SIZE = 1000
+)
+
 var (
 	//"CREATE TABLE `Clients` (`ID` varchar(100) NOT NULL,`DBPolicy` int(11) NOT NULL,PRIMARY KEY (`ID`)) DEFAULT CHARSET = utf8mb4 ROW_FORMAT = DYNAMIC COMPRESSION = 'zstd_1.3.8' REPLICA_NUM = 3 BLOCK_SIZE = 16384 USE_BLOOM_FILTER = FALSE TABLET_SIZE = 134217728 PCTFREE = 0;"
 	// INSERT INTO `Clients` VALUES('DL',0);
@@ -17,6 +22,7 @@ var (
 	  ` + "`Level`" + ` int(11) NOT NULL,
 	  ` + "`Flags`" + ` bigint(20) NOT NULL,
 	  ` + "`CreatedOnUtc`" + ` bigint(20) NOT NULL,
+	  ` + "`Labels`" + ` json DEFAULT NULL,
 	  PRIMARY KEY (` + "`ID`" + `),
 	  KEY ` + "`%s_TraceNO_IDX`" + ` (` + "`TraceNo`" + `),
 	  KEY ` + "`%s_User_IDX`" + ` (` + "`User`" + `),
@@ -27,9 +33,16 @@ var (
 	  KEY ` + "`%s_CreatedOnUtc_IDX`" + ` (` + "`CreatedOnUtc`" + `)
 	) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;`
 	_SQL_INSERT = `INSERT INTO ` + "`%s`" + `.` + "`%s`" + `
-	(ID, TraceNo, ` + "`User`" + `, Message, Error, StackTrace, Payload, ` + "`Level`" + `, Flags, CreatedOnUtc)
-	VALUES(:ID, :TraceNo, :User, :Message, :Error, :StackTrace, :Payload, :Level, :Flags, :CreatedOnUtc);`
+	(ID, TraceNo, ` + "`User`" + `, Message, Error, StackTrace, Payload, ` + "`Level`" + `, Flags, CreatedOnUtc, Labels)
+	VALUES(:ID, :TraceNo, :User, :Message, :Error, :StackTrace, :Payload, :Level, :Flags, :CreatedOnUtc, :Labels);`
+	// Tables created before labels were introduced
+	_SQL_ADD_LABELS = "ALTER TABLE `%s`.`%s` ADD COLUMN `Labels` json DEFAULT NULL;"
 	_SQL_SELECT_ONE = "SELECT * FROM `%s`.`%s` WHERE ID = ? LIMIT 1"
+	_SQL_DROP_DB    = "DROP DATABASE IF EXISTS `%s`;"
+	_SQL_DROP_TABLE = "DROP TABLE IF EXISTS `%s`.`%s`;"
+	_SQL_COUNT      = "SELECT COUNT(0) FROM `%s`.`%s` WHERE 0 = 0 %s"
+	_SQL_PAGE       = "SELECT * FROM `%s`.`%s` WHERE 0 = 0 %s ORDER BY %s LIMIT ?, ?"
+	_SQL_HISTOGRAM  = "SELECT (`CreatedOnUtc` DIV %d) * %d AS `StartTime`, `Level`, COUNT(0) AS `Count` FROM `%s`.`%s` WHERE 0 = 0 %s GROUP BY `StartTime`, `Level` ORDER BY `StartTime`, `Level`"
 
 	_SQL_SP_PAGE = `CREATE PROCEDURE ` + "`%s`" + `.SYSSP_GetPagedData(
 		PageSize INT,
@@ -16,11 +16,15 @@ import (
 )
 
 const (
-	_KEY = "account:Logs"
+	_KEY        = "account:Logs"
+	_KEYS_KEY   = "account:Logs:Keys"
+	_ALERTS_KEY = "account:Logs:Alerts"
 )
 
 var (
 	_clientsMap  map[string]*logs.LogClient
+	_keysMap     map[string][]*logs.LogClientKey
+	_alertsMap   map[string]*logs.AlertRule
 	_cacheLocker = new(sync.RWMutex)
 )
 
@@ -38,18 +42,29 @@ func (o *RedisDAL) Init(cp xconfig.IConfigProvider) {
 
 	err = o.refreshCache()
 	xerr.FatalIfErr(err)
+	err = o.refreshKeysCache()
+	xerr.FatalIfErr(err)
+	err = o.refreshAlertsCache()
+	xerr.FatalIfErr(err)
 
 	go o.monitor()
 }
 
 func (o *RedisDAL) monitor() {
 	// Subscribe key changes
-	sub := o.client.Subscribe(context.Background(), "__keyspace@0__:"+_KEY)
+	sub := o.client.Subscribe(context.Background(), "__keyspace@0__:"+_KEY, "__keyspace@0__:"+_KEYS_KEY, "__keyspace@0__:"+_ALERTS_KEY)
 
 	// refresh cache when there's a change
 	for {
 		msg := <-sub.Channel()
-		o.refreshCache()
+		switch msg.Channel {
+		case "__keyspace@0__:" + _KEYS_KEY:
+			o.refreshKeysCache()
+		case "__keyspace@0__:" + _ALERTS_KEY:
+			o.refreshAlertsCache()
+		default:
+			o.refreshCache()
+		}
 		xlog.Debugf("Cache refreshed (Channel:'%s', Pattern:'%s', Payload:'%s', PayloadSlice:%v)", msg.Channel, msg.Pattern, msg.Payload, msg.PayloadSlice)
 	}
 }
@@ -125,10 +140,15 @@ func (o *RedisDAL) DeleteClient(id string) error {
 	if err != nil {
 		return xerr.WithStack(err)
 	}
+	_, err = o.client.HDel(context.Background(), _KEYS_KEY, id).Result()
+	if err != nil {
+		return xerr.WithStack(err)
+	}
 
 	_cacheLocker.Lock()
 	defer _cacheLocker.Unlock()
 	delete(_clientsMap, id)
+	delete(_keysMap, id)
 
 	return nil
 }
@@ -155,3 +175,125 @@ func (o *RedisDAL) GetClients(*logs.LogClientsQuery) ([]*logs.LogClient, error)
 
 	return r, nil
 }
+
+func (o *RedisDAL) refreshKeysCache() error {
+	rs, err := o.client.HGetAll(context.Background(), _KEYS_KEY).Result()
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	keysMap := make(map[string][]*logs.LogClientKey, len(rs))
+	for k, v := range rs {
+		var keys []*logs.LogClientKey
+		err = json.Unmarshal(xbytes.StrToBytes(v), &keys)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+		keysMap[k] = keys
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_keysMap = keysMap
+
+	return nil
+}
+
+// GetClientKeys is served from cache, it is called by every authenticated write
+func (o *RedisDAL) GetClientKeys(clientID string) ([]*logs.LogClientKey, error) {
+	_cacheLocker.RLock()
+	defer _cacheLocker.RUnlock()
+	return _keysMap[clientID], nil
+}
+func (o *RedisDAL) UpdateClientKeys(clientID string, keys []*logs.LogClientKey) error {
+	var err error
+	if len(keys) == 0 {
+		_, err = o.client.HDel(context.Background(), _KEYS_KEY, clientID).Result()
+	} else {
+		var jsonStr []byte
+		jsonStr, err = json.Marshal(keys)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+		_, err = o.client.HSet(context.Background(), _KEYS_KEY, clientID, jsonStr).Result()
+	}
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_keysMap[clientID] = keys
+
+	return nil
+}
+
+func (o *RedisDAL) refreshAlertsCache() error {
+	rs, err := o.client.HGetAll(context.Background(), _ALERTS_KEY).Result()
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	alertsMap := make(map[string]*logs.AlertRule, len(rs))
+	for k, v := range rs {
+		var rule *logs.AlertRule
+		err = json.Unmarshal(xbytes.StrToBytes(v), &rule)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+		alertsMap[k] = rule
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_alertsMap = alertsMap
+
+	return nil
+}
+
+// GetAlertRules is served from cache, sorted by client and id
+func (o *RedisDAL) GetAlertRules() ([]*logs.AlertRule, error) {
+	_cacheLocker.RLock()
+	r := make([]*logs.AlertRule, 0, len(_alertsMap))
+	for _, x := range _alertsMap {
+		r = append(r, x)
+	}
+	_cacheLocker.RUnlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].ClientID != r[j].ClientID {
+			return r[i].ClientID < r[j].ClientID
+		}
+		return r[i].ID < r[j].ID
+	})
+
+	return r, nil
+}
+func (o *RedisDAL) SaveAlertRule(in *logs.AlertRule) error {
+	jsonStr, err := json.Marshal(in)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	_, err = o.client.HSet(context.Background(), _ALERTS_KEY, in.ID, jsonStr).Result()
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_alertsMap[in.ID] = in
+
+	return nil
+}
+func (o *RedisDAL) DeleteAlertRule(id string) error {
+	_, err := o.client.HDel(context.Background(), _ALERTS_KEY, id).Result()
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	delete(_alertsMap, id)
+
+	return nil
+}
@@ -0,0 +1,510 @@
+// Package sqlite stores every database in a file of ConnectionStrings.SQLite, a directory, and every table in a table of it.
+// Messages are indexed by FTS5 trigrams, which serve LIKE patterns of at least 3 characters.
+package sqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
+	"github.com/jmoiron/sqlx"
+	_ "modernc.org/sqlite"
+)
+
+var (
+	_wherePool = &sync.Pool{
+		New: func() any {
+			return new(strings.Builder)
+		},
+	}
+	// Held for writing while a database or table is dropped
+	_dbLocker  = new(sync.RWMutex)
+	_dbs       = make(map[string]*sqlx.DB)
+	_dbsLocker = new(sync.Mutex)
+	_dir       string
+
+	_likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+func Init() {
+	_dir = core.ServiceConfigProvider.GetStringDefault("ConnectionStrings.SQLite", "data")
+	err := os.MkdirAll(_dir, 0755)
+	xerr.FatalIfErr(err)
+}
+
+type SQLiteDAL struct {
+}
+
+// logEntryRow stores Labels in a JSON column
+type logEntryRow struct {
+	*logs.LogEntry
+	Labels sql.NullString `db:"Labels"`
+}
+
+func (o *logEntryRow) toLogEntry() (*logs.LogEntry, error) {
+	if o.Labels.Valid && o.Labels.String != "" {
+		err := json.Unmarshal([]byte(o.Labels.String), &o.LogEntry.Labels)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+	}
+	return o.LogEntry, nil
+}
+
+func insertArgs(logEntry *logs.LogEntry) ([]any, error) {
+	var labels sql.NullString
+	if len(logEntry.Labels) > 0 {
+		jsonBytes, err := json.Marshal(logEntry.Labels)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		labels = sql.NullString{String: string(jsonBytes), Valid: true}
+	}
+	return []any{
+		logEntry.ID,
+		logEntry.TraceNo,
+		logEntry.User,
+		logEntry.Message,
+		logEntry.Error,
+		logEntry.StackTrace,
+		logEntry.Payload,
+		int32(logEntry.Level),
+		logEntry.Flags,
+		logEntry.CreatedOnUtc,
+		labels,
+	}, nil
+}
+
+// quoteName makes an identifier for sql built by string concatenation
+func quoteName(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+}
+
+// openDB returns the connection of a database file, nil when the file does not exist and create is false
+func openDB(dbName string, create bool) (*sqlx.DB, error) {
+	if dbName == "" || dbName == "." || dbName == ".." || strings.ContainsAny(dbName, `/\`) {
+		return nil, xerr.Errorf("invalid database name '%s'", dbName)
+	}
+
+	_dbsLocker.Lock()
+	defer _dbsLocker.Unlock()
+
+	if r, ok := _dbs[dbName]; ok {
+		return r, nil
+	}
+
+	path := filepath.Join(_dir, dbName+_FILE_EXT)
+	if !create {
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+	}
+
+	r, err := sqlx.Open("sqlite", "file:"+path+_DSN_PARAMS)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+	_dbs[dbName] = r
+	return r, nil
+}
+
+// getDB returns the connection of an existing database file
+func getDB(dbName string) (*sqlx.DB, error) {
+	r, err := openDB(dbName, false)
+	if err == nil && r == nil {
+		err = xerr.Errorf("database '%s' not found", dbName)
+	}
+	return r, err
+}
+
+func createTable(db *sqlx.DB, tableName string) error {
+	sqlStr := fmt.Sprintf(_SQL_CREATE_TABLE,
+		quoteName(tableName),
+		quoteName(tableName+"_CreatedOnUtc_IDX"),
+		quoteName(tableName+"_TraceNo_IDX"),
+		quoteName(tableName+"_User_IDX"),
+		quoteName(tableName+_FTS_SUFFIX),
+		quoteName(tableName+"_AI"),
+		quoteName(tableName+"_AD"),
+	)
+	_, err := db.Exec(sqlStr)
+	return xerr.WithStack(err)
+}
+
+func isNoTable(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "no such table")
+}
+
+// ************************************************************************************************
+
+func (o *SQLiteDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.LogEntry) error {
+	if logEntry == nil {
+		return xerr.New("logEntry cannot be nil")
+	}
+	return o.InsertLogEntries(dbName, tableName, []*logs.LogEntry{logEntry})
+}
+
+func (o *SQLiteDAL) InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error {
+	if len(logEntries) == 0 {
+		return nil
+	}
+
+	_dbLocker.RLock()
+	defer _dbLocker.RUnlock()
+
+	db, err := openDB(dbName, true)
+	if err != nil {
+		return err
+	}
+
+	err = insertBatch(db, tableName, logEntries)
+	if isNoTable(err) {
+		err = createTable(db, tableName) // Ensure table exists
+		if err != nil {
+			return err
+		}
+
+		// Retry
+		err = insertBatch(db, tableName, logEntries)
+	}
+	return err
+}
+
+// insertBatch inserts entries in one transaction
+func insertBatch(db *sqlx.DB, tableName string, logEntries []*logs.LogEntry) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(fmt.Sprintf(_SQL_INSERT, quoteName(tableName)))
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	defer stmt.Close()
+
+	for _, x := range logEntries {
+		args, err := insertArgs(x)
+		if err != nil {
+			return err
+		}
+		_, err = stmt.Exec(args...)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+	}
+
+	return xerr.WithStack(tx.Commit())
+}
+
+func (o *SQLiteDAL) GetLogEntry(query *logs.LogEntryQuery) (*logs.LogEntry, error) {
+	if query == nil || query.ID == "" || query.DBName == "" {
+		return nil, xerr.New("query, ID and DBName cannot be nil or empty")
+	}
+
+	_dbLocker.RLock()
+	defer _dbLocker.RUnlock()
+
+	db, err := getDB(query.DBName)
+	if err != nil {
+		return nil, err
+	}
+
+	r := new(logEntryRow)
+	err = db.Get(r, fmt.Sprintf(_SQL_SELECT_ONE, quoteName(query.TableName)), query.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, xerr.WithStack(err)
+	}
+
+	return r.toLogEntry()
+}
+
+// buildWhere writes the filters of a query as " AND ..." conditions and returns the arguments of their placeholders.
+// Message is searched in the FTS5 index, LIKE is case insensitive for ASCII only.
+func buildWhere(where *strings.Builder, query *logs.LogEntriesQuery) ([]any, error) {
+	var r []any
+	if query.StartTime != "" {
+		t, err := time.ParseInLocation(time.RFC3339, query.StartTime, time.UTC)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		where.WriteString(" AND CreatedOnUtc >= ?")
+		r = append(r, t.UnixMilli())
+	}
+	if query.EndTime != "" {
+		t, err := time.ParseInLocation(time.RFC3339, query.EndTime, time.UTC)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		where.WriteString(" AND CreatedOnUtc <= ?")
+		r = append(r, t.UnixMilli())
+	}
+	if query.Level >= 0 {
+		where.WriteString(" AND Level = ?")
+		r = append(r, int32(query.Level))
+	}
+
+	// Has flag, do left & right fuzzy search, other wise, only do right fuzzy search
+	likePrefix := ""
+	if query.Flags&1 == 1 {
+		likePrefix = "%"
+	}
+	fts := quoteName(query.TableName + _FTS_SUFFIX)
+	for _, x := range [][2]string{{"User", query.User}, {"TraceNo", query.TraceNo}, {"Message", query.Message}, {"Error", query.Error}, {"StackTrace", query.StackTrace}} {
+		if x[1] == "" {
+			continue
+		}
+		if x[0] == "Message" {
+			where.WriteString(" AND rowid IN (SELECT rowid FROM " + fts + ` WHERE Message LIKE ? ESCAPE '\')`)
+		} else {
+			where.WriteString(" AND " + x[0] + ` LIKE ? ESCAPE '\'`)
+		}
+		r = append(r, likePrefix+_likeReplacer.Replace(x[1])+"%")
+	}
+	for _, x := range query.Labels {
+		if x.Prefix {
+			// LIKE is case insensitive, prefixes of labels are not
+			where.WriteString(" AND substr(json_extract(Labels, ?), 1, length(?)) = ?")
+			r = append(r, "$."+strconv.Quote(x.Key), x.Value, x.Value)
+		} else {
+			where.WriteString(" AND json_extract(Labels, ?) = ?")
+			r = append(r, "$."+strconv.Quote(x.Key), x.Value)
+		}
+	}
+
+	if query.Query != "" {
+		node, err := search.Parse(query.Query)
+		if err != nil {
+			return nil, err
+		}
+		if node != nil {
+			condition, args := search.ToSQL(node, search.DialectSQLite)
+			where.WriteString(" AND " + condition)
+			r = append(r, args...)
+		}
+	}
+
+	return r, nil
+}
+
+func (o *SQLiteDAL) GetLogEntries(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, 0, xerr.New("query, DBName and TableName cannot be nil or empty")
+	}
+
+	var cursor *core.Cursor
+	if query.Cursor != "" {
+		var err error
+		cursor, err = core.ParseCursor(query.Cursor)
+		if err != nil {
+			return nil, 0, err
+		}
+	} else if query.PageIndex < 1 {
+		return nil, 0, xerr.New("page index must be greater than 0")
+	}
+
+	if query.PageSize < 1 {
+		return nil, 0, xerr.New("page size must be greater than 0")
+	}
+
+	// Build where
+	where := _wherePool.Get().(*strings.Builder)
+	defer func() {
+		where.Reset()
+		_wherePool.Put(where)
+	}()
+
+	args, err := buildWhere(where, query)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// Keyset pagination, the cursor condition only applies to the list
+	pageIndex := query.PageIndex
+	listWhere := where.String()
+	listArgs := slices.Clip(args)
+	ord := "CreatedOnUtc DESC, ID DESC"
+	if cursor != nil {
+		pageIndex = 1
+		op := "<"
+		if cursor.Backward {
+			op = ">"
+			ord = "CreatedOnUtc ASC, ID ASC"
+		}
+		listWhere += " AND (CreatedOnUtc " + op + " ? OR (CreatedOnUtc = ? AND ID " + op + " ?))"
+		listArgs = append(listArgs, cursor.CreatedOnUtc, cursor.CreatedOnUtc, cursor.ID)
+	}
+	listArgs = append(listArgs, (pageIndex-1)*query.PageSize, query.PageSize)
+
+	_dbLocker.RLock()
+	defer _dbLocker.RUnlock()
+
+	db, err := getDB(query.DBName)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	table := quoteName(query.TableName)
+	var totalCount int64
+	err = db.Get(&totalCount, fmt.Sprintf(_SQL_COUNT, table, where.String()), args...)
+	if err != nil {
+		return nil, 0, xerr.WithStack(err)
+	}
+
+	var rows []*logEntryRow
+	err = db.Select(&rows, fmt.Sprintf(_SQL_PAGE, table, listWhere, ord), listArgs...)
+	if err != nil {
+		return nil, 0, xerr.WithStack(err)
+	}
+	r := make([]*logs.LogEntry, 0, len(rows))
+	for _, x := range rows {
+		logEntry, err := x.toLogEntry()
+		if err != nil {
+			return nil, 0, err
+		}
+		r = append(r, logEntry)
+	}
+	if cursor != nil && cursor.Backward {
+		slices.Reverse(r)
+	}
+
+	return r, totalCount, nil
+}
+
+func (o *SQLiteDAL) GetLogHistogram(query *logs.LogEntriesQuery, interval int64) ([]*logs.LogHistogramBucket, error) {
+	if query == nil || query.DBName == "" || query.TableName == "" {
+		return nil, xerr.New("query, DBName and TableName cannot be nil or empty")
+	}
+
+	if interval < 1 {
+		return nil, xerr.New("interval must be greater than 0")
+	}
+
+	where := _wherePool.Get().(*strings.Builder)
+	defer func() {
+		where.Reset()
+		_wherePool.Put(where)
+	}()
+
+	args, err := buildWhere(where, query)
+	if err != nil {
+		return nil, err
+	}
+
+	width := interval * 1000 // CreatedOnUtc is in milliseconds
+	sqlStr := fmt.Sprintf(_SQL_HISTOGRAM, width, width, quoteName(query.TableName), where.String())
+
+	_dbLocker.RLock()
+	defer _dbLocker.RUnlock()
+
+	db, err := getDB(query.DBName)
+	if err != nil {
+		return nil, err
+	}
+
+	var r []*logs.LogHistogramBucket
+	err = db.Select(&r, sqlStr, args...)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+	if r == nil {
+		r = make([]*logs.LogHistogramBucket, 0)
+	}
+
+	return r, nil
+}
+
+func (o *SQLiteDAL) GetDatabases(clientID string) ([]string, error) {
+	files, err := os.ReadDir(_dir)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+
+	var r []string
+	for _, x := range files {
+		name, ok := strings.CutSuffix(x.Name(), _FILE_EXT)
+		if ok && !x.IsDir() && core.IsClientDatabase(clientID, name) {
+			r = append(r, name)
+		}
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(r)))
+
+	return r, nil
+}
+
+func (o *SQLiteDAL) GetTables(database string) ([]string, error) {
+	db, err := openDB(database, false)
+	if err != nil || db == nil {
+		return nil, err
+	}
+
+	var r []string
+	err = db.Select(&r, _SQL_SELECT_TABLES)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+
+	return r, nil
+}
+
+func (o *SQLiteDAL) DropDatabase(database string) error {
+	_dbLocker.Lock()
+	defer _dbLocker.Unlock()
+
+	db, err := openDB(database, false)
+	if err != nil || db == nil {
+		return err
+	}
+
+	_dbsLocker.Lock()
+	delete(_dbs, database)
+	_dbsLocker.Unlock()
+	err = db.Close()
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	path := filepath.Join(_dir, database+_FILE_EXT)
+	for _, x := range []string{path, path + "-wal", path + "-shm"} {
+		err = os.Remove(x)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return xerr.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+func (o *SQLiteDAL) DropTable(database, table string) error {
+	_dbLocker.Lock()
+	defer _dbLocker.Unlock()
+
+	db, err := openDB(database, false)
+	if err != nil || db == nil {
+		return err
+	}
+
+	_, err = db.Exec(fmt.Sprintf(_SQL_DROP_TABLE, quoteName(table), quoteName(table+_FTS_SUFFIX)))
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	return nil
+}
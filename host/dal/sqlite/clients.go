@@ -0,0 +1,243 @@
+package sqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+
+	"github.com/DreamvatLab/go/xconfig"
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/jmoiron/sqlx"
+)
+
+const (
+	_CLIENTS_TABLE     = "Clients"
+	_CLIENT_KEYS_TABLE = "ClientKeys"
+	_ALERTS_TABLE      = "AlertRules"
+)
+
+var (
+	_clientsMap  map[string]*logs.LogClient
+	_keysMap     map[string][]*logs.LogClientKey
+	_alertsMap   map[string]*logs.AlertRule
+	_cacheLocker = new(sync.RWMutex)
+)
+
+// SQLiteClientDAL stores clients, keys and alert rules in Clients.db of ConnectionStrings.SQLite.
+// Everything is served from cache, the file is only read on Init since no other process writes it.
+type SQLiteClientDAL struct {
+	db *sqlx.DB
+}
+
+func (o *SQLiteClientDAL) Init(cp xconfig.IConfigProvider) {
+	err := o.open(cp.GetStringDefault("ConnectionStrings.SQLite", "data"))
+	xerr.FatalIfErr(err)
+}
+
+// open creates Clients.db in dir when missing and loads the cache from it
+func (o *SQLiteClientDAL) open(dir string) error {
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	o.db, err = sqlx.Open("sqlite", "file:"+filepath.Join(dir, _CLIENTS_DB+_FILE_EXT)+_DSN_PARAMS)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	_, err = o.db.Exec(_SQL_CREATE_CLIENT_DB)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_clientsMap = make(map[string]*logs.LogClient)
+	err = o.load(_CLIENTS_TABLE, func(id string, data []byte) error {
+		var client *logs.LogClient
+		err := json.Unmarshal(data, &client)
+		_clientsMap[id] = client
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_keysMap = make(map[string][]*logs.LogClientKey)
+	err = o.load(_CLIENT_KEYS_TABLE, func(id string, data []byte) error {
+		var keys []*logs.LogClientKey
+		err := json.Unmarshal(data, &keys)
+		_keysMap[id] = keys
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	_alertsMap = make(map[string]*logs.AlertRule)
+	return o.load(_ALERTS_TABLE, func(id string, data []byte) error {
+		var rule *logs.AlertRule
+		err := json.Unmarshal(data, &rule)
+		_alertsMap[id] = rule
+		return err
+	})
+}
+
+// load calls fn with the id and JSON of every row of a table
+func (o *SQLiteClientDAL) load(table string, fn func(id string, data []byte) error) error {
+	rows, err := o.db.Query(fmt.Sprintf(_SQL_SELECT_CLIENT_DATA, table))
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var data []byte
+		err = rows.Scan(&id, &data)
+		if err == nil {
+			err = fn(id, data)
+		}
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+	}
+	return xerr.WithStack(rows.Err())
+}
+
+func (o *SQLiteClientDAL) save(table, id string, v any) error {
+	jsonBytes, err := json.Marshal(v)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	_, err = o.db.Exec(fmt.Sprintf(_SQL_UPSERT_CLIENT_DATA, table), id, string(jsonBytes))
+	return xerr.WithStack(err)
+}
+
+func (o *SQLiteClientDAL) delete(table, id string) error {
+	_, err := o.db.Exec(fmt.Sprintf(_SQL_DELETE_CLIENT_DATA, table), id)
+	return xerr.WithStack(err)
+}
+
+func (o *SQLiteClientDAL) InsertClient(in *logs.LogClient) error {
+	return o.UpdateClient(in)
+}
+func (o *SQLiteClientDAL) GetClient(id string) (*logs.LogClient, error) {
+	_cacheLocker.RLock()
+	defer _cacheLocker.RUnlock()
+	return _clientsMap[id], nil
+}
+func (o *SQLiteClientDAL) UpdateClient(in *logs.LogClient) error {
+	err := o.save(_CLIENTS_TABLE, in.ID, in)
+	if err != nil {
+		return err
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_clientsMap[in.ID] = in
+
+	return nil
+}
+func (o *SQLiteClientDAL) DeleteClient(id string) error {
+	err := o.delete(_CLIENTS_TABLE, id)
+	if err != nil {
+		return err
+	}
+	err = o.delete(_CLIENT_KEYS_TABLE, id)
+	if err != nil {
+		return err
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	delete(_clientsMap, id)
+	delete(_keysMap, id)
+
+	return nil
+}
+func (o *SQLiteClientDAL) GetClients(*logs.LogClientsQuery) ([]*logs.LogClient, error) {
+	_cacheLocker.RLock()
+	r := make([]*logs.LogClient, 0, len(_clientsMap))
+	for _, x := range _clientsMap {
+		r = append(r, x)
+	}
+	_cacheLocker.RUnlock()
+
+	// Sort
+	sort.Slice(r, func(i, j int) bool {
+		return r[i].ID < r[j].ID
+	})
+
+	return r, nil
+}
+
+func (o *SQLiteClientDAL) GetClientKeys(clientID string) ([]*logs.LogClientKey, error) {
+	_cacheLocker.RLock()
+	defer _cacheLocker.RUnlock()
+	return _keysMap[clientID], nil
+}
+func (o *SQLiteClientDAL) UpdateClientKeys(clientID string, keys []*logs.LogClientKey) error {
+	var err error
+	if len(keys) == 0 {
+		err = o.delete(_CLIENT_KEYS_TABLE, clientID)
+	} else {
+		err = o.save(_CLIENT_KEYS_TABLE, clientID, keys)
+	}
+	if err != nil {
+		return err
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_keysMap[clientID] = keys
+
+	return nil
+}
+
+// GetAlertRules is sorted by client and id
+func (o *SQLiteClientDAL) GetAlertRules() ([]*logs.AlertRule, error) {
+	_cacheLocker.RLock()
+	r := make([]*logs.AlertRule, 0, len(_alertsMap))
+	for _, x := range _alertsMap {
+		r = append(r, x)
+	}
+	_cacheLocker.RUnlock()
+
+	sort.Slice(r, func(i, j int) bool {
+		if r[i].ClientID != r[j].ClientID {
+			return r[i].ClientID < r[j].ClientID
+		}
+		return r[i].ID < r[j].ID
+	})
+
+	return r, nil
+}
+func (o *SQLiteClientDAL) SaveAlertRule(in *logs.AlertRule) error {
+	err := o.save(_ALERTS_TABLE, in.ID, in)
+	if err != nil {
+		return err
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	_alertsMap[in.ID] = in
+
+	return nil
+}
+func (o *SQLiteClientDAL) DeleteAlertRule(id string) error {
+	err := o.delete(_ALERTS_TABLE, id)
+	if err != nil {
+		return err
+	}
+
+	_cacheLocker.Lock()
+	defer _cacheLocker.Unlock()
+	delete(_alertsMap, id)
+
+	return nil
+}
@@ -0,0 +1,12 @@
+package sqlite
+
+// SetDir stores databases in dir instead of ConnectionStrings.SQLite
+func SetDir(dir string) {
+	_dir = dir
+}
+
+// NewClientDAL opens Clients.db in dir instead of ConnectionStrings.SQLite
+func NewClientDAL(dir string) (*SQLiteClientDAL, error) {
+	r := new(SQLiteClientDAL)
+	return r, r.open(dir)
+}
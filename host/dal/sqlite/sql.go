@@ -0,0 +1,53 @@
+package sqlite
+
+const (
+	// Extension of database files
+	_FILE_EXT = ".db"
+	// WAL lets readers run beside the writer, writers wait for each other up to busy_timeout milliseconds
+	_DSN_PARAMS = "?_pragma=busy_timeout(10000)&_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_txlock=immediate"
+	// Suffix of the FTS5 table indexing the messages of a table
+	_FTS_SUFFIX = "_FTS"
+
+	// The TEXT primary key keeps the implicit rowid the FTS5 table refers to.
+	// Triggers keep the index in sync, entries are never updated.
+	_SQL_CREATE_TABLE = `CREATE TABLE IF NOT EXISTS %[1]s (
+	  ID TEXT NOT NULL PRIMARY KEY,
+	  TraceNo TEXT NOT NULL DEFAULT '',
+	  User TEXT NOT NULL DEFAULT '',
+	  Message TEXT NOT NULL DEFAULT '',
+	  Error TEXT NOT NULL DEFAULT '',
+	  StackTrace TEXT NOT NULL DEFAULT '',
+	  Payload TEXT NOT NULL DEFAULT '',
+	  Level INTEGER NOT NULL,
+	  Flags INTEGER NOT NULL,
+	  CreatedOnUtc INTEGER NOT NULL,
+	  Labels TEXT
+	);
+	CREATE INDEX IF NOT EXISTS %[2]s ON %[1]s (CreatedOnUtc, ID);
+	CREATE INDEX IF NOT EXISTS %[3]s ON %[1]s (TraceNo);
+	CREATE INDEX IF NOT EXISTS %[4]s ON %[1]s (User);
+	CREATE VIRTUAL TABLE IF NOT EXISTS %[5]s USING fts5(Message, content=%[1]s, content_rowid=rowid, tokenize='trigram');
+	CREATE TRIGGER IF NOT EXISTS %[6]s AFTER INSERT ON %[1]s BEGIN
+	  INSERT INTO %[5]s (rowid, Message) VALUES (new.rowid, new.Message);
+	END;
+	CREATE TRIGGER IF NOT EXISTS %[7]s AFTER DELETE ON %[1]s BEGIN
+	  INSERT INTO %[5]s (%[5]s, rowid, Message) VALUES ('delete', old.rowid, old.Message);
+	END;`
+	_SQL_INSERT     = "INSERT INTO %s (ID, TraceNo, User, Message, Error, StackTrace, Payload, Level, Flags, CreatedOnUtc, Labels) VALUES(?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);"
+	_SQL_SELECT_ONE = "SELECT * FROM %s WHERE ID = ? LIMIT 1"
+	_SQL_DROP_TABLE = "DROP TABLE IF EXISTS %s; DROP TABLE IF EXISTS %s;"
+	_SQL_COUNT      = "SELECT COUNT(0) FROM %s WHERE 0 = 0 %s"
+	_SQL_PAGE       = "SELECT * FROM %s WHERE 0 = 0 %s ORDER BY %s LIMIT ?, ?"
+	_SQL_HISTOGRAM  = "SELECT (CreatedOnUtc / %d) * %d AS StartTime, Level, COUNT(0) AS Count FROM %s WHERE 0 = 0 %s GROUP BY StartTime, Level ORDER BY StartTime, Level"
+	// Tables of entries, without FTS5 tables and their shadow tables
+	_SQL_SELECT_TABLES = `SELECT name FROM sqlite_master WHERE type = 'table' AND name NOT LIKE '%\_FTS%' ESCAPE '\' AND name NOT LIKE 'sqlite\_%' ESCAPE '\' ORDER BY name DESC`
+
+	// Clients, keys and alert rules are stored as JSON by id
+	_CLIENTS_DB           = "Clients"
+	_SQL_CREATE_CLIENT_DB = `CREATE TABLE IF NOT EXISTS Clients (ID TEXT NOT NULL PRIMARY KEY, Data TEXT NOT NULL);
+	CREATE TABLE IF NOT EXISTS ClientKeys (ID TEXT NOT NULL PRIMARY KEY, Data TEXT NOT NULL);
+	CREATE TABLE IF NOT EXISTS AlertRules (ID TEXT NOT NULL PRIMARY KEY, Data TEXT NOT NULL);`
+	_SQL_SELECT_CLIENT_DATA = "SELECT ID, Data FROM %s"
+	_SQL_UPSERT_CLIENT_DATA = "INSERT INTO %s (ID, Data) VALUES(?, ?) ON CONFLICT(ID) DO UPDATE SET Data = excluded.Data"
+	_SQL_DELETE_CLIENT_DATA = "DELETE FROM %s WHERE ID = ?"
+)
@@ -0,0 +1,21 @@
+package sqlite_test
+
+import (
+	"testing"
+
+	"github.com/DreamvatLab/logs/host/dal/daltest"
+	"github.com/DreamvatLab/logs/host/dal/sqlite"
+)
+
+func TestSQLiteDAL(t *testing.T) {
+	sqlite.SetDir(t.TempDir())
+	daltest.TestLogDAL(t, new(sqlite.SQLiteDAL))
+}
+
+func TestSQLiteClientDAL(t *testing.T) {
+	clientDAL, err := sqlite.NewClientDAL(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	daltest.TestClientDAL(t, clientDAL)
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs/host/svc"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// Required by the official clients since 7.14
+	_ELASTIC_PRODUCT_HEADER = "X-Elastic-Product"
+)
+
+type elasticError struct {
+	Type   string `json:"type"`
+	Reason string `json:"reason"`
+}
+
+type elasticItem struct {
+	Index   string        `json:"_index"`
+	ID      string        `json:"_id,omitempty"`
+	Version int           `json:"_version,omitempty"`
+	Result  string        `json:"result,omitempty"`
+	Status  int           `json:"status"`
+	Error   *elasticError `json:"error,omitempty"`
+}
+
+type elasticBulkResult struct {
+	Took   int64                     `json:"took"`
+	Errors bool                      `json:"errors"`
+	Items  []map[string]*elasticItem `json:"items"`
+}
+
+// bulkElastic takes the NDJSON action and source pairs of the _bulk API, index and create actions are written,
+// update and delete fail per item. The index of an action defaults to the {index} of the path.
+func bulkElastic(ctx *fasthttp.RequestCtx) {
+	startedOn := time.Now()
+	body, err := readBody(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	defaultIndex, _ := ctx.UserValue("index").(string)
+	actions := make([]string, 0)
+	docs := make([]*svc.ElasticDoc, 0)
+	scanner := bufio.NewScanner(bytes.NewReader(body))
+	scanner.Buffer(nil, len(body)+1)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		var action map[string]struct {
+			Index string `json:"_index"`
+		}
+		err := json.Unmarshal(line, &action)
+		if err != nil || len(action) != 1 {
+			writeElasticErr(fasthttp.StatusBadRequest, "illegal_argument_exception", "malformed action line: "+string(line), ctx)
+			return
+		}
+
+		for name, meta := range action {
+			doc := &svc.ElasticDoc{Index: meta.Index}
+			if doc.Index == "" {
+				doc.Index = defaultIndex
+			}
+			actions = append(actions, name)
+			docs = append(docs, doc)
+
+			switch name {
+			case "index", "create", "update":
+				// The source line follows
+				if !scanner.Scan() {
+					writeElasticErr(fasthttp.StatusBadRequest, "illegal_argument_exception", "missing source of the last action", ctx)
+					return
+				}
+				if name == "update" {
+					doc.Err = xerr.New("update is not supported, log entries are immutable")
+				} else {
+					doc.Err = json.Unmarshal(scanner.Bytes(), &doc.Source)
+				}
+			case "delete":
+				doc.Err = xerr.New("delete is not supported, log entries are immutable")
+			default:
+				writeElasticErr(fasthttp.StatusBadRequest, "illegal_argument_exception", "unknown action '"+name+"'", ctx)
+				return
+			}
+			if doc.Err == nil && doc.Index == "" {
+				doc.Err = xerr.New("index is missing")
+			}
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	results, err := svc.IndexElastic(rpcContext(ctx), docs)
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+
+	r := &elasticBulkResult{Items: make([]map[string]*elasticItem, len(results))}
+	for i, x := range results {
+		item := toElasticItem(x)
+		r.Errors = r.Errors || item.Error != nil
+		r.Items[i] = map[string]*elasticItem{actions[i]: item}
+	}
+	r.Took = time.Since(startedOn).Milliseconds()
+
+	ctx.Response.Header.Set(_ELASTIC_PRODUCT_HEADER, "Elasticsearch")
+	writeJson(r, ctx)
+}
+
+// indexElasticDoc takes a single document of the /{index}/_doc API, a given id is ignored since the host assigns ids
+func indexElasticDoc(ctx *fasthttp.RequestCtx) {
+	body, err := readBody(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	doc := &svc.ElasticDoc{Index: ctx.UserValue("index").(string)}
+	doc.Err = json.Unmarshal(body, &doc.Source)
+
+	results, err := svc.IndexElastic(rpcContext(ctx), []*svc.ElasticDoc{doc})
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+
+	item := toElasticItem(results[0])
+	if item.Error != nil {
+		writeElasticErr(item.Status, item.Error.Type, item.Error.Reason, ctx)
+		return
+	}
+
+	ctx.Response.Header.Set(_ELASTIC_PRODUCT_HEADER, "Elasticsearch")
+	writeJson(item, ctx)
+	ctx.SetStatusCode(item.Status)
+}
+
+func toElasticItem(in *svc.ElasticResult) *elasticItem {
+	r := &elasticItem{
+		Index:  in.Index,
+		ID:     in.ID,
+		Status: in.Status,
+	}
+	switch {
+	case in.ErrType != "":
+		r.Error = &elasticError{Type: in.ErrType, Reason: in.Reason}
+	case in.ID == "":
+		r.Result = "noop"
+	default:
+		r.Version = 1
+		r.Result = "created"
+	}
+	return r
+}
+
+// writeElasticErr responds an error in the shape of Elasticsearch
+func writeElasticErr(status int, errType, reason string, ctx *fasthttp.RequestCtx) {
+	ctx.Response.Header.Set(_ELASTIC_PRODUCT_HEADER, "Elasticsearch")
+	writeJson(map[string]any{
+		"error":  &elasticError{Type: errType, Reason: reason},
+		"status": status,
+	}, ctx)
+	ctx.SetStatusCode(status)
+}
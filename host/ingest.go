@@ -0,0 +1,279 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xhttp"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/svc"
+	"github.com/valyala/fasthttp"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	_CTYPE_NDJSON     = "application/x-ndjson"
+	_CLIENT_ID_HEADER = "X-Client-ID"
+)
+
+var (
+	// Maximum bytes of an ingestion request body after decompression, Write.MaxBodySize of web.json
+	_maxBodySize = 4 * 1024 * 1024
+
+	errBodyTooLarge = errors.New("request body is too large")
+)
+
+// readBody returns the request body, gunzipped when Content-Encoding is gzip, no larger than _maxBodySize
+func readBody(ctx *fasthttp.RequestCtx) ([]byte, error) {
+	body := ctx.Request.Body()
+	if len(body) > _maxBodySize {
+		return nil, errBodyTooLarge
+	}
+	if !bytes.EqualFold(ctx.Request.Header.ContentEncoding(), []byte("gzip")) {
+		return body, nil
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+	defer reader.Close()
+
+	// Read one more byte to tell a body of exactly the limit from a larger one
+	r, err := io.ReadAll(io.LimitReader(reader, int64(_maxBodySize)+1))
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+	if len(r) > _maxBodySize {
+		return nil, errBodyTooLarge
+	}
+	return r, nil
+}
+
+// writeBodyErr responds 413 for a body over the limit, otherwise 400
+func writeBodyErr(err error, ctx *fasthttp.RequestCtx) {
+	writeJson(struct{ Message string }{Message: err.Error()}, ctx)
+	if errors.Is(err, errBodyTooLarge) {
+		ctx.SetStatusCode(fasthttp.StatusRequestEntityTooLarge)
+	} else {
+		ctx.SetStatusCode(fasthttp.StatusBadRequest)
+	}
+}
+
+// writeLogs writes entries of the client in ?client= or the X-Client-ID header. The body is a LogEntry, a JSON array
+// of them, or NDJSON with one per line. Results are in the same order as the entries, an entry failing validation has
+// the reason in its Message and is not written.
+func writeLogs(ctx *fasthttp.RequestCtx) {
+	clientID := string(ctx.QueryArgs().Peek("client"))
+	if clientID == "" {
+		clientID = string(ctx.Request.Header.Peek(_CLIENT_ID_HEADER))
+	}
+	if clientID == "" {
+		writeStatusErr(status.Error(codes.InvalidArgument, "client is required"), ctx)
+		return
+	}
+
+	body, err := readBody(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	entries, errs := decodeEntries(body, bytes.HasPrefix(ctx.Request.Header.ContentType(), []byte(_CTYPE_NDJSON)))
+	if len(entries) == 0 {
+		writeBodyErr(xerr.New("no entries in the request body"), ctx)
+		return
+	}
+
+	rpcCtx := rpcContext(ctx)
+	err = svc.Authorize(rpcCtx, clientID)
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+
+	r := &logs.WriteLogEntriesResult{
+		Results: make([]*logs.WriteLogEntryResult, len(entries)),
+	}
+	valid := make([]*logs.LogEntry, 0, len(entries))
+	indexes := make([]int, 0, len(entries))
+	for i, x := range entries {
+		if errs[i] == nil {
+			errs[i] = validateEntry(x)
+		}
+		if errs[i] != nil {
+			r.Results[i] = &logs.WriteLogEntryResult{Message: errs[i].Error()}
+			continue
+		}
+		valid = append(valid, x)
+		indexes = append(indexes, i)
+	}
+
+	if len(valid) > 0 {
+		rs, err := logService.WriteLogEntries(rpcCtx, &logs.WriteLogEntriesCommand{
+			ClientID:   clientID,
+			LogEntries: valid,
+		})
+		if err != nil {
+			writeStatusErr(err, ctx)
+			return
+		}
+		if rs.Message != "" {
+			writeJson(rs, ctx)
+			ctx.SetStatusCode(fasthttp.StatusBadRequest)
+			return
+		}
+		for i, x := range rs.Results {
+			r.Results[indexes[i]] = x
+		}
+	}
+
+	writeJson(r, ctx)
+}
+
+// decodeEntries decodes a LogEntry, a JSON array or NDJSON, the errors are of the entries at the same indexes
+func decodeEntries(body []byte, ndjson bool) ([]*logs.LogEntry, []error) {
+	body = bytes.TrimSpace(body)
+	if len(body) == 0 {
+		return nil, nil
+	}
+
+	var items [][]byte
+	switch {
+	case !ndjson && body[0] == '[':
+		var array []json.RawMessage
+		err := json.Unmarshal(body, &array)
+		if err != nil {
+			return []*logs.LogEntry{nil}, []error{xerr.WithStack(err)}
+		}
+		for _, x := range array {
+			items = append(items, x)
+		}
+	case !ndjson && json.Valid(body):
+		items = [][]byte{body}
+	default:
+		for _, line := range bytes.Split(body, []byte("\n")) {
+			if line = bytes.TrimSpace(line); len(line) > 0 {
+				items = append(items, line)
+			}
+		}
+	}
+
+	entries := make([]*logs.LogEntry, len(items))
+	errs := make([]error, len(items))
+	for i, x := range items {
+		errs[i] = json.Unmarshal(x, &entries[i])
+	}
+	return entries, errs
+}
+
+// validateEntry checks an entry decoded from JSON, a missing CreatedOnUtc is set to now
+func validateEntry(in *logs.LogEntry) error {
+	if in == nil {
+		return xerr.New("entry cannot be null")
+	}
+	if in.Message == "" && in.Error == "" {
+		return xerr.New("Message or Error is required")
+	}
+	if _, ok := logs.LogLevel_name[int32(in.Level)]; !ok {
+		return xerr.Errorf("invalid Level %d", in.Level)
+	}
+	if in.CreatedOnUtc < 0 {
+		return xerr.Errorf("invalid CreatedOnUtc %d", in.CreatedOnUtc)
+	} else if in.CreatedOnUtc == 0 {
+		in.CreatedOnUtc = time.Now().UnixMilli()
+	}
+	return nil
+}
+
+// exportOTLPLogs is the OTLP/HTTP logs endpoint, it takes protobuf or JSON, optionally gzipped, and responds in the same encoding
+func exportOTLPLogs(ctx *fasthttp.RequestCtx) {
+	body, err := readBody(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	isJSON := bytes.HasPrefix(ctx.Request.Header.ContentType(), []byte(xhttp.CTYPE_JSON))
+	request := new(collogspb.ExportLogsServiceRequest)
+	if isJSON {
+		err = svc.UnmarshalOTLPJSON(body, request)
+	} else {
+		err = proto.Unmarshal(body, request)
+	}
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	rs, err := otlpService.Export(rpcContext(ctx), request)
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+
+	var rsBytes []byte
+	if isJSON {
+		rsBytes, err = protojson.Marshal(rs)
+	} else {
+		rsBytes, err = proto.Marshal(rs)
+	}
+	if !handleErr(err, ctx) {
+		ctx.SetContentTypeBytes(ctx.Request.Header.ContentType())
+		ctx.Write(rsBytes)
+	}
+}
+
+// rpcContext carries the authorization header and client certificate of a request like a gRPC call, so that services authorize it the same way
+func rpcContext(ctx *fasthttp.RequestCtx) context.Context {
+	r := context.Background()
+	if auth := ctx.Request.Header.Peek("Authorization"); len(auth) > 0 {
+		r = metadata.NewIncomingContext(r, metadata.Pairs("authorization", string(auth)))
+	}
+	if state := ctx.TLSConnectionState(); state != nil {
+		r = peer.NewContext(r, &peer.Peer{
+			Addr:     ctx.RemoteAddr(),
+			AuthInfo: credentials.TLSInfo{State: *state},
+		})
+	}
+	return r
+}
+
+// writeStatusErr responds a gRPC status error with the matching HTTP status code
+func writeStatusErr(err error, ctx *fasthttp.RequestCtx) {
+	code := fasthttp.StatusInternalServerError
+	switch status.Code(err) {
+	case codes.InvalidArgument:
+		code = fasthttp.StatusBadRequest
+	case codes.Unauthenticated:
+		code = fasthttp.StatusUnauthorized
+	case codes.PermissionDenied:
+		code = fasthttp.StatusForbidden
+	case codes.NotFound:
+		code = fasthttp.StatusNotFound
+	case codes.ResourceExhausted:
+		code = fasthttp.StatusTooManyRequests
+		var limitErr *svc.LimitError
+		if errors.As(err, &limitErr) && limitErr.RetryAfter > 0 {
+			ctx.Response.Header.Set("Retry-After", strconv.Itoa(int(math.Ceil(limitErr.RetryAfter.Seconds()))))
+		}
+	}
+
+	writeJson(struct{ Message string }{Message: status.Convert(err).Message()}, ctx)
+	ctx.SetStatusCode(code)
+}
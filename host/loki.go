@@ -0,0 +1,154 @@
+package main
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DreamvatLab/logs/host/loki"
+	"github.com/DreamvatLab/logs/host/svc"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	_LOKI_DEFAULT_LIMIT = 100
+	_LOKI_MAX_LIMIT     = 5000
+	_LOKI_DEFAULT_RANGE = time.Hour
+)
+
+type lokiResult struct {
+	Status string `json:"status"`
+	Data   any    `json:"data"`
+}
+
+type lokiStreamsData struct {
+	ResultType string            `json:"resultType"`
+	Result     []*svc.LokiStream `json:"result"`
+	Stats      struct{}          `json:"stats"`
+}
+
+// pushLoki is the Loki push endpoint used by Promtail and other Loki clients, it responds 204 when all entries are written
+func pushLoki(ctx *fasthttp.RequestCtx) {
+	body, err := readBody(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	streams, err := loki.DecodePush(body, string(ctx.Request.Header.ContentType()), _maxBodySize)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	err = svc.PushLoki(rpcContext(ctx), streams)
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+	ctx.SetStatusCode(fasthttp.StatusNoContent)
+}
+
+// queryLokiRange runs a log query of ?query= in [start, end), with limit and direction=backward|forward
+func queryLokiRange(ctx *fasthttp.RequestCtx) {
+	args := ctx.QueryArgs()
+	start, end, err := lokiRange(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	limit := args.GetUintOrZero("limit")
+	if limit <= 0 {
+		limit = _LOKI_DEFAULT_LIMIT
+	}
+	limit = min(limit, _LOKI_MAX_LIMIT)
+
+	streams, err := svc.QueryLoki(string(args.Peek("query")), start, end, limit, string(args.Peek("direction")) == "forward")
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+
+	writeJson(&lokiResult{
+		Status: "success",
+		Data: &lokiStreamsData{
+			ResultType: "streams",
+			Result:     streams,
+		},
+	}, ctx)
+}
+
+func getLokiLabels(ctx *fasthttp.RequestCtx) {
+	start, end, err := lokiRange(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	labels, err := svc.LokiLabels(start, end)
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+	writeJson(&lokiResult{Status: "success", Data: labels}, ctx)
+}
+
+func getLokiLabelValues(ctx *fasthttp.RequestCtx) {
+	start, end, err := lokiRange(ctx)
+	if err != nil {
+		writeBodyErr(err, ctx)
+		return
+	}
+
+	values, err := svc.LokiLabelValues(ctx.UserValue("name").(string), start, end)
+	if err != nil {
+		writeStatusErr(err, ctx)
+		return
+	}
+	writeJson(&lokiResult{Status: "success", Data: values}, ctx)
+}
+
+// lokiRange reads start and end, end defaults to now and start to an hour before end, or end minus ?since= when given
+func lokiRange(ctx *fasthttp.RequestCtx) (time.Time, time.Time, error) {
+	args := ctx.QueryArgs()
+	end := time.Now()
+	var err error
+	if s := string(args.Peek("end")); s != "" {
+		end, err = parseLokiTime(s)
+		if err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	start := end.Add(-_LOKI_DEFAULT_RANGE)
+	if s := string(args.Peek("start")); s != "" {
+		start, err = parseLokiTime(s)
+	} else if s := string(args.Peek("since")); s != "" {
+		var since time.Duration
+		since, err = time.ParseDuration(s)
+		start = end.Add(-since)
+	}
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+
+	return start, end, nil
+}
+
+// parseLokiTime parses unix nanoseconds, unix seconds with an optional fraction, or RFC 3339
+func parseLokiTime(s string) (time.Time, error) {
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		// Seconds have at most 10 digits until year 2286
+		if len(strings.TrimPrefix(s, "-")) > 10 {
+			return time.Unix(0, n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		seconds, fraction := math.Modf(f)
+		return time.Unix(int64(seconds), int64(fraction*float64(time.Second))), nil
+	}
+	return time.Parse(time.RFC3339Nano, s)
+}
@@ -0,0 +1,273 @@
+package loki
+
+import (
+	"fmt"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+
+	"github.com/DreamvatLab/go/xerr"
+)
+
+const (
+	// Most values a regex is expanded to, see Values
+	_MAX_REGEX_VALUES = 100
+)
+
+// Matcher is a label matcher of a stream selector, Op is one of =, !=, =~ and !~
+type Matcher struct {
+	Name  string
+	Op    string
+	Value string
+}
+
+// LineFilter is a line filter expression, Op is one of |=, !=, |~ and !~
+type LineFilter struct {
+	Op    string
+	Value string
+}
+
+// Query is the supported subset of LogQL log queries, a stream selector followed by line filters
+type Query struct {
+	Matchers []*Matcher
+	Filters  []*LineFilter
+}
+
+// IsRegex reports whether the value of the matcher or filter is a regex
+func IsRegex(op string) bool {
+	return strings.HasSuffix(op, "~")
+}
+
+// IsNegative reports whether the matcher or filter excludes what its value matches
+func IsNegative(op string) bool {
+	return strings.HasPrefix(op, "!")
+}
+
+// ParseLabels parses the labels of a pushed stream, e.g. {app="api", env="prod"}
+func ParseLabels(s string) (map[string]string, error) {
+	p := &queryParser{s: s}
+	matchers, err := p.parseSelector()
+	if err == nil && p.skipSpaces() < len(s) {
+		err = p.errorf("unexpected '%s'", s[p.i:])
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(map[string]string, len(matchers))
+	for _, x := range matchers {
+		if x.Op != "=" {
+			return nil, xerr.Errorf("labels '%s' can only use '='", s)
+		}
+		r[x.Name] = x.Value
+	}
+	return r, nil
+}
+
+// ParseQuery parses a stream selector followed by line filters, e.g. {app="api", level=~"error|warn"} |= "timeout" != "retry".
+// Parsers, formatters and metric queries are not supported.
+func ParseQuery(s string) (*Query, error) {
+	p := &queryParser{s: s}
+	matchers, err := p.parseSelector()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Query{Matchers: matchers}
+	for p.skipSpaces() < len(s) {
+		op := p.readOp("|=", "!=", "|~", "!~")
+		if op == "" {
+			return nil, p.errorf("unsupported expression '%s', only line filters can follow the stream selector", s[p.i:])
+		}
+		value, err := p.readString()
+		if err != nil {
+			return nil, err
+		}
+		r.Filters = append(r.Filters, &LineFilter{Op: op, Value: value})
+	}
+
+	return r, nil
+}
+
+// Values expands a regex matching a finite set of strings, e.g. "error|warn" or "api-(1|2)".
+// matchAll is true for regexes matching everything like ".*", nonEmpty for regexes matching every non-empty string like ".+".
+// ok is false when the regex is none of them.
+func Values(pattern string) (values []string, matchAll, nonEmpty, ok bool) {
+	re, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return nil, false, false, false
+	}
+	re = re.Simplify()
+	for re.Op == syntax.OpCapture {
+		re = re.Sub[0]
+	}
+
+	switch re.Op {
+	case syntax.OpStar:
+		if isAnyChar(re.Sub[0]) {
+			return nil, true, false, true
+		}
+	case syntax.OpPlus:
+		if isAnyChar(re.Sub[0]) {
+			return nil, false, true, true
+		}
+	}
+
+	values, ok = expand(re)
+	return values, false, false, ok
+}
+
+func isAnyChar(re *syntax.Regexp) bool {
+	return re.Op == syntax.OpAnyChar || re.Op == syntax.OpAnyCharNotNL
+}
+
+// expand returns all strings a regex matches, ok is false when they are unbounded or more than _MAX_REGEX_VALUES
+func expand(re *syntax.Regexp) ([]string, bool) {
+	switch re.Op {
+	case syntax.OpEmptyMatch:
+		return []string{""}, true
+	case syntax.OpLiteral:
+		// Case insensitive literals match values in any case, which cannot be expanded
+		if re.Flags&syntax.FoldCase != 0 {
+			return nil, false
+		}
+		return []string{string(re.Rune)}, true
+	case syntax.OpCapture:
+		return expand(re.Sub[0])
+	case syntax.OpCharClass:
+		var r []string
+		for i := 0; i+1 < len(re.Rune); i += 2 {
+			for c := re.Rune[i]; c <= re.Rune[i+1]; c++ {
+				if len(r) == _MAX_REGEX_VALUES {
+					return nil, false
+				}
+				r = append(r, string(c))
+			}
+		}
+		return r, true
+	case syntax.OpAlternate:
+		var r []string
+		for _, x := range re.Sub {
+			values, ok := expand(x)
+			if !ok || len(r)+len(values) > _MAX_REGEX_VALUES {
+				return nil, false
+			}
+			r = append(r, values...)
+		}
+		return r, true
+	case syntax.OpConcat:
+		r := []string{""}
+		for _, x := range re.Sub {
+			values, ok := expand(x)
+			if !ok || len(r)*len(values) > _MAX_REGEX_VALUES {
+				return nil, false
+			}
+			product := make([]string, 0, len(r)*len(values))
+			for _, prefix := range r {
+				for _, value := range values {
+					product = append(product, prefix+value)
+				}
+			}
+			r = product
+		}
+		return r, true
+	default:
+		return nil, false
+	}
+}
+
+// ************************************************************************************************
+
+type queryParser struct {
+	s string
+	i int
+}
+
+func (o *queryParser) errorf(format string, args ...any) error {
+	return xerr.Errorf("LogQL error at position %d: %s", o.i+1, fmt.Sprintf(format, args...))
+}
+
+func (o *queryParser) skipSpaces() int {
+	for o.i < len(o.s) && (o.s[o.i] == ' ' || o.s[o.i] == '\t' || o.s[o.i] == '\n' || o.s[o.i] == '\r') {
+		o.i++
+	}
+	return o.i
+}
+
+func (o *queryParser) readOp(ops ...string) string {
+	o.skipSpaces()
+	for _, op := range ops {
+		if strings.HasPrefix(o.s[o.i:], op) {
+			o.i += len(op)
+			return op
+		}
+	}
+	return ""
+}
+
+// parseSelector parses {name op "value", ...}
+func (o *queryParser) parseSelector() ([]*Matcher, error) {
+	if o.readOp("{") == "" {
+		return nil, o.errorf("expected '{'")
+	}
+
+	var r []*Matcher
+	for {
+		if o.readOp("}") != "" {
+			return r, nil
+		}
+		if len(r) > 0 && o.readOp(",") == "" {
+			return nil, o.errorf("expected ',' or '}'")
+		}
+
+		o.skipSpaces()
+		start := o.i
+		for o.i < len(o.s) && isNameChar(o.s[o.i], o.i == start) {
+			o.i++
+		}
+		if o.i == start {
+			return nil, o.errorf("expected label name")
+		}
+		name := o.s[start:o.i]
+
+		// Longer operators first
+		op := o.readOp("=~", "!~", "!=", "=")
+		if op == "" {
+			return nil, o.errorf("expected operator after '%s'", name)
+		}
+		value, err := o.readString()
+		if err != nil {
+			return nil, err
+		}
+		r = append(r, &Matcher{Name: name, Op: op, Value: value})
+	}
+}
+
+// readString reads a double quoted string with Go escapes or a back quoted raw string
+func (o *queryParser) readString() (string, error) {
+	o.skipSpaces()
+	if o.i == len(o.s) || (o.s[o.i] != '"' && o.s[o.i] != '`') {
+		return "", o.errorf("expected string")
+	}
+
+	quote := o.s[o.i]
+	for j := o.i + 1; j < len(o.s); j++ {
+		if o.s[j] == '\\' && quote == '"' {
+			j++
+			continue
+		}
+		if o.s[j] == quote {
+			r, err := strconv.Unquote(o.s[o.i : j+1])
+			if err != nil {
+				return "", o.errorf("invalid string %s", o.s[o.i:j+1])
+			}
+			o.i = j + 1
+			return r, nil
+		}
+	}
+	return "", o.errorf("unterminated string")
+}
+
+func isNameChar(c byte, first bool) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c == '_' || (!first && c >= '0' && c <= '9')
+}
@@ -0,0 +1,125 @@
+package loki
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseQuery(t *testing.T) {
+	cases := []struct {
+		query string
+		want  *Query
+	}{
+		{`{app="api"}`, &Query{Matchers: []*Matcher{{"app", "=", "api"}}}},
+		{`{}`, &Query{}},
+		{` { app = "api" , env!="dev",level=~"error|warn", host !~ ` + "`web-\\d+`" + ` } `, &Query{Matchers: []*Matcher{
+			{"app", "=", "api"}, {"env", "!=", "dev"}, {"level", "=~", "error|warn"}, {"host", "!~", `web-\d+`},
+		}}},
+		{`{app="a\"b\\c"}`, &Query{Matchers: []*Matcher{{"app", "=", `a"b\c`}}}},
+		{`{app="api"} |= "timeout" != "retry" |~ "err.*" !~ ` + "`a\\.b`", &Query{
+			Matchers: []*Matcher{{"app", "=", "api"}},
+			Filters:  []*LineFilter{{"|=", "timeout"}, {"!=", "retry"}, {"|~", "err.*"}, {"!~", `a\.b`}},
+		}},
+		{"{_a1=\"x\"}\n|= \"\"", &Query{Matchers: []*Matcher{{"_a1", "=", "x"}}, Filters: []*LineFilter{{"|=", ""}}}},
+	}
+	for _, x := range cases {
+		t.Run(x.query, func(t *testing.T) {
+			got, err := ParseQuery(x.query)
+			if err != nil {
+				t.Fatalf("ParseQuery = %v", err)
+			}
+			if !reflect.DeepEqual(got, x.want) {
+				t.Errorf("ParseQuery = %+v, want %+v", got, x.want)
+			}
+		})
+	}
+}
+
+func TestParseQueryErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{``, "LogQL error at position 1: expected '{'"},
+		{`app="api"`, "LogQL error at position 1: expected '{'"},
+		{`{app="api"`, "LogQL error at position 11: expected ',' or '}'"},
+		{`{app="api" env="dev"}`, "LogQL error at position 12: expected ',' or '}'"},
+		{`{1app="api"}`, "LogQL error at position 2: expected label name"},
+		{`{app>"api"}`, "LogQL error at position 5: expected operator after 'app'"},
+		{`{app=api}`, "LogQL error at position 6: expected string"},
+		{`{app="api}`, "LogQL error at position 6: unterminated string"},
+		{`{app="\q"}`, `LogQL error at position 6: invalid string "\q"`},
+		{`{app="api"} | json`, "LogQL error at position 13: unsupported expression '| json', only line filters can follow the stream selector"},
+		{`{app="api"} |= timeout`, "LogQL error at position 16: expected string"},
+	}
+	for _, x := range cases {
+		t.Run(x.query, func(t *testing.T) {
+			_, err := ParseQuery(x.query)
+			if err == nil || err.Error() != x.want {
+				t.Errorf("ParseQuery = %v, want %s", err, x.want)
+			}
+		})
+	}
+}
+
+func TestParseLabels(t *testing.T) {
+	cases := []struct {
+		labels  string
+		want    map[string]string
+		wantErr bool
+	}{
+		{`{app="api", env="prod"}`, map[string]string{"app": "api", "env": "prod"}, false},
+		{`{}`, map[string]string{}, false},
+		{`{app=~"api"}`, nil, true},
+		{`{app="api"} |= "x"`, nil, true},
+		{`app="api"`, nil, true},
+	}
+	for _, x := range cases {
+		t.Run(x.labels, func(t *testing.T) {
+			got, err := ParseLabels(x.labels)
+			if (err != nil) != x.wantErr {
+				t.Fatalf("ParseLabels = %v, want error %v", err, x.wantErr)
+			}
+			if !x.wantErr && !reflect.DeepEqual(got, x.want) {
+				t.Errorf("ParseLabels = %v, want %v", got, x.want)
+			}
+		})
+	}
+}
+
+func TestValues(t *testing.T) {
+	cases := []struct {
+		pattern  string
+		values   []string
+		matchAll bool
+		nonEmpty bool
+		ok       bool
+	}{
+		{"error", []string{"error"}, false, false, true},
+		{"error|warn", []string{"error", "warn"}, false, false, true},
+		{"(error|warn)", []string{"error", "warn"}, false, false, true},
+		{"api-(1|2)", []string{"api-1", "api-2"}, false, false, true},
+		{"api-[1-3]", []string{"api-1", "api-2", "api-3"}, false, false, true},
+		{"a|", []string{"a", ""}, false, false, true},
+		{".*", nil, true, false, true},
+		{"(.*)", nil, true, false, true},
+		{".+", nil, false, true, true},
+		{"(?s).*", nil, true, false, true},
+		{"err.*", nil, false, false, false},
+		{"a+", nil, false, false, false},
+		{`\d+`, nil, false, false, false},
+		{"[a-z]{3}", nil, false, false, false},
+		{"(", nil, false, false, false},
+		// Case insensitive values cannot be enumerated
+		{"(?i)error", nil, false, false, false},
+		{"(?i)error|warn", nil, false, false, false},
+	}
+	for _, x := range cases {
+		t.Run(x.pattern, func(t *testing.T) {
+			values, matchAll, nonEmpty, ok := Values(x.pattern)
+			if !reflect.DeepEqual(values, x.values) || matchAll != x.matchAll || nonEmpty != x.nonEmpty || ok != x.ok {
+				t.Errorf("Values = %q %v %v %v, want %q %v %v %v", values, matchAll, nonEmpty, ok, x.values, x.matchAll, x.nonEmpty, x.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,225 @@
+// Package loki decodes Loki push requests and parses the stream selectors and line filters of LogQL.
+package loki
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	CTYPE_PROTOBUF = "application/x-protobuf"
+)
+
+type Stream struct {
+	Labels  map[string]string
+	Entries []*Entry
+}
+
+type Entry struct {
+	Timestamp time.Time
+	Line      string
+	// Structured metadata of Loki 3
+	Metadata map[string]string
+}
+
+// DecodePush decodes a push request, snappy compressed protobuf when contentType is application/x-protobuf, otherwise JSON.
+// maxSize limits the decompressed protobuf.
+func DecodePush(body []byte, contentType string, maxSize int) ([]*Stream, error) {
+	if strings.HasPrefix(contentType, CTYPE_PROTOBUF) {
+		size, err := snappy.DecodedLen(body)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		} else if size > maxSize {
+			return nil, xerr.Errorf("decompressed request of %d bytes exceeds %d", size, maxSize)
+		}
+		data, err := snappy.Decode(nil, body)
+		if err != nil {
+			return nil, xerr.WithStack(err)
+		}
+		return decodePushProto(data)
+	}
+	return decodePushJSON(body)
+}
+
+// decodePushJSON decodes {"streams":[{"stream":{"label":"value"},"values":[["<unix ns>","line",{"key":"value"}]]}]}
+func decodePushJSON(body []byte) ([]*Stream, error) {
+	var request struct {
+		Streams []struct {
+			Stream map[string]string
+			Values [][]json.RawMessage
+		}
+	}
+	err := json.Unmarshal(body, &request)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+
+	r := make([]*Stream, 0, len(request.Streams))
+	for _, x := range request.Streams {
+		stream := &Stream{
+			Labels:  x.Stream,
+			Entries: make([]*Entry, 0, len(x.Values)),
+		}
+		for _, value := range x.Values {
+			if len(value) < 2 {
+				return nil, xerr.New("value must be [timestamp, line]")
+			}
+
+			var ts, line string
+			err = json.Unmarshal(value[0], &ts)
+			if err == nil {
+				err = json.Unmarshal(value[1], &line)
+			}
+			if err != nil {
+				return nil, xerr.WithStack(err)
+			}
+			ns, err := strconv.ParseInt(ts, 10, 64)
+			if err != nil {
+				return nil, xerr.Errorf("invalid timestamp '%s'", ts)
+			}
+
+			entry := &Entry{Timestamp: time.Unix(0, ns), Line: line}
+			if len(value) > 2 {
+				err = json.Unmarshal(value[2], &entry.Metadata)
+				if err != nil {
+					return nil, xerr.WithStack(err)
+				}
+			}
+			stream.Entries = append(stream.Entries, entry)
+		}
+		r = append(r, stream)
+	}
+
+	return r, nil
+}
+
+// decodePushProto decodes logproto.PushRequest:
+//
+//	PushRequest { repeated StreamAdapter streams = 1; }
+//	StreamAdapter { string labels = 1; repeated EntryAdapter entries = 2; uint64 hash = 3; }
+//	EntryAdapter { Timestamp timestamp = 1; string line = 2; repeated LabelPairAdapter structuredMetadata = 3; }
+func decodePushProto(data []byte) ([]*Stream, error) {
+	var r []*Stream
+	err := readFields(data, func(num protowire.Number, value []byte) error {
+		if num != 1 {
+			return nil
+		}
+		stream := new(Stream)
+		err := readFields(value, func(num protowire.Number, value []byte) error {
+			switch num {
+			case 1:
+				labels, err := ParseLabels(string(value))
+				if err != nil {
+					return err
+				}
+				stream.Labels = labels
+			case 2:
+				entry, err := decodeEntryProto(value)
+				if err != nil {
+					return err
+				}
+				stream.Entries = append(stream.Entries, entry)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		r = append(r, stream)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+func decodeEntryProto(data []byte) (*Entry, error) {
+	r := new(Entry)
+	err := readFields(data, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			var seconds, nanos int64
+			err := readFields(value, func(num protowire.Number, value []byte) error {
+				n, size := protowire.ConsumeVarint(value)
+				if size < 0 {
+					return xerr.New("invalid timestamp")
+				}
+				if num == 1 {
+					seconds = int64(n)
+				} else if num == 2 {
+					nanos = int64(int32(n))
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			r.Timestamp = time.Unix(seconds, nanos)
+		case 2:
+			r.Line = string(value)
+		case 3:
+			var name, pairValue string
+			err := readFields(value, func(num protowire.Number, value []byte) error {
+				if num == 1 {
+					name = string(value)
+				} else if num == 2 {
+					pairValue = string(value)
+				}
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if r.Metadata == nil {
+				r.Metadata = make(map[string]string)
+			}
+			r.Metadata[name] = pairValue
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// readFields calls fn with the field number and value of every field in a message,
+// the value of a varint is its encoded bytes and the value of a length-delimited field is its content
+func readFields(data []byte, fn func(num protowire.Number, value []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return xerr.WithStack(protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var value []byte
+		if typ == protowire.BytesType {
+			value, n = protowire.ConsumeBytes(data)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+			if n >= 0 {
+				value = data[:n]
+			}
+		}
+		if n < 0 {
+			return xerr.WithStack(protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		err := fn(num, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
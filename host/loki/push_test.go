@@ -0,0 +1,123 @@
+package loki
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang/snappy"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestDecodePushJSON(t *testing.T) {
+	body := `{"streams":[
+		{"stream":{"client":"c1","level":"error"},"values":[["1700000000000000001","first"],["1700000000500000000","second",{"trace_id":"t1"}]]},
+		{"stream":{"client":"c2"},"values":[]}
+	]}`
+	got, err := DecodePush([]byte(body), "application/json", 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Stream{
+		{Labels: map[string]string{"client": "c1", "level": "error"}, Entries: []*Entry{
+			{Timestamp: time.Unix(1700000000, 1), Line: "first"},
+			{Timestamp: time.Unix(1700000000, 500000000), Line: "second", Metadata: map[string]string{"trace_id": "t1"}},
+		}},
+		{Labels: map[string]string{"client": "c2"}, Entries: []*Entry{}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodePush = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodePushJSONErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+	}{
+		{"NotJSON", `streams`},
+		{"MissingLine", `{"streams":[{"stream":{},"values":[["1"]]}]}`},
+		{"NumericTimestamp", `{"streams":[{"stream":{},"values":[[1,"line"]]}]}`},
+		{"InvalidTimestamp", `{"streams":[{"stream":{},"values":[["now","line"]]}]}`},
+		{"InvalidMetadata", `{"streams":[{"stream":{},"values":[["1","line",["x"]]]}]}`},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			if got, err := DecodePush([]byte(x.body), "", 1024); err == nil {
+				t.Errorf("DecodePush = %+v, want an error", got)
+			}
+		})
+	}
+}
+
+// appendMessage appends a length-delimited field
+func appendMessage(b []byte, num protowire.Number, value []byte) []byte {
+	b = protowire.AppendTag(b, num, protowire.BytesType)
+	return protowire.AppendBytes(b, value)
+}
+
+// pushRequest encodes a logproto.PushRequest of one stream with one entry
+func pushRequest(labels string, seconds, nanos int64, line string, metadata ...string) []byte {
+	var timestamp []byte
+	timestamp = protowire.AppendTag(timestamp, 1, protowire.VarintType)
+	timestamp = protowire.AppendVarint(timestamp, uint64(seconds))
+	timestamp = protowire.AppendTag(timestamp, 2, protowire.VarintType)
+	timestamp = protowire.AppendVarint(timestamp, uint64(nanos))
+
+	var entry []byte
+	entry = appendMessage(entry, 1, timestamp)
+	entry = appendMessage(entry, 2, []byte(line))
+	for i := 0; i+1 < len(metadata); i += 2 {
+		var pair []byte
+		pair = appendMessage(pair, 1, []byte(metadata[i]))
+		pair = appendMessage(pair, 2, []byte(metadata[i+1]))
+		entry = appendMessage(entry, 3, pair)
+	}
+
+	var stream []byte
+	stream = appendMessage(stream, 1, []byte(labels))
+	stream = appendMessage(stream, 2, entry)
+	// Fields not used are skipped
+	stream = protowire.AppendTag(stream, 3, protowire.VarintType)
+	stream = protowire.AppendVarint(stream, 12345)
+
+	return appendMessage(nil, 1, stream)
+}
+
+func TestDecodePushProto(t *testing.T) {
+	body := snappy.Encode(nil, pushRequest(`{client="c1", level="warn"}`, 1700000000, 42, "hello", "trace_id", "t1"))
+	got, err := DecodePush(body, CTYPE_PROTOBUF, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*Stream{{
+		Labels: map[string]string{"client": "c1", "level": "warn"},
+		Entries: []*Entry{
+			{Timestamp: time.Unix(1700000000, 42), Line: "hello", Metadata: map[string]string{"trace_id": "t1"}},
+		},
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("DecodePush = %+v, want %+v", got, want)
+	}
+}
+
+func TestDecodePushProtoErrors(t *testing.T) {
+	request := pushRequest(`{client="c1"}`, 1700000000, 0, "hello")
+	cases := []struct {
+		name    string
+		body    []byte
+		maxSize int
+	}{
+		{"NotSnappy", []byte("hello"), 1024},
+		{"TooLarge", snappy.Encode(nil, request), len(request) - 1},
+		{"Truncated", snappy.Encode(nil, request[:len(request)-3]), 1024},
+		{"InvalidLabels", snappy.Encode(nil, pushRequest(`client="c1"`, 1, 0, "hello")), 1024},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			if got, err := DecodePush(x.body, CTYPE_PROTOBUF, x.maxSize); err == nil {
+				t.Errorf("DecodePush = %+v, want an error", got)
+			}
+		})
+	}
+}
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"bufio"
 	"context"
+	"crypto/tls"
 	"embed"
 	"encoding/json"
 	"fmt"
 	"mime"
 	"net"
+	"os"
 	fp "path/filepath"
 	"strings"
+	"time"
 
 	"github.com/DreamvatLab/go/xbytes"
 	"github.com/DreamvatLab/go/xconfig"
@@ -17,21 +21,28 @@ import (
 	"github.com/DreamvatLab/go/xlog"
 	"github.com/DreamvatLab/logs"
 	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
 	"github.com/DreamvatLab/logs/host/svc"
 	"github.com/fasthttp/router"
 	"github.com/hashicorp/consul/api"
 	"github.com/valyala/fasthttp"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/protobuf/proto"
 )
 
 const (
 	_filepath = "filepath"
 	_suffix   = "/{" + _filepath + ":*}"
+
+	_SSE_HEARTBEAT = time.Second * 15
 )
 
 var (
 	logService       *svc.LogService
 	logClientService *svc.LogClientService
+	otlpService      *svc.OTLPLogsService
 )
 
 //go:embed wwwroot
@@ -45,13 +56,30 @@ func (o *customLogger) Printf(format string, args ...any) {
 }
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		runMigrate(os.Args[2:])
+		return
+	}
+
 	core.Init()
 	svc.Init()
+	xerr.FatalIfErr(svc.ServeSyslog())
 
 	logService = new(svc.LogService)
 	logClientService = new(svc.LogClientService)
+	otlpService = new(svc.OTLPLogsService)
 
-	grpcServer := grpc.NewServer()
+	grpcOptions := []grpc.ServerOption{
+		grpc.UnaryInterceptor(svc.UnaryAuthInterceptor),
+		grpc.StreamInterceptor(svc.StreamAuthInterceptor),
+	}
+	grpcTLSConfig, err := core.NewTLSConfig(core.ServiceConfigProvider, "h2")
+	xerr.FatalIfErr(err)
+	if grpcTLSConfig != nil {
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(grpcTLSConfig)))
+	}
+
+	grpcServer := grpc.NewServer(grpcOptions...)
 	go func() {
 		// Register to consul
 		err := registerConsulServiceInfo(core.ServiceConfigProvider)
@@ -59,6 +87,8 @@ func main() {
 
 		// Register GRPC service
 		logs.RegisterLogEntryServiceServer(grpcServer, logService)
+		logs.RegisterLogClientServiceServer(grpcServer, logClientService)
+		collogspb.RegisterLogsServiceServer(grpcServer, otlpService)
 
 		grpcServerListenAddr := core.ServiceConfigProvider.GetString("ListenAddr")
 		lis, err := net.Listen("tcp", grpcServerListenAddr)
@@ -70,11 +100,7 @@ func main() {
 		xerr.FatalIfErr(grpcServer.Serve(lis))
 	}()
 
-	router := router.New()
-	router.POST("/api/logs", getLogs)
-	router.GET("/api/listData", getListData)
-
-	serveEmbedFiles(router, _suffix, "wwwroot", staticFiles)
+	router := newRouter()
 
 	allowedOrigin := core.WebConfigProvider.GetString("CORS.AllowedOrigin")
 	allowedMethods := core.WebConfigProvider.GetString("CORS.AllowedMethods")
@@ -103,15 +129,84 @@ func main() {
 		}
 	}
 
+	if maxBodySize := core.WebConfigProvider.GetInt("Write.MaxBodySize"); maxBodySize > 0 {
+		_maxBodySize = maxBodySize
+	}
+
 	webServer := &fasthttp.Server{
-		Handler: corsMiddleware(router.Handler),
-		Logger:  &customLogger{},
+		Handler:            corsMiddleware(router.Handler),
+		Logger:             &customLogger{},
+		MaxRequestBodySize: _maxBodySize,
 	}
 
 	webServerListenAddr := core.WebConfigProvider.GetString("ListenAddr")
-	xlog.Infof("Web server listen on %s", webServerListenAddr)
+	webTLSConfig, err := core.NewTLSConfig(core.WebConfigProvider, "http/1.1")
+	xerr.FatalIfErr(err)
+	if webTLSConfig == nil {
+		xlog.Infof("Web server listen on %s", webServerListenAddr)
+		xerr.FatalIfErr(webServer.ListenAndServe(webServerListenAddr))
+		return
+	}
+
+	lis, err := net.Listen("tcp", webServerListenAddr)
+	xerr.FatalIfErr(err)
+	xlog.Infof("Web server listen on %s with TLS", webServerListenAddr)
+	xerr.FatalIfErr(webServer.Serve(tls.NewListener(lis, webTLSConfig)))
+}
 
-	xerr.FatalIfErr(webServer.ListenAndServe(webServerListenAddr))
+// newRouter routes the web API and the embedded GUI
+func newRouter() *router.Router {
+	r := router.New()
+	r.POST("/api/logs", getLogs)
+	r.POST("/api/write", writeLogs)
+	r.POST("/v1/logs", exportOTLPLogs)
+	r.POST("/loki/api/v1/push", pushLoki)
+	r.GET("/loki/api/v1/query_range", queryLokiRange)
+	r.GET("/loki/api/v1/labels", getLokiLabels)
+	r.GET("/loki/api/v1/label/{name}/values", getLokiLabelValues)
+	r.POST("/_bulk", bulkElastic)
+	r.PUT("/_bulk", bulkElastic)
+	r.POST("/{index}/_bulk", bulkElastic)
+	r.PUT("/{index}/_bulk", bulkElastic)
+	r.POST("/{index}/_doc", indexElasticDoc)
+	r.POST("/{index}/_doc/{id}", indexElasticDoc)
+	r.PUT("/{index}/_doc/{id}", indexElasticDoc)
+	r.POST("/{index}/_create/{id}", indexElasticDoc)
+	r.PUT("/{index}/_create/{id}", indexElasticDoc)
+	r.GET("/api/listData", getListData)
+	r.GET("/api/tail", tailLogs)
+	r.GET("/api/export", exportLogs)
+	r.POST("/api/histogram", getHistogram)
+	r.GET("/api/admin/clients/{id}", adminHandler(getClient))
+	r.POST("/api/admin/clients", adminHandler(createClient))
+	r.PUT("/api/admin/clients", adminHandler(updateClient))
+	r.DELETE("/api/admin/clients/{id}", adminHandler(deleteClient))
+	r.GET("/api/admin/clients/{id}/keys", adminHandler(getClientKeys))
+	r.POST("/api/admin/clients/{id}/keys", adminHandler(issueClientKey))
+	r.DELETE("/api/admin/clients/{id}/keys/{keyID}", adminHandler(revokeClientKey))
+	r.GET("/api/admin/usages", adminHandler(getClientUsages))
+	r.GET("/api/admin/alerts", adminHandler(getAlertRules))
+	r.PUT("/api/admin/alerts", adminHandler(saveAlertRule))
+	r.DELETE("/api/admin/alerts/{id}", adminHandler(deleteAlertRule))
+	r.GET("/api/admin/alerts/states", adminHandler(getAlertStates))
+	r.GET("/api/admin/retention", adminHandler(getRetentionReport))
+	r.POST("/api/admin/retention", adminHandler(runRetention))
+
+	serveEmbedFiles(r, _suffix, "wwwroot", staticFiles)
+
+	return r
+}
+
+// adminHandler serves a request only when it has the admin key, see svc.AuthorizeAdmin
+func adminHandler(next fasthttp.RequestHandler) fasthttp.RequestHandler {
+	return func(ctx *fasthttp.RequestCtx) {
+		err := svc.AuthorizeAdmin(rpcContext(ctx))
+		if err != nil {
+			writeStatusErr(err, ctx)
+			return
+		}
+		next(ctx)
+	}
 }
 
 func getLogs(ctx *fasthttp.RequestCtx) {
@@ -123,11 +218,16 @@ func getLogs(ctx *fasthttp.RequestCtx) {
 		return
 	}
 
-	if query.PageSize <= 0 || query.PageIndex < 1 || query.DBName == "" || query.TableName == "" {
+	if query.PageSize <= 0 || (query.PageIndex < 1 && query.Cursor == "") || ((query.DBName == "" || query.TableName == "") && query.ClientID == "") {
 		handleErr(err, ctx)
 		return
 	}
 
+	if _, err = search.Parse(query.Query); err != nil {
+		writeQueryErr(err, ctx)
+		return
+	}
+
 	rs, err := logService.GetLogEntries(context.Background(), query)
 	if !handleErr(err, ctx) {
 		jsonBytes, err := json.Marshal(rs)
@@ -137,6 +237,145 @@ func getLogs(ctx *fasthttp.RequestCtx) {
 	}
 }
 
+func getHistogram(ctx *fasthttp.RequestCtx) {
+	var query *logs.LogHistogramQuery
+	err := json.Unmarshal(ctx.Request.Body(), &query)
+	if handleErr(err, ctx) {
+		return
+	}
+
+	if query == nil || query.Query == nil || query.Interval <= 0 || query.Query.DBName == "" || query.Query.TableName == "" {
+		ctx.SetStatusCode(400)
+		return
+	}
+
+	if _, err = search.Parse(query.Query.Query); err != nil {
+		writeQueryErr(err, ctx)
+		return
+	}
+
+	rs, err := logService.GetLogHistogram(context.Background(), query)
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+// tailLogs streams newly written entries as server-sent events
+func tailLogs(ctx *fasthttp.RequestCtx) {
+	// Copy values, they are used after the handler returns
+	query := &logs.TailLogEntriesQuery{
+		ClientID: string(ctx.FormValue("client")),
+		Level:    logs.LogLevel(ctx.QueryArgs().GetUintOrZero("level")),
+		User:     string(ctx.FormValue("user")),
+		TraceNo:  string(ctx.FormValue("traceNo")),
+		Message:  string(ctx.FormValue("message")),
+	}
+
+	subscriber, err := logService.SubscribeLogEntries(query)
+	if handleErr(err, ctx) {
+		return
+	}
+
+	ctx.SetContentType("text/event-stream")
+	ctx.Response.Header.Set("Cache-Control", "no-cache")
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer logService.UnsubscribeLogEntries(subscriber)
+
+		heartbeat := time.NewTicker(_SSE_HEARTBEAT)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case x, ok := <-subscriber.C:
+				if !ok {
+					if subscriber.Dropped() {
+						w.WriteString("event: dropped\ndata: subscriber dropped for not keeping up\n\n")
+						w.Flush()
+					}
+					return
+				}
+				jsonBytes, err := json.Marshal(x)
+				if xerr.LogError(err) {
+					continue
+				}
+				w.WriteString("data: ")
+				w.Write(jsonBytes)
+				w.WriteString("\n\n")
+			case <-heartbeat.C:
+				w.WriteString(": ping\n\n")
+			}
+
+			// Fails when the viewer is gone
+			if w.Flush() != nil {
+				return
+			}
+		}
+	})
+}
+
+// exportLogs streams every entry matching the query as a file, see svc.Export
+func exportLogs(ctx *fasthttp.RequestCtx) {
+	// Copy values, they are used after the handler returns
+	args := ctx.QueryArgs()
+	level := logs.LogLevel(-1) // All levels
+	if n, err := args.GetUint("level"); err == nil {
+		level = logs.LogLevel(n)
+	}
+	in := &logs.ExportLogEntriesQuery{
+		Query: &logs.LogEntriesQuery{
+			ClientID:   string(args.Peek("client")),
+			DBName:     string(args.Peek("db")),
+			TableName:  string(args.Peek("table")),
+			StartTime:  string(args.Peek("startTime")),
+			EndTime:    string(args.Peek("endTime")),
+			Level:      level,
+			User:       string(args.Peek("user")),
+			TraceNo:    string(args.Peek("traceNo")),
+			Message:    string(args.Peek("message")),
+			Error:      string(args.Peek("error")),
+			StackTrace: string(args.Peek("stackTrace")),
+			Query:      string(args.Peek("query")),
+			Flags:      int64(args.GetUintOrZero("flags")),
+		},
+		Format:      string(args.Peek("format")),
+		Compression: string(args.Peek("compression")),
+		MaxRows:     int64(args.GetUintOrZero("maxRows")),
+	}
+
+	err := svc.ValidateExport(in)
+	if err != nil {
+		ctx.SetStatusCode(400)
+		ctx.SetBodyString(err.Error())
+		return
+	}
+
+	name, contentType := "logs", "application/x-ndjson"
+	switch in.Format {
+	case svc.EXPORT_CSV:
+		name, contentType = name+".csv", "text/csv"
+	case svc.EXPORT_PARQUET:
+		name, contentType = name+".parquet", "application/vnd.apache.parquet"
+	default:
+		name += ".ndjson"
+	}
+	switch in.Compression {
+	case svc.COMPRESSION_GZIP:
+		name, contentType = name+".gz", "application/gzip"
+	case svc.COMPRESSION_ZSTD:
+		name, contentType = name+".zst", "application/zstd"
+	}
+
+	ctx.SetContentType(contentType)
+	ctx.Response.Header.Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	ctx.SetBodyStreamWriter(func(w *bufio.Writer) {
+		// The status is sent already, a failed export ends with a truncated file
+		_, err := svc.Export(in, w)
+		if !xerr.LogError(err) {
+			w.Flush()
+		}
+	})
+}
+
 type GetListDataResult struct {
 	Client    string
 	Database  string
@@ -189,6 +428,178 @@ func getListData(ctx *fasthttp.RequestCtx) {
 	}
 }
 
+func getClient(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.GetClient(context.Background(), &logs.LogClientQuery{
+		ID: ctx.UserValue("id").(string),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+func createClient(ctx *fasthttp.RequestCtx) {
+	var client *logs.LogClient
+	err := json.Unmarshal(ctx.Request.Body(), &client)
+	if handleErr(err, ctx) {
+		return
+	}
+
+	rs, err := logClientService.CreateClient(context.Background(), client)
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+// updateClient changes the fields in the body of the client with its ID, fields left out are kept
+func updateClient(ctx *fasthttp.RequestCtx) {
+	var in struct{ ID string }
+	err := json.Unmarshal(ctx.Request.Body(), &in)
+	if handleErr(err, ctx) {
+		return
+	}
+
+	rs, err := logClientService.GetClient(context.Background(), &logs.LogClientQuery{ID: in.ID})
+	if handleErr(err, ctx) {
+		return
+	} else if rs.LogClient == nil {
+		writeJson(rs, ctx)
+		return
+	}
+
+	client, err := mergeClient(rs.LogClient, ctx.Request.Body())
+	if handleErr(err, ctx) {
+		return
+	}
+
+	rs, err = logClientService.UpdateClient(context.Background(), client)
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+// mergeClient returns a copy of the stored client with the fields of a JSON body, the stored client may be cached so it is not changed
+func mergeClient(stored *logs.LogClient, body []byte) (*logs.LogClient, error) {
+	r := proto.Clone(stored).(*logs.LogClient)
+	err := json.Unmarshal(body, r)
+	if err != nil {
+		return nil, xerr.WithStack(err)
+	}
+	return r, nil
+}
+
+func deleteClient(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.DeleteClient(context.Background(), &logs.LogClientQuery{
+		ID: ctx.UserValue("id").(string),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+func getClientKeys(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.GetClientKeys(context.Background(), &logs.LogClientQuery{
+		ID: ctx.UserValue("id").(string),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+// issueClientKey takes an optional IssueLogClientKeyCommand body, the secret in the response is not retrievable later
+func issueClientKey(ctx *fasthttp.RequestCtx) {
+	cmd := new(logs.IssueLogClientKeyCommand)
+	if body := ctx.Request.Body(); len(body) > 0 {
+		err := json.Unmarshal(body, cmd)
+		if handleErr(err, ctx) {
+			return
+		}
+	}
+	cmd.ClientID = ctx.UserValue("id").(string)
+
+	rs, err := logClientService.IssueClientKey(context.Background(), cmd)
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+func revokeClientKey(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.RevokeClientKey(context.Background(), &logs.RevokeLogClientKeyCommand{
+		ClientID: ctx.UserValue("id").(string),
+		KeyID:    ctx.UserValue("keyID").(string),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+// getClientUsages returns today's usage of ?client=, or of all clients
+func getClientUsages(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.GetClientUsages(context.Background(), &logs.LogClientQuery{
+		ID: string(ctx.QueryArgs().Peek("client")),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+// getAlertRules returns the rules of ?client=, or of all clients
+func getAlertRules(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.GetAlertRules(context.Background(), &logs.AlertRulesQuery{
+		ClientID: string(ctx.QueryArgs().Peek("client")),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+func saveAlertRule(ctx *fasthttp.RequestCtx) {
+	var rule *logs.AlertRule
+	err := json.Unmarshal(ctx.Request.Body(), &rule)
+	if handleErr(err, ctx) {
+		return
+	}
+
+	rs, err := logClientService.SaveAlertRule(context.Background(), rule)
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+func deleteAlertRule(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.DeleteAlertRule(context.Background(), &logs.AlertRuleQuery{
+		ID: ctx.UserValue("id").(string),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+func getAlertStates(ctx *fasthttp.RequestCtx) {
+	rs, err := logClientService.GetAlertStates(context.Background(), &logs.AlertRulesQuery{
+		ClientID: string(ctx.QueryArgs().Peek("client")),
+	})
+	if !handleErr(err, ctx) {
+		writeJson(rs, ctx)
+	}
+}
+
+func getRetentionReport(ctx *fasthttp.RequestCtx) {
+	writeJson(svc.LastRetentionReport(), ctx)
+}
+
+// runRetention runs the retention janitor now, see retentionDryRun
+func runRetention(ctx *fasthttp.RequestCtx) {
+	writeJson(svc.RunRetention(retentionDryRun(ctx.QueryArgs())), ctx)
+}
+
+// retentionDryRun is true unless dropping is asked for by dryRun=false or confirm=true
+func retentionDryRun(args *fasthttp.Args) bool {
+	if args.GetBool("confirm") {
+		return false
+	}
+	return !args.Has("dryRun") || args.GetBool("dryRun")
+}
+
 func handleErr(err error, ctx *fasthttp.RequestCtx) bool {
 	if err != nil {
 		ctx.SetStatusCode(400)
@@ -197,6 +608,27 @@ func handleErr(err error, ctx *fasthttp.RequestCtx) bool {
 	return false
 }
 
+// writeQueryErr responds 400 with the message and position of a search query error
+func writeQueryErr(err error, ctx *fasthttp.RequestCtx) {
+	rs := struct {
+		Message  string
+		Position int `json:",omitempty"`
+	}{Message: err.Error()}
+	if parseErr, ok := err.(*search.ParseError); ok {
+		rs.Position = parseErr.Pos
+	}
+
+	writeJson(rs, ctx)
+	ctx.SetStatusCode(fasthttp.StatusBadRequest)
+}
+
+func writeJson(v any, ctx *fasthttp.RequestCtx) {
+	jsonBytes, err := json.Marshal(v)
+	if !handleErr(err, ctx) {
+		writeJsonBytes(jsonBytes, ctx)
+	}
+}
+
 func writeJsonBytes(jsonBytes []byte, ctx *fasthttp.RequestCtx) {
 	ctx.Response.Header.Set(xhttp.HEADER_CTYPE, xhttp.CTYPE_JSON)
 	ctx.Write(jsonBytes)
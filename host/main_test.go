@@ -0,0 +1,88 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/valyala/fasthttp"
+)
+
+// serve runs a request through the router without a connection
+func serve(method, uri, authorization string) *fasthttp.Response {
+	req := fasthttp.AcquireRequest()
+	defer fasthttp.ReleaseRequest(req)
+	req.Header.SetMethod(method)
+	req.SetRequestURI(uri)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+
+	ctx := new(fasthttp.RequestCtx)
+	ctx.Init(req, nil, nil)
+	newRouter().Handler(ctx)
+	return &ctx.Response
+}
+
+func TestAdminRoutesRequireAdminKey(t *testing.T) {
+	routes := [][2]string{
+		{"GET", "/api/admin/clients/c1"},
+		{"POST", "/api/admin/clients"},
+		{"PUT", "/api/admin/clients"},
+		{"DELETE", "/api/admin/clients/c1"},
+		{"GET", "/api/admin/clients/c1/keys"},
+		{"POST", "/api/admin/clients/c1/keys"},
+		{"DELETE", "/api/admin/clients/c1/keys/k1"},
+		{"GET", "/api/admin/usages"},
+		{"GET", "/api/admin/alerts"},
+		{"PUT", "/api/admin/alerts"},
+		{"DELETE", "/api/admin/alerts/r1"},
+		{"GET", "/api/admin/alerts/states"},
+		{"GET", "/api/admin/retention"},
+		{"POST", "/api/admin/retention?confirm=true"},
+	}
+	for _, x := range routes {
+		for _, authorization := range []string{"", "Bearer guess"} {
+			if rs := serve(x[0], x[1], authorization); rs.StatusCode() != fasthttp.StatusUnauthorized {
+				t.Errorf("%s %s with '%s' = %d, want 401", x[0], x[1], authorization, rs.StatusCode())
+			}
+		}
+	}
+}
+
+func TestRetentionDryRun(t *testing.T) {
+	cases := map[string]bool{
+		"":                         true,
+		"dryRun=true":              true,
+		"dryRun=1":                 true,
+		"dryRun=false":             false,
+		"dryRun=0":                 false,
+		"confirm=true":             false,
+		"confirm=false":            true,
+		"dryRun=true&confirm=true": false,
+	}
+	for query, want := range cases {
+		args := new(fasthttp.Args)
+		args.Parse(query)
+		if got := retentionDryRun(args); got != want {
+			t.Errorf("retentionDryRun(%s) = %v, want %v", query, got, want)
+		}
+	}
+}
+
+func TestMergeClient(t *testing.T) {
+	stored := &logs.LogClient{ID: "c1", DBPolicy: 2, Level: logs.LogLevel_Warning, RetentionDays: 30, RateLimit: 100, DailyQuota: 5000}
+
+	r, err := mergeClient(stored, []byte(`{"ID":"c1","RetentionDays":7,"RateLimit":0}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.DBPolicy != 2 || r.Level != logs.LogLevel_Warning || r.DailyQuota != 5000 {
+		t.Errorf("fields left out changed: %v", r)
+	}
+	if r.RetentionDays != 7 || r.RateLimit != 0 {
+		t.Errorf("fields in the body not applied: %v", r)
+	}
+	if stored.RetentionDays != 30 || stored.RateLimit != 100 {
+		t.Errorf("stored client changed: %v", stored)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xlog"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/dal"
+	"github.com/DreamvatLab/logs/host/migrate"
+)
+
+// runMigrate copies partitions between providers, e.g. host migrate -from mongodb -to clickhouse -checkpoint migrate.json.
+// Both providers connect by their connection strings in service.json.
+func runMigrate(args []string) {
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	from := flags.String("from", "", "source provider: mongodb, clickhouse, mysql or sqlite")
+	to := flags.String("to", "", "destination provider: mongodb, clickhouse, mysql or sqlite")
+	clients := flags.String("clients", "", "comma separated client ids, all clients when empty")
+	batchSize := flags.Int("batch", 1000, "entries read and written at a time")
+	parallelism := flags.Int("parallel", 4, "partitions copied at the same time")
+	checkpoint := flags.String("checkpoint", "migrate.json", "progress file to resume from, empty disables resuming")
+	verify := flags.Bool("verify", true, "compare entry counts of every partition after copying")
+	flags.Parse(args)
+
+	if *from == "" || *to == "" || *from == *to {
+		fmt.Fprintln(os.Stderr, "-from and -to must be two different providers")
+		flags.Usage()
+		os.Exit(2)
+	}
+
+	core.Init()
+
+	var clientIDs []string
+	if *clients != "" {
+		clientIDs = strings.Split(*clients, ",")
+	} else {
+		all, err := dal.NewClientDAL().GetClients(nil)
+		xerr.FatalIfErr(err)
+		for _, x := range all {
+			clientIDs = append(clientIDs, x.ID)
+		}
+	}
+
+	migrator := migrate.NewMigrator(dal.NewLogDALFor(*from), dal.NewLogDALFor(*to), migrate.Options{
+		ClientIDs:   clientIDs,
+		BatchSize:   *batchSize,
+		Parallelism: *parallelism,
+		Checkpoint:  *checkpoint,
+		Verify:      *verify,
+	})
+	report, err := migrator.Run()
+	if report != nil {
+		xlog.Infof("Migrated %d entries of %d partitions, %d partitions done by a previous run", report.Copied, len(report.Partitions), report.Skipped)
+	}
+	xerr.FatalIfErr(err)
+	if len(report.Mismatches) > 0 {
+		xlog.Fatalf("%d partitions have different counts in source and destination", len(report.Mismatches))
+	}
+}
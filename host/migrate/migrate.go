@@ -0,0 +1,287 @@
+// Package migrate copies the partitions of clients from one log store to another, keeping entry ids and partition names.
+package migrate
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xlog"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/dal"
+)
+
+type Options struct {
+	ClientIDs []string
+	// Entries read and written at a time
+	BatchSize int
+	// Partitions copied at the same time
+	Parallelism int
+	// File recording the progress of every partition, a rerun resumes from it. Empty disables resuming.
+	Checkpoint string
+	// Compare the entry counts of source and destination partitions after copying
+	Verify bool
+}
+
+// Partition is the state of a partition in the checkpoint and the report
+type Partition struct {
+	ClientID  string
+	DBName    string
+	TableName string
+	Copied    int64
+	// Keyset cursor of the last copied entry, see core.Cursor
+	Cursor string
+	Done   bool
+	// Set by verification
+	SourceCount int64 `json:",omitempty"`
+	DestCount   int64 `json:",omitempty"`
+}
+
+func (o *Partition) key() string {
+	return o.DBName + "/" + o.TableName
+}
+
+type Report struct {
+	Partitions []*Partition
+	Copied     int64
+	// Partitions done by a previous run
+	Skipped int
+	// Partitions whose counts differ, filled when Options.Verify is set
+	Mismatches []*Partition
+}
+
+type Migrator struct {
+	source  dal.ILogDAL
+	dest    dal.ILogDAL
+	options Options
+	locker  sync.Mutex
+	states  map[string]*Partition
+}
+
+// NewMigrator creates a migrator copying from source to dest, zero options get defaults
+func NewMigrator(source, dest dal.ILogDAL, options Options) *Migrator {
+	if options.BatchSize <= 0 {
+		options.BatchSize = 1000
+	}
+	if options.Parallelism <= 0 {
+		options.Parallelism = 4
+	}
+	return &Migrator{
+		source:  source,
+		dest:    dest,
+		options: options,
+		states:  make(map[string]*Partition),
+	}
+}
+
+// Run copies all partitions of the clients. Entries are written before their cursor is checkpointed,
+// so a partition interrupted by a crash may get its last batch written twice when resumed.
+// Failed partitions do not stop the others, their errors are joined.
+func (o *Migrator) Run() (*Report, error) {
+	err := o.loadCheckpoint()
+	if err != nil {
+		return nil, err
+	}
+
+	partitions, err := o.listPartitions()
+	if err != nil {
+		return nil, err
+	}
+
+	r := &Report{Partitions: partitions}
+	errs := make([]error, len(partitions))
+	semaphore := make(chan struct{}, o.options.Parallelism)
+	wg := new(sync.WaitGroup)
+	for i, x := range partitions {
+		if x.Done {
+			r.Skipped++
+			continue
+		}
+
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			errs[i] = o.copyPartition(x)
+			if errs[i] != nil {
+				errs[i] = xerr.Errorf("%s: %v", x.key(), errs[i])
+			} else {
+				xlog.Infof("Migrated %s, %d entries", x.key(), x.Copied)
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, x := range partitions {
+		r.Copied += x.Copied
+	}
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return r, err
+	}
+
+	if o.options.Verify {
+		r.Mismatches, err = o.verify(partitions)
+	}
+	return r, err
+}
+
+// listPartitions lists the partitions of the clients in the source, with their states in the checkpoint
+func (o *Migrator) listPartitions() ([]*Partition, error) {
+	var r []*Partition
+	for _, clientID := range o.options.ClientIDs {
+		databases, err := o.source.GetDatabases(clientID)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, dbName := range databases {
+			tables, err := o.source.GetTables(dbName)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, tableName := range tables {
+				x := &Partition{ClientID: clientID, DBName: dbName, TableName: tableName}
+				if state, ok := o.states[x.key()]; ok {
+					x = state
+				} else {
+					o.states[x.key()] = x
+				}
+				r = append(r, x)
+			}
+		}
+	}
+	return r, nil
+}
+
+// copyPartition copies entries newest first in batches, continuing after the cursor of the partition
+func (o *Migrator) copyPartition(partition *Partition) error {
+	query := &logs.LogEntriesQuery{
+		DBName:    partition.DBName,
+		TableName: partition.TableName,
+		Level:     -1, // All levels
+		PageIndex: 1,
+		PageSize:  int32(o.options.BatchSize),
+	}
+
+	for {
+		o.locker.Lock()
+		query.Cursor = partition.Cursor
+		o.locker.Unlock()
+
+		list, _, err := o.source.GetLogEntries(query)
+		if err != nil {
+			return err
+		}
+
+		if len(list) > 0 {
+			err = o.dest.InsertLogEntries(partition.DBName, partition.TableName, list)
+			if err != nil {
+				return err
+			}
+		}
+
+		o.locker.Lock()
+		partition.Copied += int64(len(list))
+		if len(list) > 0 {
+			last := list[len(list)-1]
+			partition.Cursor = (&core.Cursor{CreatedOnUtc: last.CreatedOnUtc, ID: last.ID}).String()
+		}
+		partition.Done = len(list) < o.options.BatchSize
+		err = o.saveCheckpoint()
+		o.locker.Unlock()
+		if err != nil {
+			return err
+		}
+
+		if partition.Done {
+			return nil
+		}
+	}
+}
+
+// verify compares the total counts of every partition in source and destination
+func (o *Migrator) verify(partitions []*Partition) ([]*Partition, error) {
+	var r []*Partition
+	for _, x := range partitions {
+		query := &logs.LogEntriesQuery{
+			DBName:    x.DBName,
+			TableName: x.TableName,
+			Level:     -1,
+			PageIndex: 1,
+			PageSize:  1,
+		}
+
+		var err error
+		_, x.SourceCount, err = o.source.GetLogEntries(query)
+		if err != nil {
+			return nil, err
+		}
+		_, x.DestCount, err = o.dest.GetLogEntries(query)
+		if err != nil {
+			return nil, err
+		}
+
+		if x.SourceCount != x.DestCount {
+			xlog.Warnf("%s has %d entries in source but %d in destination", x.key(), x.SourceCount, x.DestCount)
+			r = append(r, x)
+		}
+	}
+	return r, nil
+}
+
+func (o *Migrator) loadCheckpoint() error {
+	if o.options.Checkpoint == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(o.options.Checkpoint)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	var partitions []*Partition
+	err = json.Unmarshal(data, &partitions)
+	if err != nil {
+		return xerr.Errorf("invalid checkpoint file '%s': %v", o.options.Checkpoint, err)
+	}
+	for _, x := range partitions {
+		o.states[x.key()] = x
+	}
+	return nil
+}
+
+// saveCheckpoint writes the states of all partitions, replacing the file at once so that a crash never leaves it partly
+// written. The locker must be held.
+func (o *Migrator) saveCheckpoint() error {
+	if o.options.Checkpoint == "" {
+		return nil
+	}
+
+	partitions := make([]*Partition, 0, len(o.states))
+	for _, x := range o.states {
+		partitions = append(partitions, x)
+	}
+	data, err := json.MarshalIndent(partitions, "", "  ")
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	tmp := o.options.Checkpoint + ".tmp"
+	err = os.WriteFile(tmp, data, 0644)
+	if err == nil {
+		err = os.Rename(tmp, o.options.Checkpoint)
+	}
+	return xerr.WithStack(err)
+}
@@ -0,0 +1,134 @@
+package migrate
+
+import (
+	"errors"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/dal/memory"
+)
+
+// failingDAL fails inserting after a number of successful inserts
+type failingDAL struct {
+	*memory.MemoryDAL
+	inserts int
+}
+
+func (o *failingDAL) InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error {
+	if o.inserts == 0 {
+		return errors.New("connection lost")
+	}
+	o.inserts--
+	return o.MemoryDAL.InsertLogEntries(dbName, tableName, logEntries)
+}
+
+// newSource stores 4 entries in LOG_c1/2024_02, 5 in LOG_c1/2024_01 and 1 of another client, timestamps are shared in pairs
+func newSource(t *testing.T) *memory.MemoryDAL {
+	r := new(memory.MemoryDAL)
+	seed := func(dbName, tableName string, count int) {
+		for i := range count {
+			err := r.InsertLogEntry(dbName, tableName, &logs.LogEntry{
+				ID:           tableName + "-" + strconv.Itoa(i),
+				CreatedOnUtc: 1700000000000 + int64(i/2),
+				Message:      "entry",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	seed("LOG_c1", "2024_02", 4)
+	seed("LOG_c1", "2024_01", 5)
+	seed("LOG_c2", "2024_01", 1)
+	return r
+}
+
+// ids returns the sorted ids of a table
+func ids(t *testing.T, dal *memory.MemoryDAL, dbName, tableName string) []string {
+	t.Helper()
+	list, _, err := dal.GetLogEntries(&logs.LogEntriesQuery{DBName: dbName, TableName: tableName, Level: -1, PageIndex: 1, PageSize: 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := make([]string, 0, len(list))
+	for _, x := range list {
+		r = append(r, x.ID)
+	}
+	slices.Sort(r)
+	return r
+}
+
+func TestRunResumes(t *testing.T) {
+	source := newSource(t)
+	dest := &failingDAL{MemoryDAL: new(memory.MemoryDAL), inserts: 3}
+	options := Options{
+		ClientIDs:   []string{"c1"},
+		BatchSize:   2,
+		Parallelism: 1,
+		Checkpoint:  filepath.Join(t.TempDir(), "checkpoint.json"),
+		Verify:      true,
+	}
+
+	// 2024_02 is copied by 2 inserts, 2024_01 fails at its second batch
+	r, err := NewMigrator(source, dest, options).Run()
+	if err == nil {
+		t.Fatal("Run = nil, want the insert error")
+	}
+	if r.Copied != 6 || len(r.Partitions) != 2 || !r.Partitions[0].Done || r.Partitions[1].Done || r.Partitions[1].Cursor == "" {
+		t.Fatalf("report = %+v %+v, want 2024_02 done and 2024_01 stopped after 2 entries", r.Partitions[0], r.Partitions[1])
+	}
+
+	dest.inserts = 100
+	r, err = NewMigrator(source, dest, options).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Skipped != 1 || r.Copied != 9 || len(r.Mismatches) != 0 {
+		t.Errorf("report = %+v, want 1 skipped, 9 copied and no mismatches", r)
+	}
+	for _, x := range r.Partitions {
+		if !x.Done || x.SourceCount != x.DestCount {
+			t.Errorf("partition = %+v, want done with equal counts", x)
+		}
+	}
+
+	// Nothing is copied twice and ids are kept
+	for _, tableName := range []string{"2024_02", "2024_01"} {
+		if got, want := ids(t, dest.MemoryDAL, "LOG_c1", tableName), ids(t, source, "LOG_c1", tableName); !reflect.DeepEqual(got, want) {
+			t.Errorf("%s ids = %v, want %v", tableName, got, want)
+		}
+	}
+	if databases, _ := dest.GetDatabases("c2"); len(databases) != 0 {
+		t.Errorf("databases of c2 = %v, want none", databases)
+	}
+
+	// A finished run is not repeated
+	r, err = NewMigrator(source, dest, options).Run()
+	if err != nil || r.Skipped != 2 || len(r.Mismatches) != 0 {
+		t.Errorf("rerun = %+v %v, want everything skipped", r, err)
+	}
+}
+
+func TestRunVerify(t *testing.T) {
+	source := newSource(t)
+	dest := new(memory.MemoryDAL)
+	err := dest.InsertLogEntry("LOG_c1", "2024_01", &logs.LogEntry{ID: "extra", CreatedOnUtc: 1700000000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := NewMigrator(source, dest, Options{ClientIDs: []string{"c1"}, Verify: true}).Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Copied != 9 || len(r.Mismatches) != 1 {
+		t.Fatalf("report = %+v, want 9 copied and 1 mismatch", r)
+	}
+	if x := r.Mismatches[0]; x.TableName != "2024_01" || x.SourceCount != 5 || x.DestCount != 6 {
+		t.Errorf("mismatch = %+v, want 2024_01 with 5 and 6 entries", x)
+	}
+}
@@ -0,0 +1,63 @@
+package search
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+var (
+	_bsonOps = map[string]string{
+		"=":  "$eq",
+		"!=": "$ne",
+		">":  "$gt",
+		">=": "$gte",
+		"<":  "$lt",
+		"<=": "$lte",
+	}
+)
+
+// ToBSON compiles a node to a MongoDB filter
+func ToBSON(node Node) bson.M {
+	switch x := node.(type) {
+	case *AndNode:
+		return bson.M{"$and": bson.A{ToBSON(x.Left), ToBSON(x.Right)}}
+	case *OrNode:
+		return bson.M{"$or": bson.A{ToBSON(x.Left), ToBSON(x.Right)}}
+	case *NotNode:
+		// $not is not allowed at top level, $nor also matches missing fields
+		return bson.M{"$nor": bson.A{ToBSON(x.Node)}}
+	case *TermNode:
+		return bsonTerm(x)
+	default:
+		return bson.M{}
+	}
+}
+
+func bsonTerm(term *TermNode) bson.M {
+	var key string
+	switch term.Field {
+	case "ID":
+		key = "id"
+	case "Labels":
+		key = "labels." + term.LabelKey
+	default:
+		key = strings.ToLower(term.Field)
+	}
+
+	switch term.Match {
+	case MatchCompare:
+		level, _ := strconv.Atoi(term.Value)
+		return bson.M{key: bson.M{_bsonOps[term.Op]: level}}
+	case MatchWildcard:
+		pattern := strings.ReplaceAll(regexp.QuoteMeta(term.Value), `\*`, ".*")
+		return bson.M{key: bson.M{"$regex": "^" + pattern + "$", "$options": "i"}}
+	case MatchContains:
+		return bson.M{key: bson.M{"$regex": regexp.QuoteMeta(term.Value), "$options": "i"}}
+	default:
+		// Case insensitive like the other text terms, an anchored regex instead of equality
+		return bson.M{key: bson.M{"$regex": "^" + regexp.QuoteMeta(term.Value) + "$", "$options": "i"}}
+	}
+}
@@ -0,0 +1,122 @@
+package search
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func mustParse(t *testing.T, query string) Node {
+	t.Helper()
+	node, err := Parse(query)
+	if err != nil {
+		t.Fatalf("Parse(%q) = %v", query, err)
+	}
+	return node
+}
+
+func TestToSQL(t *testing.T) {
+	cases := []struct {
+		query   string
+		dialect Dialect
+		want    string
+		args    []any
+	}{
+		{"user:Bob", DialectMySQL, "LOWER(`User`) = ?", []any{"bob"}},
+		{"user:Bob", DialectClickHouse, "LOWER(`User`) = ?", []any{"bob"}},
+		{"user:b_b%*", DialectMySQL, "LOWER(`User`) LIKE ?", []any{`b\_b\%%`}},
+		{"user:b_b%*", DialectSQLite, "LOWER(`User`) LIKE ? ESCAPE '\\'", []any{`b\_b\%%`}},
+		{`user:*a\b*`, DialectClickHouse, "LOWER(`User`) LIKE ?", []any{`%a\\b%`}},
+		{`"50%"`, DialectClickHouse, "LOWER(`Message`) LIKE ?", []any{`%50\%%`}},
+		{"Reset", DialectSQLite, "LOWER(`Message`) LIKE ? ESCAPE '\\'", []any{"%reset%"}},
+		{"level>=error", DialectMySQL, "`Level` >= ?", []any{4}},
+		{"labels.env:Prod", DialectMySQL, "LOWER(JSON_UNQUOTE(JSON_EXTRACT(`Labels`, ?))) = ?", []any{`$."env"`, "prod"}},
+		{"labels.env:Prod", DialectClickHouse, "LOWER(`Labels`[?]) = ?", []any{"env", "prod"}},
+		{"labels.env:Prod", DialectSQLite, "LOWER(json_extract(`Labels`, ?)) = ?", []any{`$."env"`, "prod"}},
+		// Negated nullable columns are compared as empty strings
+		{"-labels.env:prod", DialectSQLite, "NOT (LOWER(IFNULL(json_extract(`Labels`, ?), '')) = ?)", []any{`$."env"`, "prod"}},
+		{"-user:bob", DialectClickHouse, "NOT (LOWER(`User`) = ?)", []any{"bob"}},
+		{"a OR b c", DialectMySQL, "(LOWER(`Message`) LIKE ? OR (LOWER(`Message`) LIKE ? AND LOWER(`Message`) LIKE ?))", []any{"%a%", "%b%", "%c%"}},
+	}
+	for _, x := range cases {
+		t.Run(fmt.Sprintf("%s/%d", x.query, x.dialect), func(t *testing.T) {
+			got, args := ToSQL(mustParse(t, x.query), x.dialect)
+			if got != x.want || !reflect.DeepEqual(args, x.args) {
+				t.Errorf("ToSQL(%q) = %s %v, want %s %v", x.query, got, args, x.want, x.args)
+			}
+		})
+	}
+}
+
+func TestToBSON(t *testing.T) {
+	cases := []struct {
+		query string
+		want  bson.M
+	}{
+		{"id:E05", bson.M{"id": bson.M{"$regex": "^E05$", "$options": "i"}}},
+		{"user:b.b", bson.M{"user": bson.M{"$regex": `^b\.b$`, "$options": "i"}}},
+		{"user:b.b*", bson.M{"user": bson.M{"$regex": `^b\.b.*$`, "$options": "i"}}},
+		{`"a+b"`, bson.M{"message": bson.M{"$regex": `a\+b`, "$options": "i"}}},
+		{"labels.env:prod", bson.M{"labels.env": bson.M{"$regex": "^prod$", "$options": "i"}}},
+		{"level<warn", bson.M{"level": bson.M{"$lt": 3}}},
+		{"-trace:x", bson.M{"$nor": bson.A{bson.M{"traceno": bson.M{"$regex": "^x$", "$options": "i"}}}}},
+		{"a OR level=0", bson.M{"$or": bson.A{
+			bson.M{"message": bson.M{"$regex": "a", "$options": "i"}},
+			bson.M{"level": bson.M{"$eq": 0}},
+		}}},
+	}
+	for _, x := range cases {
+		t.Run(x.query, func(t *testing.T) {
+			if got := ToBSON(mustParse(t, x.query)); !reflect.DeepEqual(got, x.want) {
+				t.Errorf("ToBSON(%q) = %v, want %v", x.query, got, x.want)
+			}
+		})
+	}
+}
+
+func TestMatch(t *testing.T) {
+	entry := &logs.LogEntry{
+		ID:      "e01",
+		User:    "Bob_Admin",
+		TraceNo: "T100",
+		Message: "Disk 90% full",
+		Level:   logs.LogLevel_Warning,
+		Labels:  map[string]string{"env": "Prod"},
+	}
+	cases := []struct {
+		query string
+		want  bool
+	}{
+		{"", true},
+		{"user:bob_admin", true},
+		{"user:bob", false},
+		{"user:BOB*", true},
+		{"user:*admin", true},
+		{"user:b*_*n", true},
+		{"user:b*x*n", false},
+		{"user:bob%admin", false},
+		{"id:E01", true},
+		{`"90% FULL"`, true},
+		{`"90 %"`, false},
+		{`message:"disk 90% full"`, true},
+		{`message:"disk*"`, false},
+		{"labels.env:prod", true},
+		{"labels.region:eu", false},
+		{"-labels.region:eu", true},
+		{"level>=warn", true},
+		{"level>warn", false},
+		{"disk OR nothing", true},
+		{"disk -trace:t100", false},
+		{"(nothing OR disk) level:3", true},
+	}
+	for _, x := range cases {
+		t.Run(x.query, func(t *testing.T) {
+			if got := Match(mustParse(t, x.query), entry); got != x.want {
+				t.Errorf("Match(%q) = %v, want %v", x.query, got, x.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package search
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	_TOKEN_EOF tokenKind = iota
+	_TOKEN_LPAREN
+	_TOKEN_RPAREN
+	_TOKEN_AND
+	_TOKEN_OR
+	_TOKEN_NOT
+	_TOKEN_MINUS
+	_TOKEN_TERM
+)
+
+var (
+	// Longer operators first
+	_operators = []string{">=", "<=", "!=", ":", "=", ">", "<"}
+)
+
+type token struct {
+	kind tokenKind
+	// Byte position, starts from 1
+	pos  int
+	text string
+	// Empty for bare words and phrases
+	field  string
+	op     string
+	value  string
+	quoted bool
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isFieldChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-'
+}
+
+func lex(s string) ([]*token, error) {
+	var r []*token
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case isSpace(c):
+			i++
+		case c == '(':
+			r = append(r, &token{kind: _TOKEN_LPAREN, pos: i + 1, text: "("})
+			i++
+		case c == ')':
+			r = append(r, &token{kind: _TOKEN_RPAREN, pos: i + 1, text: ")"})
+			i++
+		case c == '-' && i+1 < len(s) && !isSpace(s[i+1]):
+			r = append(r, &token{kind: _TOKEN_MINUS, pos: i + 1, text: "-"})
+			i++
+		case c == '"':
+			value, end, err := readQuoted(s, i)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, &token{kind: _TOKEN_TERM, pos: i + 1, text: s[i:end], value: value, quoted: true})
+			i = end
+		default:
+			t, end, err := readTerm(s, i)
+			if err != nil {
+				return nil, err
+			}
+			r = append(r, t)
+			i = end
+		}
+	}
+
+	r = append(r, &token{kind: _TOKEN_EOF, pos: len(s) + 1})
+	return r, nil
+}
+
+// readTerm reads a keyword, a bare word or a field term starting at i
+func readTerm(s string, i int) (*token, int, error) {
+	j := i
+	for j < len(s) && isFieldChar(s[j]) {
+		j++
+	}
+
+	if j > i {
+		for _, op := range _operators {
+			if !strings.HasPrefix(s[j:], op) {
+				continue
+			}
+
+			r := &token{kind: _TOKEN_TERM, pos: i + 1, field: s[i:j], op: op}
+			k := j + len(op)
+			var end int
+			if k < len(s) && s[k] == '"' {
+				value, n, err := readQuoted(s, k)
+				if err != nil {
+					return nil, 0, err
+				}
+				r.value, r.quoted, end = value, true, n
+			} else {
+				end = bareEnd(s, k)
+				r.value = s[k:end]
+			}
+			if r.value == "" && !r.quoted {
+				return nil, 0, &ParseError{Pos: k + 1, Msg: "missing value after '" + s[i:k] + "'"}
+			}
+			r.text = s[i:end]
+			return r, end, nil
+		}
+	}
+
+	end := bareEnd(s, i)
+	word := s[i:end]
+	r := &token{kind: _TOKEN_TERM, pos: i + 1, text: word, value: word}
+	switch word {
+	case "AND":
+		r.kind = _TOKEN_AND
+	case "OR":
+		r.kind = _TOKEN_OR
+	case "NOT":
+		r.kind = _TOKEN_NOT
+	}
+	return r, end, nil
+}
+
+func bareEnd(s string, i int) int {
+	for i < len(s) && !isSpace(s[i]) && s[i] != '(' && s[i] != ')' {
+		i++
+	}
+	return i
+}
+
+// readQuoted reads a double quoted string starting at i, \" and \\ are escapes
+func readQuoted(s string, i int) (string, int, error) {
+	sb := new(strings.Builder)
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			if j+1 < len(s) && (s[j+1] == '"' || s[j+1] == '\\') {
+				j++
+			}
+			sb.WriteByte(s[j])
+		case '"':
+			return sb.String(), j + 1, nil
+		default:
+			sb.WriteByte(s[j])
+		}
+	}
+	return "", 0, &ParseError{Pos: i + 1, Msg: "unterminated quote"}
+}
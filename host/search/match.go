@@ -0,0 +1,102 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/DreamvatLab/logs"
+)
+
+// Match evaluates a node against an entry in memory, a nil node matches everything
+func Match(node Node, entry *logs.LogEntry) bool {
+	switch x := node.(type) {
+	case nil:
+		return true
+	case *AndNode:
+		return Match(x.Left, entry) && Match(x.Right, entry)
+	case *OrNode:
+		return Match(x.Left, entry) || Match(x.Right, entry)
+	case *NotNode:
+		return !Match(x.Node, entry)
+	case *TermNode:
+		return matchTerm(x, entry)
+	default:
+		return false
+	}
+}
+
+func matchTerm(term *TermNode, entry *logs.LogEntry) bool {
+	if term.Match == MatchCompare {
+		level, _ := strconv.Atoi(term.Value)
+		return compare(int(entry.Level), term.Op, level)
+	}
+
+	var value string
+	switch term.Field {
+	case "ID":
+		value = entry.ID
+	case "User":
+		value = entry.User
+	case "TraceNo":
+		value = entry.TraceNo
+	case "Message":
+		value = entry.Message
+	case "Error":
+		value = entry.Error
+	case "StackTrace":
+		value = entry.StackTrace
+	case "Labels":
+		value = entry.Labels[term.LabelKey]
+	}
+
+	// Text terms are case insensitive, same as LOWER on both sides in the databases
+	value, pattern := strings.ToLower(value), strings.ToLower(term.Value)
+	switch term.Match {
+	case MatchWildcard:
+		return wildcard(pattern, value)
+	case MatchContains:
+		return strings.Contains(value, pattern)
+	default:
+		return value == pattern
+	}
+}
+
+func compare(a int, op string, b int) bool {
+	switch op {
+	case "!=":
+		return a != b
+	case ">":
+		return a > b
+	case ">=":
+		return a >= b
+	case "<":
+		return a < b
+	case "<=":
+		return a <= b
+	default:
+		return a == b
+	}
+}
+
+// wildcard matches the whole value against a pattern where '*' is any sequence
+func wildcard(pattern, value string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == value
+	}
+
+	if !strings.HasPrefix(value, parts[0]) {
+		return false
+	}
+	value = value[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, x := range parts[1 : len(parts)-1] {
+		i := strings.Index(value, x)
+		if i < 0 {
+			return false
+		}
+		value = value[i+len(x):]
+	}
+	return len(value) >= len(last) && strings.HasSuffix(value, last)
+}
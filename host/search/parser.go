@@ -0,0 +1,281 @@
+// Package search parses the log search language into an AST and compiles it to backend filters.
+//
+//	level>=warning user:alice* "connection reset" -trace:abc NOT error:timeout
+//
+// Bare words and quoted phrases match Message by substring.
+// field:value matches the whole value, '*' in value is a wildcard.
+// All text terms are case insensitive, e.g. user:ALICE matches "alice".
+// level supports =, !=, >, >=, <, <= with level names or numbers.
+// Terms are joined by AND unless OR is given, NOT or '-' negates a term, parentheses group terms.
+package search
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/DreamvatLab/logs"
+)
+
+type MatchKind int
+
+const (
+	// Whole value
+	MatchExact MatchKind = iota
+	// Value with '*' wildcards
+	MatchWildcard
+	// Substring
+	MatchContains
+	// Numeric comparison, only for level
+	MatchCompare
+)
+
+type Node interface {
+	node()
+}
+
+type AndNode struct {
+	Left, Right Node
+}
+
+type OrNode struct {
+	Left, Right Node
+}
+
+type NotNode struct {
+	Node Node
+}
+
+type TermNode struct {
+	// Column name of the field, e.g. "Message", "Level", "Labels"
+	Field string
+	// Key of the label when Field is "Labels"
+	LabelKey string
+	// One of "=", "!=", ">", ">=", "<", "<="
+	Op    string
+	Value string
+	Match MatchKind
+	// Byte position of the term in the query, starts from 1
+	Pos int
+}
+
+func (*AndNode) node()  {}
+func (*OrNode) node()   {}
+func (*NotNode) node()  {}
+func (*TermNode) node() {}
+
+type ParseError struct {
+	// Byte position of the error in the query, starts from 1
+	Pos int
+	Msg string
+}
+
+func (o *ParseError) Error() string {
+	return fmt.Sprintf("query error at position %d: %s", o.Pos, o.Msg)
+}
+
+var (
+	_fields = map[string]string{
+		"level":      "Level",
+		"user":       "User",
+		"trace":      "TraceNo",
+		"traceno":    "TraceNo",
+		"msg":        "Message",
+		"message":    "Message",
+		"error":      "Error",
+		"stack":      "StackTrace",
+		"stacktrace": "StackTrace",
+		"id":         "ID",
+	}
+	_levels = map[string]logs.LogLevel{
+		"verbose":    logs.LogLevel_Verbose,
+		"debug":      logs.LogLevel_Debug,
+		"info":       logs.LogLevel_Infomation,
+		"infomation": logs.LogLevel_Infomation,
+		"warn":       logs.LogLevel_Warning,
+		"warning":    logs.LogLevel_Warning,
+		"error":      logs.LogLevel_Error,
+		"fatal":      logs.LogLevel_Fatal,
+	}
+)
+
+// Parse returns nil for an empty query
+func Parse(query string) (Node, error) {
+	tokens, err := lex(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == _TOKEN_EOF {
+		return nil, nil
+	}
+
+	r, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if t := p.peek(); t.kind != _TOKEN_EOF {
+		return nil, &ParseError{Pos: t.pos, Msg: fmt.Sprintf("unexpected '%s'", t.text)}
+	}
+	return r, nil
+}
+
+// ParseLevel accepts level names and numbers
+func ParseLevel(s string) (logs.LogLevel, bool) {
+	if level, ok := _levels[strings.ToLower(s)]; ok {
+		return level, true
+	}
+	if n, err := strconv.Atoi(s); err == nil {
+		if _, ok := logs.LogLevel_name[int32(n)]; ok {
+			return logs.LogLevel(n), true
+		}
+	}
+	return 0, false
+}
+
+// ************************************************************************************************
+
+type parser struct {
+	tokens []*token
+	index  int
+}
+
+func (o *parser) peek() *token {
+	return o.tokens[o.index]
+}
+
+func (o *parser) next() *token {
+	r := o.tokens[o.index]
+	if r.kind != _TOKEN_EOF {
+		o.index++
+	}
+	return r
+}
+
+func (o *parser) parseOr() (Node, error) {
+	left, err := o.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for o.peek().kind == _TOKEN_OR {
+		o.next()
+		right, err := o.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &OrNode{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+func (o *parser) parseAnd() (Node, error) {
+	left, err := o.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch o.peek().kind {
+		case _TOKEN_AND:
+			o.next()
+		case _TOKEN_NOT, _TOKEN_MINUS, _TOKEN_LPAREN, _TOKEN_TERM:
+			// Implicit AND
+		default:
+			return left, nil
+		}
+
+		right, err := o.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &AndNode{Left: left, Right: right}
+	}
+}
+
+func (o *parser) parseUnary() (Node, error) {
+	switch o.peek().kind {
+	case _TOKEN_NOT, _TOKEN_MINUS:
+		o.next()
+		node, err := o.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Node: node}, nil
+	default:
+		return o.parsePrimary()
+	}
+}
+
+func (o *parser) parsePrimary() (Node, error) {
+	t := o.next()
+	switch t.kind {
+	case _TOKEN_LPAREN:
+		node, err := o.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := o.next(); closing.kind != _TOKEN_RPAREN {
+			return nil, &ParseError{Pos: closing.pos, Msg: fmt.Sprintf("missing ')' for '(' at position %d", t.pos)}
+		}
+		return node, nil
+	case _TOKEN_TERM:
+		return newTerm(t)
+	case _TOKEN_EOF:
+		return nil, &ParseError{Pos: t.pos, Msg: "unexpected end of query"}
+	default:
+		return nil, &ParseError{Pos: t.pos, Msg: fmt.Sprintf("unexpected '%s'", t.text)}
+	}
+}
+
+func newTerm(t *token) (Node, error) {
+	// Bare word or phrase
+	if t.field == "" {
+		return &TermNode{Field: "Message", Op: "=", Value: t.value, Match: MatchContains, Pos: t.pos}, nil
+	}
+
+	r := &TermNode{Op: t.op, Value: t.value, Pos: t.pos}
+	if r.Op == ":" {
+		r.Op = "="
+	}
+
+	name := strings.ToLower(t.field)
+	if strings.HasPrefix(name, "labels.") || strings.HasPrefix(name, "label.") {
+		r.Field = "Labels"
+		r.LabelKey = t.field[strings.IndexByte(t.field, '.')+1:]
+	} else if field, ok := _fields[name]; ok {
+		r.Field = field
+	} else {
+		return nil, &ParseError{Pos: t.pos, Msg: fmt.Sprintf("unknown field '%s'", t.field)}
+	}
+	if r.Field == "Labels" && r.LabelKey == "" {
+		return nil, &ParseError{Pos: t.pos, Msg: "label key cannot be empty"}
+	}
+
+	valuePos := t.pos + len(t.field) + len(t.op)
+	if r.Field == "Level" {
+		level, ok := ParseLevel(t.value)
+		if !ok {
+			return nil, &ParseError{Pos: valuePos, Msg: fmt.Sprintf("invalid level '%s'", t.value)}
+		}
+		r.Value = strconv.Itoa(int(level))
+		r.Match = MatchCompare
+		return r, nil
+	}
+
+	if r.Op != "=" && r.Op != "!=" {
+		return nil, &ParseError{Pos: t.pos + len(t.field), Msg: fmt.Sprintf("operator '%s' is only supported by level", t.op)}
+	}
+	if !t.quoted && strings.Contains(r.Value, "*") {
+		r.Match = MatchWildcard
+	}
+
+	var node Node = r
+	if r.Op == "!=" {
+		r.Op = "="
+		node = &NotNode{Node: r}
+	}
+	return node, nil
+}
@@ -0,0 +1,128 @@
+package search
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// format prints a node with explicit parentheses, terms are Field=value for exact, Field*=value for wildcard,
+// Field~value for contains and Field<op>value for comparisons
+func format(node Node) string {
+	switch x := node.(type) {
+	case nil:
+		return "<nil>"
+	case *AndNode:
+		return "(" + format(x.Left) + " AND " + format(x.Right) + ")"
+	case *OrNode:
+		return "(" + format(x.Left) + " OR " + format(x.Right) + ")"
+	case *NotNode:
+		return "NOT " + format(x.Node)
+	case *TermNode:
+		field := x.Field
+		if x.Field == "Labels" {
+			field += "." + x.LabelKey
+		}
+		switch x.Match {
+		case MatchWildcard:
+			return field + "*=" + strconv.Quote(x.Value)
+		case MatchContains:
+			return field + "~" + strconv.Quote(x.Value)
+		case MatchCompare:
+			return field + x.Op + x.Value
+		default:
+			return field + "=" + strconv.Quote(x.Value)
+		}
+	default:
+		return "?"
+	}
+}
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		query string
+		want  string
+	}{
+		{"", "<nil>"},
+		{"  ", "<nil>"},
+		{"reset", `Message~"reset"`},
+		{`"connection reset"`, `Message~"connection reset"`},
+		// AND binds tighter than OR, implicit AND is the same as AND
+		{"a b OR c", `((Message~"a" AND Message~"b") OR Message~"c")`},
+		{"a OR b AND c", `(Message~"a" OR (Message~"b" AND Message~"c"))`},
+		{"a OR b OR c", `((Message~"a" OR Message~"b") OR Message~"c")`},
+		{"(a OR b) c", `((Message~"a" OR Message~"b") AND Message~"c")`},
+		// NOT and '-' bind to the next term only
+		{"NOT a b", `(NOT Message~"a" AND Message~"b")`},
+		{"-(a OR b) level>=error", `(NOT (Message~"a" OR Message~"b") AND Level>=4)`},
+		{"-user:erin", `NOT User="erin"`},
+		{"a - b", `((Message~"a" AND Message~"-") AND Message~"b")`},
+		// Fields, operators and levels
+		{"user:Bob", `User="Bob"`},
+		{"USER:bob", `User="bob"`},
+		{"trace=T1 msg:x stack:y error:z id:e1", `((((TraceNo="T1" AND Message="x") AND StackTrace="y") AND Error="z") AND ID="e1")`},
+		{"user!=bob", `NOT User="bob"`},
+		{"level:warn", "Level=3"},
+		{"level<2", "Level<2"},
+		{"level!=Fatal", "Level!=5"},
+		{"labels.env:prod", `Labels.env="prod"`},
+		{"Label.Env:prod", `Labels.Env="prod"`},
+		{"labels.a.b:x", `Labels.a.b="x"`},
+		// Quoting
+		{`user:"bob smith"`, `User="bob smith"`},
+		{`msg:"say \"hi\" \\ ok"`, `Message="say \"hi\" \\ ok"`},
+		{`msg:"a\nb"`, `Message="a\\nb"`},
+		{`user:""`, `User=""`},
+		{`"OR"`, `Message~"OR"`},
+		// Wildcards, only unquoted
+		{"user:bob*", `User*="bob*"`},
+		{"user:*b*b", `User*="*b*b"`},
+		{`user:"bob*"`, `User="bob*"`},
+		{"user:b_b%*", `User*="b_b%*"`},
+		{"bob*", `Message~"bob*"`},
+	}
+	for _, x := range cases {
+		t.Run(x.query, func(t *testing.T) {
+			node, err := Parse(x.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) = %v", x.query, err)
+			}
+			if got := format(node); got != x.want {
+				t.Errorf("Parse(%q) = %s, want %s", x.query, got, x.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		query string
+		pos   int
+		msg   string
+	}{
+		{"user:", 6, "missing value after 'user:'"},
+		{`a "abc`, 3, "unterminated quote"},
+		{`user:"abc`, 6, "unterminated quote"},
+		{"a (b", 5, "missing ')' for '(' at position 3"},
+		{"a )", 3, "unexpected ')'"},
+		{"a OR", 5, "unexpected end of query"},
+		{"NOT", 4, "unexpected end of query"},
+		{"a AND OR b", 7, "unexpected 'OR'"},
+		{"foo:bar", 1, "unknown field 'foo'"},
+		{"a level:loud", 9, "invalid level 'loud'"},
+		{"a user>=x", 7, "operator '>=' is only supported by level"},
+		{"labels.:x", 1, "label key cannot be empty"},
+	}
+	for _, x := range cases {
+		t.Run(x.query, func(t *testing.T) {
+			_, err := Parse(x.query)
+			var parseErr *ParseError
+			if !errors.As(err, &parseErr) {
+				t.Fatalf("Parse(%q) = %v, want a ParseError", x.query, err)
+			}
+			if parseErr.Pos != x.pos || parseErr.Msg != x.msg {
+				t.Errorf("Parse(%q) = %d %q, want %d %q", x.query, parseErr.Pos, parseErr.Msg, x.pos, x.msg)
+			}
+		})
+	}
+}
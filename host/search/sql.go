@@ -0,0 +1,112 @@
+package search
+
+import (
+	"strconv"
+	"strings"
+)
+
+type Dialect int
+
+const (
+	DialectMySQL Dialect = iota
+	DialectClickHouse
+	DialectSQLite
+)
+
+var (
+	_likeReplacer = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+)
+
+// ToSQL compiles a node to a condition with '?' placeholders and its arguments
+func ToSQL(node Node, dialect Dialect) (string, []any) {
+	c := &sqlCompiler{dialect: dialect, sb: new(strings.Builder)}
+	c.compile(node, false)
+	return c.sb.String(), c.args
+}
+
+type sqlCompiler struct {
+	dialect Dialect
+	sb      *strings.Builder
+	args    []any
+}
+
+func (o *sqlCompiler) compile(node Node, negated bool) {
+	switch x := node.(type) {
+	case *AndNode:
+		o.binary(x.Left, " AND ", x.Right, negated)
+	case *OrNode:
+		o.binary(x.Left, " OR ", x.Right, negated)
+	case *NotNode:
+		o.sb.WriteString("NOT (")
+		o.compile(x.Node, !negated)
+		o.sb.WriteString(")")
+	case *TermNode:
+		o.term(x, negated)
+	}
+}
+
+func (o *sqlCompiler) binary(left Node, op string, right Node, negated bool) {
+	o.sb.WriteString("(")
+	o.compile(left, negated)
+	o.sb.WriteString(op)
+	o.compile(right, negated)
+	o.sb.WriteString(")")
+}
+
+func (o *sqlCompiler) term(term *TermNode, negated bool) {
+	if term.Match == MatchCompare {
+		level, _ := strconv.Atoi(term.Value)
+		o.sb.WriteString("`" + term.Field + "` " + term.Op + " ?")
+		o.args = append(o.args, level)
+		return
+	}
+
+	// Text terms are case insensitive, LOWER on both sides also covers the binary collation of MySQL JSON values
+	o.sb.WriteString("LOWER(")
+	o.column(term, negated)
+	o.sb.WriteString(")")
+	value := strings.ToLower(term.Value)
+	switch term.Match {
+	case MatchWildcard:
+		o.sb.WriteString(" LIKE ?" + o.likeEscape())
+		o.args = append(o.args, strings.ReplaceAll(_likeReplacer.Replace(value), "*", "%"))
+	case MatchContains:
+		o.sb.WriteString(" LIKE ?" + o.likeEscape())
+		o.args = append(o.args, "%"+_likeReplacer.Replace(value)+"%")
+	default:
+		o.sb.WriteString(" = ?")
+		o.args = append(o.args, value)
+	}
+}
+
+func (o *sqlCompiler) column(term *TermNode, negated bool) {
+	var column string
+	switch {
+	case term.Field == "Labels" && o.dialect == DialectClickHouse:
+		column = "`Labels`[?]"
+		o.args = append(o.args, term.LabelKey)
+	case term.Field == "Labels" && o.dialect == DialectSQLite:
+		// json_extract returns strings unquoted
+		column = "json_extract(`Labels`, ?)"
+		o.args = append(o.args, "$."+strconv.Quote(term.LabelKey))
+	case term.Field == "Labels":
+		column = "JSON_UNQUOTE(JSON_EXTRACT(`Labels`, ?))"
+		o.args = append(o.args, "$."+strconv.Quote(term.LabelKey))
+	default:
+		column = "`" + term.Field + "`"
+	}
+
+	// MySQL and SQLite columns are nullable, NOT over NULL would drop the row
+	if negated && o.dialect != DialectClickHouse {
+		column = "IFNULL(" + column + ", '')"
+	}
+	o.sb.WriteString(column)
+}
+
+// likeEscape declares the escape character of LIKE patterns, SQLite has none by default
+func (o *sqlCompiler) likeEscape() string {
+	if o.dialect == DialectSQLite {
+		return ` ESCAPE '\'`
+	}
+	return ""
+}
@@ -1,18 +1,98 @@
 package svc
 
 import (
+	"time"
+
+	"github.com/DreamvatLab/logs/host/alert"
 	"github.com/DreamvatLab/logs/host/core"
 	"github.com/DreamvatLab/logs/host/dal"
+	"github.com/DreamvatLab/logs/host/tail"
 )
 
 var (
 	_logDAL       dal.ILogDAL
 	_clientDAL    dal.IClientDAL
 	_asyncWriting bool
+
+	_streamBatchSize     int
+	_streamFlushInterval time.Duration
+
+	_tailHub     *tail.Hub
+	_alertEngine *alert.Engine
+
+	_authRequired bool
+	_adminKey     string
+
+	_otlpClientAttribute string
+
+	_lokiClientLabel string
+	_lokiSampleSize  int
+
+	_elasticEnabled bool
+	_elasticIndices map[string]string
+
+	_exportMaxRows   int64
+	_exportChunkSize int
 )
 
 func Init() {
 	_asyncWriting = core.ServiceConfigProvider.GetBool("AsyncWriting")
+	_authRequired = core.ServiceConfigProvider.GetBool("Auth.Required")
+	_adminKey = core.ServiceConfigProvider.GetString("Auth.AdminKey")
+
+	_otlpClientAttribute = core.ServiceConfigProvider.GetStringDefault("OTLP.ClientAttribute", "service.name")
+
+	_lokiClientLabel = core.ServiceConfigProvider.GetStringDefault("Loki.ClientLabel", "client")
+	_lokiSampleSize = core.ServiceConfigProvider.GetInt("Loki.LabelSampleSize")
+	if _lokiSampleSize <= 0 {
+		_lokiSampleSize = 1000
+	}
+
+	// Elastic ingestion is off without an "Elastic" section, a section failing to decode counts as missing
+	var elasticConfig *struct{ Indices map[string]string }
+	if core.ServiceConfigProvider.GetStruct("Elastic", &elasticConfig) == nil && elasticConfig != nil {
+		_elasticEnabled, _elasticIndices = true, elasticConfig.Indices
+	}
+
+	_exportMaxRows = int64(core.ServiceConfigProvider.GetInt("Export.MaxRows"))
+	if _exportMaxRows <= 0 {
+		_exportMaxRows = 1000000
+	}
+	_exportChunkSize = core.ServiceConfigProvider.GetInt("Export.ChunkSize")
+	if _exportChunkSize <= 0 {
+		_exportChunkSize = 1000
+	}
+
+	_streamBatchSize = core.ServiceConfigProvider.GetInt("Streaming.BatchSize")
+	if _streamBatchSize <= 0 {
+		_streamBatchSize = 500
+	}
+	flushInterval := core.ServiceConfigProvider.GetInt("Streaming.FlushInterval") // Milliseconds
+	if flushInterval <= 0 {
+		flushInterval = 1000
+	}
+	_streamFlushInterval = time.Millisecond * time.Duration(flushInterval)
+
+	tailQueueSize := core.ServiceConfigProvider.GetInt("Tail.QueueSize")
+	if tailQueueSize <= 0 {
+		tailQueueSize = 1000
+	}
+	_tailHub = tail.NewHub(tailQueueSize)
+
 	_logDAL = dal.NewLogDAL()
 	_clientDAL = dal.NewClientDAL()
+
+	retentionInterval := core.ServiceConfigProvider.GetInt("Retention.Interval") // Minutes
+	if retentionInterval <= 0 {
+		retentionInterval = 60
+	}
+	retentionDryRun := core.ServiceConfigProvider.GetBool("Retention.DryRun")
+	go runJanitor(time.Minute*time.Duration(retentionInterval), retentionDryRun)
+
+	alertInterval := core.ServiceConfigProvider.GetInt("Alert.Interval") // Seconds
+	if alertInterval <= 0 {
+		alertInterval = 10
+	}
+	_alertEngine = alert.NewEngine(_clientDAL.GetAlertRules)
+	go _alertEngine.Run(time.Second * time.Duration(alertInterval))
 }
@@ -2,13 +2,140 @@ package svc
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"sync"
+	"time"
 
 	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xutils"
 	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/alert"
 )
 
 type LogClientService struct{}
 
+var (
+	// Client id is part of database names
+	_clientIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,50}$`)
+	// Serializes read-modify-write of client keys
+	_keysLocker = new(sync.Mutex)
+)
+
+func validateClient(in *logs.LogClient) error {
+	if in == nil {
+		return xerr.New("client cannot be nil")
+	}
+	if !_clientIDRegex.MatchString(in.ID) {
+		return xerr.Errorf("invalid client id '%s', only 1 to 50 letters, digits, '_' and '-' are allowed", in.ID)
+	}
+	if in.DBPolicy < 0 || in.DBPolicy > 3 {
+		return xerr.Errorf("invalid DBPolicy %d, must be 0 to 3", in.DBPolicy)
+	}
+	if _, ok := logs.LogLevel_name[int32(in.Level)]; !ok {
+		return xerr.Errorf("invalid Level %d", in.Level)
+	}
+	if in.RetentionDays < 0 {
+		return xerr.Errorf("invalid RetentionDays %d, must not be negative", in.RetentionDays)
+	}
+	if in.RateLimit < 0 || in.RateBurst < 0 || in.DailyQuota < 0 {
+		return xerr.New("RateLimit, RateBurst and DailyQuota must not be negative")
+	}
+	return nil
+}
+
+func (o *LogClientService) GetClient(ctx context.Context, in *logs.LogClientQuery) (*logs.LogClientResult, error) {
+	r := new(logs.LogClientResult)
+
+	client, err := _clientDAL.GetClient(in.ID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+	} else if client == nil {
+		r.Message = fmt.Sprintf("Client '%s' not found", in.ID)
+	}
+
+	r.LogClient = client
+	return r, nil
+}
+func (o *LogClientService) CreateClient(ctx context.Context, in *logs.LogClient) (*logs.LogClientResult, error) {
+	r := new(logs.LogClientResult)
+
+	err := validateClient(in)
+	if err != nil {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	client, err := _clientDAL.GetClient(in.ID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	} else if client != nil {
+		r.Message = fmt.Sprintf("Client '%s' already exists", in.ID)
+		return r, nil
+	}
+
+	err = _clientDAL.InsertClient(in)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.LogClient = in
+	return r, nil
+}
+
+// UpdateClient replaces all fields of the client, callers get it first to change some of them
+func (o *LogClientService) UpdateClient(ctx context.Context, in *logs.LogClient) (*logs.LogClientResult, error) {
+	r := new(logs.LogClientResult)
+
+	err := validateClient(in)
+	if err != nil {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	client, err := _clientDAL.GetClient(in.ID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	} else if client == nil {
+		r.Message = fmt.Sprintf("Client '%s' not found", in.ID)
+		return r, nil
+	}
+
+	err = _clientDAL.UpdateClient(in)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.LogClient = in
+	return r, nil
+}
+func (o *LogClientService) DeleteClient(ctx context.Context, in *logs.LogClientQuery) (*logs.LogClientResult, error) {
+	r := new(logs.LogClientResult)
+
+	client, err := _clientDAL.GetClient(in.ID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	} else if client == nil {
+		r.Message = fmt.Sprintf("Client '%s' not found", in.ID)
+		return r, nil
+	}
+
+	err = _clientDAL.DeleteClient(in.ID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.LogClient = client
+	return r, nil
+}
+
 func (o *LogClientService) GetClients(ctx context.Context, in *logs.LogClientsQuery) (*logs.LogClientsResult, error) {
 	r := new(logs.LogClientsResult)
 
@@ -43,3 +170,198 @@ func (o *LogClientService) GetTables(ctx context.Context, in *logs.TablesQuery)
 	r.Tables = list
 	return r, nil
 }
+
+// publicKeys drops expired keys and hashes
+func publicKeys(keys []*logs.LogClientKey) []*logs.LogClientKey {
+	now := time.Now().UnixMilli()
+	r := make([]*logs.LogClientKey, 0, len(keys))
+	for _, x := range keys {
+		if x.ExpiresOnUtc > 0 && x.ExpiresOnUtc <= now {
+			continue
+		}
+		r = append(r, &logs.LogClientKey{
+			ID:           x.ID,
+			CreatedOnUtc: x.CreatedOnUtc,
+			ExpiresOnUtc: x.ExpiresOnUtc,
+		})
+	}
+	return r
+}
+
+func (o *LogClientService) GetClientKeys(ctx context.Context, in *logs.LogClientQuery) (*logs.LogClientKeysResult, error) {
+	r := new(logs.LogClientKeysResult)
+
+	keys, err := _clientDAL.GetClientKeys(in.ID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.Keys = publicKeys(keys)
+	return r, nil
+}
+func (o *LogClientService) IssueClientKey(ctx context.Context, in *logs.IssueLogClientKeyCommand) (*logs.IssueLogClientKeyResult, error) {
+	r := new(logs.IssueLogClientKeyResult)
+
+	if in.ExpiresIn < 0 || in.Overlap < 0 {
+		r.Message = "ExpiresIn and Overlap must not be negative"
+		return r, nil
+	}
+
+	client, err := _clientDAL.GetClient(in.ClientID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	} else if client == nil {
+		r.Message = fmt.Sprintf("Client '%s' not found", in.ClientID)
+		return r, nil
+	}
+
+	_keysLocker.Lock()
+	defer _keysLocker.Unlock()
+
+	keys, err := _clientDAL.GetClientKeys(in.ClientID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	now := time.Now()
+	var expiresOnUtc int64
+	if in.ExpiresIn > 0 {
+		expiresOnUtc = now.Add(time.Second * time.Duration(in.ExpiresIn)).UnixMilli()
+	}
+	key, secret, err := newClientKey(expiresOnUtc)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	// Expired keys are dropped, the rotated ones stay valid during the overlap
+	overlapEnd := now.Add(time.Second * time.Duration(in.Overlap)).UnixMilli()
+	newKeys := make([]*logs.LogClientKey, 0, len(keys)+1)
+	for _, x := range keys {
+		if x.ExpiresOnUtc > 0 && x.ExpiresOnUtc <= now.UnixMilli() {
+			continue
+		}
+		x = &logs.LogClientKey{ID: x.ID, Hash: x.Hash, CreatedOnUtc: x.CreatedOnUtc, ExpiresOnUtc: x.ExpiresOnUtc}
+		if in.Rotate && (x.ExpiresOnUtc == 0 || x.ExpiresOnUtc > overlapEnd) {
+			x.ExpiresOnUtc = overlapEnd
+		}
+		newKeys = append(newKeys, x)
+	}
+	newKeys = append(newKeys, key)
+
+	err = _clientDAL.UpdateClientKeys(in.ClientID, newKeys)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.Key = publicKeys([]*logs.LogClientKey{key})[0]
+	r.Secret = secret
+	return r, nil
+}
+func (o *LogClientService) RevokeClientKey(ctx context.Context, in *logs.RevokeLogClientKeyCommand) (*logs.LogClientKeysResult, error) {
+	r := new(logs.LogClientKeysResult)
+
+	_keysLocker.Lock()
+	defer _keysLocker.Unlock()
+
+	keys, err := _clientDAL.GetClientKeys(in.ClientID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	index := slices.IndexFunc(keys, func(x *logs.LogClientKey) bool { return x.ID == in.KeyID })
+	if index < 0 {
+		r.Message = fmt.Sprintf("Key '%s' of client '%s' not found", in.KeyID, in.ClientID)
+		return r, nil
+	}
+	// The key is kept expired, a client without keys would be open again
+	keys = slices.Clone(keys)
+	x := keys[index]
+	keys[index] = &logs.LogClientKey{ID: x.ID, Hash: x.Hash, CreatedOnUtc: x.CreatedOnUtc, ExpiresOnUtc: time.Now().UnixMilli()}
+
+	err = _clientDAL.UpdateClientKeys(in.ClientID, keys)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.Keys = publicKeys(keys)
+	return r, nil
+}
+
+func (o *LogClientService) GetClientUsages(ctx context.Context, in *logs.LogClientQuery) (*logs.LogClientUsagesResult, error) {
+	r := new(logs.LogClientUsagesResult)
+	r.Usages = getUsages(in.ID)
+	return r, nil
+}
+
+func (o *LogClientService) GetAlertRules(ctx context.Context, in *logs.AlertRulesQuery) (*logs.AlertRulesResult, error) {
+	r := new(logs.AlertRulesResult)
+
+	rules, err := _clientDAL.GetAlertRules()
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.AlertRules = make([]*logs.AlertRule, 0, len(rules))
+	for _, x := range rules {
+		if in.ClientID == "" || x.ClientID == in.ClientID {
+			r.AlertRules = append(r.AlertRules, x)
+		}
+	}
+	return r, nil
+}
+func (o *LogClientService) SaveAlertRule(ctx context.Context, in *logs.AlertRule) (*logs.AlertRuleResult, error) {
+	r := new(logs.AlertRuleResult)
+
+	err := alert.ValidateRule(in)
+	if err != nil {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	client, err := _clientDAL.GetClient(in.ClientID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	} else if client == nil {
+		r.Message = fmt.Sprintf("Client '%s' not found", in.ClientID)
+		return r, nil
+	}
+
+	if in.ID == "" {
+		in.ID = xutils.GenerateStringID()
+	}
+	err = _clientDAL.SaveAlertRule(in)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+	_alertEngine.Reload()
+
+	r.AlertRule = in
+	return r, nil
+}
+func (o *LogClientService) DeleteAlertRule(ctx context.Context, in *logs.AlertRuleQuery) (*logs.AlertRuleResult, error) {
+	r := new(logs.AlertRuleResult)
+
+	err := _clientDAL.DeleteAlertRule(in.ID)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+	_alertEngine.Reload()
+
+	return r, nil
+}
+func (o *LogClientService) GetAlertStates(ctx context.Context, in *logs.AlertRulesQuery) (*logs.AlertStatesResult, error) {
+	r := new(logs.AlertStatesResult)
+	r.AlertStates = _alertEngine.States(in.ClientID)
+	return r, nil
+}
@@ -2,28 +2,46 @@ package svc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/DreamvatLab/go/xerr"
 	"github.com/DreamvatLab/go/xutils"
 	"github.com/DreamvatLab/logs"
 	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
+	"github.com/DreamvatLab/logs/host/tail"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 type LogService struct{}
 
-func (o *LogService) WriteLogEntry(_ context.Context, in *logs.WriteLogCommand) (*logs.LogEntryResult, error) {
+func (o *LogService) WriteLogEntry(ctx context.Context, in *logs.WriteLogCommand) (*logs.LogEntryResult, error) {
 	r := new(logs.LogEntryResult)
 
+	entries := []*logs.LogEntry{in.LogEntry}
 	if _asyncWriting {
+		// Limits are checked before returning, so that the caller can back off
+		client, err := admitClient(in.ClientID, entries)
+		if limitErr := limitStatus(ctx, err); limitErr != nil {
+			return nil, limitErr
+		} else if xerr.LogError(err) {
+			r.Message = err.Error()
+			return r, nil
+		}
 		go func() {
-			err := write(in)
-			xerr.LogError(err)
+			errs := store(client, entries)
+			xerr.LogError(errs[0])
 		}()
 	} else {
-		err := write(in)
-		if xerr.LogError(err) {
+		err := writeOne(in)
+		if limitErr := limitStatus(ctx, err); limitErr != nil {
+			return nil, limitErr
+		} else if xerr.LogError(err) {
 			r.Message = err.Error()
 		}
 	}
@@ -31,63 +49,369 @@ func (o *LogService) WriteLogEntry(_ context.Context, in *logs.WriteLogCommand)
 	return r, nil
 }
 
-func write(in *logs.WriteLogCommand) error {
-	client, err := _clientDAL.GetClient(in.ClientID)
+// WriteLogEntries always writes synchronously, so that per entry results can be returned
+func (o *LogService) WriteLogEntries(ctx context.Context, in *logs.WriteLogEntriesCommand) (*logs.WriteLogEntriesResult, error) {
+	r := new(logs.WriteLogEntriesResult)
+
+	errs, err := write(in.ClientID, in.LogEntries)
+	if limitErr := limitStatus(ctx, err); limitErr != nil {
+		return nil, limitErr
+	} else if xerr.LogError(err) {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	r.Results = make([]*logs.WriteLogEntryResult, len(in.LogEntries))
+	for i, x := range in.LogEntries {
+		result := new(logs.WriteLogEntryResult)
+		if errs[i] != nil {
+			result.Message = errs[i].Error()
+		} else if x != nil {
+			result.ID = x.ID
+		}
+		r.Results[i] = result
+	}
+
+	return r, nil
+}
+
+// StreamLogEntries writes entries in batches of the configured size or interval,
+// and acknowledges the highest sequence number after each batch is persisted.
+// At most one more batch is received while a batch is being written, then receiving pauses,
+// which applies back pressure to the producer through gRPC flow control.
+func (o *LogService) StreamLogEntries(stream grpc.BidiStreamingServer[logs.WriteLogCommand, logs.StreamLogEntriesAck]) error {
+	commands := make(chan *logs.WriteLogCommand, _streamBatchSize)
+	var recvErr error
+	go func() {
+		defer close(commands)
+		for {
+			in, err := stream.Recv()
+			if err != nil {
+				if err != io.EOF {
+					recvErr = err
+				}
+				return
+			}
+			select {
+			case commands <- in:
+			case <-stream.Context().Done():
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(_streamFlushInterval)
+	defer ticker.Stop()
+
+	var acked int64
+	batch := make([]*logs.WriteLogCommand, 0, _streamBatchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+
+		err := writeStreamBatch(batch)
+		if err != nil {
+			// Not acknowledged entries are expected to be sent again by the producer
+			xerr.LogError(err)
+			stream.Send(&logs.StreamLogEntriesAck{
+				Sequence: acked,
+				Message:  err.Error(),
+			})
+			return err
+		}
+
+		acked = batch[len(batch)-1].Sequence
+		batch = batch[:0]
+		err = stream.Send(&logs.StreamLogEntriesAck{
+			Sequence: acked,
+		})
+		return err
+	}
+
+	for {
+		select {
+		case in, ok := <-commands:
+			if !ok {
+				if err := flush(); err != nil {
+					return streamStatus(stream, err)
+				}
+				return recvErr
+			}
+			batch = append(batch, in)
+			if len(batch) < _streamBatchSize {
+				continue
+			}
+		case <-ticker.C:
+		}
+
+		if err := flush(); err != nil {
+			return streamStatus(stream, err)
+		}
+	}
+}
+
+// streamStatus is the status ending a stream after a failed batch, RESOURCE_EXHAUSTED with retry-after in the trailer for a LimitError.
+// Rejected batches keep their NOT_FOUND or INVALID_ARGUMENT, failed writes end it with UNAVAILABLE, never OK,
+// so that the producer does not take the not acknowledged entries as persisted.
+func streamStatus(stream grpc.ServerStream, err error) error {
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		stream.SetTrailer(limitErr.Metadata())
+		return limitErr
+	}
+	if _, ok := status.FromError(err); ok {
+		return err // e.g. sending the ack failed
+	}
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// writeStreamBatch writes a batch received from a stream, commands may belong to different clients
+func writeStreamBatch(batch []*logs.WriteLogCommand) error {
+	clientIDs := make([]string, 0, 1)
+	entries := make(map[string][]*logs.LogEntry, 1)
+	for _, x := range batch {
+		if _, ok := entries[x.ClientID]; !ok {
+			clientIDs = append(clientIDs, x.ClientID)
+		}
+		entries[x.ClientID] = append(entries[x.ClientID], x.LogEntry)
+	}
+
+	for _, clientID := range clientIDs {
+		client, err := _clientDAL.GetClient(clientID)
+		if err != nil {
+			return err
+		} else if client == nil {
+			return status.Errorf(codes.NotFound, "Client '%s' not found", clientID)
+		}
+
+		// The batch size is up to the host, entries of a client are admitted in chunks no larger than its burst
+		list := entries[clientID]
+		size := int(rateBurst(client))
+		if size <= 0 {
+			size = len(list)
+		}
+		for start := 0; start < len(list); start += size {
+			chunk := list[start:min(start+size, len(list))]
+			err = admit(client, chunk)
+			if err != nil {
+				return err
+			}
+			for i, x := range store(client, chunk) {
+				if x != nil && chunk[i] == nil {
+					return status.Error(codes.InvalidArgument, x.Error())
+				} else if x != nil {
+					return x
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func writeOne(in *logs.WriteLogCommand) error {
+	errs, err := write(in.ClientID, []*logs.LogEntry{in.LogEntry})
 	if err != nil {
 		return err
+	}
+	return errs[0]
+}
+
+// partition determines database and table of an entry by client's DBPolicy
+func partition(client *logs.LogClient, createdOnUtc time.Time) (dbName, tableName string) {
+	switch client.DBPolicy {
+	case 1: // By Year
+		dbName = fmt.Sprintf("%s%s_%04d", core.LOG_DB_PREFIX, client.ID, createdOnUtc.Year())
+		// Use month as table name
+		tableName = fmt.Sprintf("%02d", createdOnUtc.Month())
+	case 2: // By Month
+		dbName = fmt.Sprintf("%s%s_%04d%02d", core.LOG_DB_PREFIX, client.ID, createdOnUtc.Year(), createdOnUtc.Month())
+		// Use day as table name
+		tableName = fmt.Sprintf("%02d", createdOnUtc.Day())
+	case 3: // By Day
+		dbName = fmt.Sprintf("%s%s_%04d%02d%02d", core.LOG_DB_PREFIX, client.ID, createdOnUtc.Year(), createdOnUtc.Month(), createdOnUtc.Day())
+		// Use hour as table name
+		tableName = fmt.Sprintf("%02d", createdOnUtc.Hour())
+	default:
+		dbName = fmt.Sprintf("%s%s", core.LOG_DB_PREFIX, client.ID)
+		// Use year as table name
+		tableName = fmt.Sprintf("%02d", createdOnUtc.Year())
+	}
+	return
+}
+
+// write stores entries of a client, entries are grouped by partition and inserted in bulk.
+// The returned errors are in the same order as entries, entries skipped by level have no error and no ID.
+func write(clientID string, entries []*logs.LogEntry) ([]error, error) {
+	client, err := admitClient(clientID, entries)
+	if err != nil {
+		return nil, err
+	}
+	return store(client, entries), nil
+}
+
+// admitClient gets the client and counts the entries against its limits, the error is a *LimitError when exceeded
+func admitClient(clientID string, entries []*logs.LogEntry) (*logs.LogClient, error) {
+	client, err := _clientDAL.GetClient(clientID)
+	if err != nil {
+		return nil, err
 	} else if client == nil {
-		return xerr.Errorf("Client '%s' not found", in.ClientID)
-	} else {
-		if in.LogEntry.Level < client.Level {
+		return nil, xerr.Errorf("Client '%s' not found", clientID)
+	}
+
+	err = admit(client, entries)
+	if err != nil {
+		return nil, err
+	}
+	return client, nil
+}
+
+// store writes entries of an admitted client, see write
+func store(client *logs.LogClient, entries []*logs.LogEntry) []error {
+	type group struct {
+		indexes []int
+		entries []*logs.LogEntry
+	}
+
+	errs := make([]error, len(entries))
+	groups := make(map[[2]string]*group)
+	keys := make([][2]string, 0, 1)
+	for i, x := range entries {
+		if x == nil {
+			errs[i] = xerr.New("log entry cannot be nil")
+			continue
+		}
+		if x.Level < client.Level {
 			// skip
-			return nil
+			continue
 		}
 
-		// determine database and table
-		createdOnUtc := time.UnixMilli(in.LogEntry.CreatedOnUtc)
+		createdOnUtc := time.UnixMilli(x.CreatedOnUtc)
 		// convert to ISO 8601 format
-		if in.LogEntry.CreatedOnUtcISO == "" {
-			in.LogEntry.CreatedOnUtcISO = createdOnUtc.Format(time.RFC3339)
-		}
-
-		var dbName, tableName string
-		switch client.DBPolicy {
-		case 1: // By Year
-			dbName = fmt.Sprintf("%s%s_%04d", core.LOG_DB_PREFIX, client.ID, createdOnUtc.Year())
-			// Use month as table name
-			tableName = fmt.Sprintf("%02d", createdOnUtc.Month())
-		case 2: // By Month
-			dbName = fmt.Sprintf("%s%s_%04d%02d", core.LOG_DB_PREFIX, client.ID, createdOnUtc.Year(), createdOnUtc.Month())
-			// Use day as table name
-			tableName = fmt.Sprintf("%02d", createdOnUtc.Day())
-		case 3: // By Day
-			dbName = fmt.Sprintf("%s%s_%04d%02d%02d", core.LOG_DB_PREFIX, client.ID, createdOnUtc.Year(), createdOnUtc.Month(), createdOnUtc.Day())
-			// Use hour as table name
-			tableName = fmt.Sprintf("%02d", createdOnUtc.Hour())
-		default:
-			dbName = fmt.Sprintf("%s%s", core.LOG_DB_PREFIX, client.ID)
-			// Use year as table name
-			tableName = fmt.Sprintf("%02d", createdOnUtc.Year())
+		if x.CreatedOnUtcISO == "" {
+			x.CreatedOnUtcISO = createdOnUtc.Format(time.RFC3339)
 		}
 		// generate id
-		in.LogEntry.ID = xutils.GenerateStringID()
+		x.ID = xutils.GenerateStringID()
 
-		err = _logDAL.InsertLogEntry(dbName, tableName, in.LogEntry)
-		return err
+		dbName, tableName := partition(client, createdOnUtc)
+		key := [2]string{dbName, tableName}
+		g, ok := groups[key]
+		if !ok {
+			g = new(group)
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.indexes = append(g.indexes, i)
+		g.entries = append(g.entries, x)
+	}
+
+	for _, key := range keys {
+		g := groups[key]
+		var err error
+		if len(g.entries) == 1 {
+			err = _logDAL.InsertLogEntry(key[0], key[1], g.entries[0])
+		} else {
+			err = _logDAL.InsertLogEntries(key[0], key[1], g.entries)
+		}
+		if err != nil {
+			for _, i := range g.indexes {
+				errs[i] = err
+			}
+			continue
+		}
+
+		_tailHub.Publish(client.ID, g.entries)
+		_alertEngine.Observe(client.ID, g.entries)
+	}
+
+	return errs
+}
+
+// SubscribeLogEntries subscribes to newly written entries matching the query, call UnsubscribeLogEntries to release it
+func (o *LogService) SubscribeLogEntries(query *logs.TailLogEntriesQuery) (*tail.Subscriber, error) {
+	if query == nil || query.ClientID == "" {
+		return nil, xerr.New("query and ClientID cannot be nil or empty")
+	}
+	return _tailHub.Subscribe(query), nil
+}
+
+func (o *LogService) UnsubscribeLogEntries(subscriber *tail.Subscriber) {
+	_tailHub.Unsubscribe(subscriber)
+}
+
+func (o *LogService) TailLogEntries(query *logs.TailLogEntriesQuery, stream grpc.ServerStreamingServer[logs.LogEntry]) error {
+	subscriber, err := o.SubscribeLogEntries(query)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	defer o.UnsubscribeLogEntries(subscriber)
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case x, ok := <-subscriber.C:
+			if !ok {
+				if subscriber.Dropped() {
+					return status.Error(codes.ResourceExhausted, "subscriber dropped for not keeping up")
+				}
+				return nil
+			}
+			err = stream.Send(x)
+			if err != nil {
+				return err
+			}
+		}
 	}
 }
 
 func (o *LogService) GetLogEntry(_ context.Context, query *logs.LogEntryQuery) (*logs.LogEntryResult, error) {
 	r := new(logs.LogEntryResult)
 
-	_logDAL.GetLogEntry(query)
+	var err error
+	r.LogEntry, err = _logDAL.GetLogEntry(query)
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+	} else if r.LogEntry == nil {
+		r.Message = "log entry not found"
+	}
 
 	return r, nil
 }
 func (o *LogService) GetLogEntries(_ context.Context, query *logs.LogEntriesQuery) (*logs.LogEntriesResult, error) {
 	r := new(logs.LogEntriesResult)
+	// Syntax errors are the caller's, don't log them
+	if _, err := search.Parse(query.Query); err != nil {
+		r.Message = err.Error()
+		return r, nil
+	}
+
+	var err error
+	if query.ClientID != "" && query.DBName == "" {
+		r.LogEntries, r.TotalCount, err = getLogEntriesInRange(query)
+	} else {
+		r.LogEntries, r.TotalCount, err = _logDAL.GetLogEntries(query)
+	}
+	if xerr.LogError(err) {
+		r.Message = err.Error()
+	} else {
+		r.NextCursor, r.PrevCursor = core.PageCursors(query, r.LogEntries, r.TotalCount)
+	}
+
+	return r, nil
+}
+
+func (o *LogService) GetLogHistogram(_ context.Context, query *logs.LogHistogramQuery) (*logs.LogHistogramResult, error) {
+	r := new(logs.LogHistogramResult)
+	if _, err := search.Parse(query.GetQuery().GetQuery()); err != nil {
+		r.Message = err.Error()
+		return r, nil
+	}
+
 	var err error
-	r.LogEntries, r.TotalCount, err = _logDAL.GetLogEntries(query)
+	r.Buckets, err = _logDAL.GetLogHistogram(query.Query, query.Interval)
 	if xerr.LogError(err) {
 		r.Message = err.Error()
 	}
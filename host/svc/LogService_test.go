@@ -0,0 +1,153 @@
+package svc
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/alert"
+	"github.com/DreamvatLab/logs/host/dal"
+	"github.com/DreamvatLab/logs/host/dal/memory"
+	"github.com/DreamvatLab/logs/host/tail"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// failingDAL fails every write like a database that is down
+type failingDAL struct {
+	memory.MemoryDAL
+}
+
+func (o *failingDAL) InsertLogEntry(dbName, tableName string, logEntry *logs.LogEntry) error {
+	return xerr.New("database is down")
+}
+func (o *failingDAL) InsertLogEntries(dbName, tableName string, logEntries []*logs.LogEntry) error {
+	return xerr.New("database is down")
+}
+
+// fakeStream receives commands and records acks without a connection
+type fakeStream struct {
+	grpc.ServerStream
+	commands []*logs.WriteLogCommand
+	acks     []*logs.StreamLogEntriesAck
+	trailer  metadata.MD
+}
+
+func (o *fakeStream) Context() context.Context {
+	return context.Background()
+}
+func (o *fakeStream) Recv() (*logs.WriteLogCommand, error) {
+	if len(o.commands) == 0 {
+		return nil, io.EOF
+	}
+	r := o.commands[0]
+	o.commands = o.commands[1:]
+	return r, nil
+}
+func (o *fakeStream) Send(ack *logs.StreamLogEntriesAck) error {
+	o.acks = append(o.acks, ack)
+	return nil
+}
+func (o *fakeStream) SetTrailer(md metadata.MD) {
+	o.trailer = md
+}
+
+// useDALs replaces the DALs, stream settings, tail hub and alert engine until the test ends
+func useDALs(t *testing.T, logDAL dal.ILogDAL, clients ...*logs.LogClient) {
+	logDALBefore, clientDALBefore := _logDAL, _clientDAL
+	batchSize, flushInterval := _streamBatchSize, _streamFlushInterval
+	tailHub, alertEngine := _tailHub, _alertEngine
+	t.Cleanup(func() {
+		_logDAL, _clientDAL = logDALBefore, clientDALBefore
+		_streamBatchSize, _streamFlushInterval = batchSize, flushInterval
+		_tailHub, _alertEngine = tailHub, alertEngine
+	})
+
+	_logDAL = logDAL
+	_clientDAL = new(memory.MemoryClientDAL)
+	for _, x := range clients {
+		_clientDAL.InsertClient(x)
+	}
+	_streamBatchSize, _streamFlushInterval = 10, time.Second
+	_tailHub = tail.NewHub(10)
+	_alertEngine = alert.NewEngine(_clientDAL.GetAlertRules)
+}
+
+func TestStreamLogEntriesFailedWrite(t *testing.T) {
+	useDALs(t, new(failingDAL), &logs.LogClient{ID: "c1"})
+
+	stream := &fakeStream{commands: []*logs.WriteLogCommand{
+		{ClientID: "c1", Sequence: 1, LogEntry: &logs.LogEntry{Message: "one", CreatedOnUtc: time.Now().UnixMilli()}},
+		{ClientID: "c1", Sequence: 2, LogEntry: &logs.LogEntry{Message: "two", CreatedOnUtc: time.Now().UnixMilli()}},
+	}}
+	err := new(LogService).StreamLogEntries(stream)
+	if got := status.Code(err); got != codes.Unavailable {
+		t.Errorf("StreamLogEntries = %v, want Unavailable", err)
+	}
+	if len(stream.acks) == 0 {
+		t.Errorf("the failure is not reported by an ack")
+	}
+	for _, x := range stream.acks {
+		if x.Sequence != 0 || x.Message == "" {
+			t.Errorf("ack %v acknowledges entries that are not persisted", x)
+		}
+	}
+}
+
+func TestStreamLogEntriesRejectedBatch(t *testing.T) {
+	useDALs(t, new(memory.MemoryDAL), &logs.LogClient{ID: "c1"})
+
+	cases := []struct {
+		name    string
+		command *logs.WriteLogCommand
+		want    codes.Code
+	}{
+		{"UnknownClient", &logs.WriteLogCommand{ClientID: "missing", Sequence: 1, LogEntry: &logs.LogEntry{Message: "one"}}, codes.NotFound},
+		{"NilEntry", &logs.WriteLogCommand{ClientID: "c1", Sequence: 1}, codes.InvalidArgument},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			stream := &fakeStream{commands: []*logs.WriteLogCommand{x.command}}
+			err := new(LogService).StreamLogEntries(stream)
+			if got := status.Code(err); got != x.want {
+				t.Errorf("StreamLogEntries = %v, want %v", err, x.want)
+			}
+		})
+	}
+}
+
+func TestWriteLogEntriesLargerThanBurst(t *testing.T) {
+	useDALs(t, new(memory.MemoryDAL), &logs.LogClient{ID: "burst-unary", RateLimit: 100, RateBurst: 2})
+	_usages.Delete("burst-unary")
+
+	entries := []*logs.LogEntry{{Message: "one"}, {Message: "two"}, {Message: "three"}}
+	_, err := new(LogService).WriteLogEntries(context.Background(), &logs.WriteLogEntriesCommand{ClientID: "burst-unary", LogEntries: entries})
+	if got := status.Code(err); got != codes.InvalidArgument {
+		t.Errorf("WriteLogEntries = %v, want InvalidArgument", err)
+	}
+	if usages := getUsages("burst-unary"); len(usages) != 1 || usages[0].Rejected != 3 {
+		t.Errorf("usages = %v, want 3 rejected", usages)
+	}
+}
+
+func TestStreamLogEntriesLargerThanBurst(t *testing.T) {
+	// The bucket refills at once, only the size of a chunk matters
+	useDALs(t, new(memory.MemoryDAL), &logs.LogClient{ID: "burst-stream", RateLimit: 1 << 30, RateBurst: 2})
+
+	stream := new(fakeStream)
+	for i := range 5 {
+		stream.commands = append(stream.commands, &logs.WriteLogCommand{ClientID: "burst-stream", Sequence: int64(i + 1), LogEntry: &logs.LogEntry{Message: "entry"}})
+	}
+	err := new(LogService).StreamLogEntries(stream)
+	if err != nil {
+		t.Fatalf("StreamLogEntries = %v, want nil", err)
+	}
+	if len(stream.acks) == 0 || stream.acks[len(stream.acks)-1].Sequence != 5 {
+		t.Errorf("acks = %v, want all 5 entries acknowledged", stream.acks)
+	}
+}
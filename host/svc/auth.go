@@ -0,0 +1,217 @@
+package svc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	_AUTH_HEADER = "authorization"
+	_AUTH_SCHEME = "Bearer "
+)
+
+var (
+	// Methods writing entries of the ClientID in their requests
+	_writeMethods = map[string]bool{
+		logs.LogEntryService_WriteLogEntry_FullMethodName:    true,
+		logs.LogEntryService_WriteLogEntries_FullMethodName:  true,
+		logs.LogEntryService_StreamLogEntries_FullMethodName: true,
+	}
+	// Methods changing clients and keys or reading alert rules with their webhooks, guarded by Auth.AdminKey
+	_adminMethods = map[string]bool{
+		logs.LogClientService_CreateClient_FullMethodName:    true,
+		logs.LogClientService_UpdateClient_FullMethodName:    true,
+		logs.LogClientService_DeleteClient_FullMethodName:    true,
+		logs.LogClientService_GetClientKeys_FullMethodName:   true,
+		logs.LogClientService_IssueClientKey_FullMethodName:  true,
+		logs.LogClientService_RevokeClientKey_FullMethodName: true,
+		logs.LogClientService_GetClientUsages_FullMethodName: true,
+		logs.LogClientService_GetAlertRules_FullMethodName:   true,
+		logs.LogClientService_SaveAlertRule_FullMethodName:   true,
+		logs.LogClientService_DeleteAlertRule_FullMethodName: true,
+		logs.LogClientService_GetAlertStates_FullMethodName:  true,
+	}
+)
+
+type clientIDGetter interface {
+	GetClientID() string
+}
+
+// newClientKey generates a key, the returned secret is the only copy of the plain key
+func newClientKey(expiresOnUtc int64) (*logs.LogClientKey, string, error) {
+	idBytes := make([]byte, 6)
+	secretBytes := make([]byte, 32)
+	_, err := rand.Read(idBytes)
+	if err == nil {
+		_, err = rand.Read(secretBytes)
+	}
+	if err != nil {
+		return nil, "", xerr.WithStack(err)
+	}
+
+	r := &logs.LogClientKey{
+		ID:           hex.EncodeToString(idBytes),
+		CreatedOnUtc: time.Now().UnixMilli(),
+		ExpiresOnUtc: expiresOnUtc,
+	}
+	secret := r.ID + "." + base64.RawURLEncoding.EncodeToString(secretBytes)
+	r.Hash = hashKey(secret)
+
+	return r, secret, nil
+}
+
+// hashKey uses plain SHA-256, keys are random so they don't need a slow hash
+func hashKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+func bearer(md metadata.MD) string {
+	for _, x := range md.Get(_AUTH_HEADER) {
+		if key, ok := strings.CutPrefix(x, _AUTH_SCHEME); ok {
+			return strings.TrimSpace(key)
+		}
+	}
+	return ""
+}
+
+// authorize checks the caller may write entries of the client.
+// A verified client certificate authorizes the client named by its CN, otherwise the API key in
+// the metadata must belong to the client and not be expired.
+// Clients never given a key are open unless Auth.Required is set, revoked keys are kept expired so clients stay closed.
+func authorize(ctx context.Context, clientID string) error {
+	if p, ok := peer.FromContext(ctx); ok {
+		if tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo); ok {
+			if certID := core.ClientCertID(&tlsInfo.State); certID != "" {
+				if certID != clientID {
+					return status.Errorf(codes.PermissionDenied, "certificate of '%s' cannot write client '%s'", certID, clientID)
+				}
+				return nil
+			}
+		}
+	}
+
+	keys, err := _clientDAL.GetClientKeys(clientID)
+	if xerr.LogError(err) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	if len(keys) == 0 && !_authRequired {
+		return nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	key := bearer(md)
+	if key == "" {
+		return status.Error(codes.Unauthenticated, "missing API key")
+	}
+
+	id, _, _ := strings.Cut(key, ".")
+	hash := hashKey(key)
+	for _, x := range keys {
+		if x.ID != id || subtle.ConstantTimeCompare([]byte(x.Hash), []byte(hash)) != 1 {
+			continue
+		}
+		if x.ExpiresOnUtc > 0 && x.ExpiresOnUtc <= time.Now().UnixMilli() {
+			return status.Errorf(codes.Unauthenticated, "API key '%s' is expired or revoked", x.ID)
+		}
+		return nil
+	}
+
+	return status.Errorf(codes.PermissionDenied, "API key is not valid for client '%s'", clientID)
+}
+
+// Authorize is authorize for requests not served by gRPC, see rpcContext of the web server
+func Authorize(ctx context.Context, clientID string) error {
+	return authorize(ctx, clientID)
+}
+
+// authorizeAdmin checks the admin key in the metadata, admin methods are refused until Auth.AdminKey is set
+func authorizeAdmin(md metadata.MD) error {
+	if _adminKey == "" {
+		return status.Error(codes.Unauthenticated, "admin API is disabled until Auth.AdminKey is set")
+	}
+	key := bearer(md)
+	if key == "" {
+		return status.Error(codes.Unauthenticated, "admin key is required")
+	}
+	if subtle.ConstantTimeCompare([]byte(key), []byte(_adminKey)) != 1 {
+		return status.Error(codes.PermissionDenied, "admin key is not valid")
+	}
+	return nil
+}
+
+// AuthorizeAdmin is authorizeAdmin for requests not served by gRPC, see rpcContext of the web server
+func AuthorizeAdmin(ctx context.Context) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	return authorizeAdmin(md)
+}
+
+// UnaryAuthInterceptor checks the API key of writes and the admin key of client management
+func UnaryAuthInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	var err error
+	if _adminMethods[info.FullMethod] {
+		err = authorizeAdmin(md)
+	} else if x, ok := req.(clientIDGetter); ok && _writeMethods[info.FullMethod] {
+		err = authorize(ctx, x.GetClientID())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(ctx, req)
+}
+
+// StreamAuthInterceptor checks the API key against the ClientID of every received command
+func StreamAuthInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if !_writeMethods[info.FullMethod] {
+		return handler(srv, ss)
+	}
+
+	return handler(srv, &authStream{
+		ServerStream: ss,
+		authorized:   make(map[string]bool),
+	})
+}
+
+type authStream struct {
+	grpc.ServerStream
+	// Clients already checked, a key revoked during the stream is honored by the next stream
+	authorized map[string]bool
+}
+
+func (o *authStream) RecvMsg(m any) error {
+	err := o.ServerStream.RecvMsg(m)
+	if err != nil {
+		return err
+	}
+
+	x, ok := m.(clientIDGetter)
+	if !ok || o.authorized[x.GetClientID()] {
+		return nil
+	}
+
+	err = authorize(o.Context(), x.GetClientID())
+	if err != nil {
+		return err
+	}
+	o.authorized[x.GetClientID()] = true
+	return nil
+}
@@ -0,0 +1,97 @@
+package svc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/dal"
+	"github.com/DreamvatLab/logs/host/dal/memory"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuthorizeAdmin(t *testing.T) {
+	defer func(adminKey string) { _adminKey = adminKey }(_adminKey)
+
+	withKey := func(key string) context.Context {
+		return metadata.NewIncomingContext(context.Background(), metadata.Pairs(_AUTH_HEADER, key))
+	}
+	cases := []struct {
+		name     string
+		adminKey string
+		ctx      context.Context
+		want     codes.Code
+	}{
+		{"Disabled", "", withKey("Bearer secret"), codes.Unauthenticated},
+		{"Missing", "secret", context.Background(), codes.Unauthenticated},
+		{"NotBearer", "secret", withKey("secret"), codes.Unauthenticated},
+		{"Wrong", "secret", withKey("Bearer guess"), codes.PermissionDenied},
+		{"Valid", "secret", withKey("Bearer secret"), codes.OK},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			_adminKey = x.adminKey
+			if got := status.Code(AuthorizeAdmin(x.ctx)); got != x.want {
+				t.Errorf("AuthorizeAdmin = %v, want %v", got, x.want)
+			}
+		})
+	}
+}
+
+func TestUnaryAuthInterceptorAdmin(t *testing.T) {
+	defer func(adminKey string) { _adminKey = adminKey }(_adminKey)
+
+	handler := func(ctx context.Context, req any) (any, error) { return "served", nil }
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(_AUTH_HEADER, "Bearer secret"))
+	cases := []struct {
+		name     string
+		adminKey string
+		method   string
+		want     codes.Code
+	}{
+		{"IssueKeyDisabled", "", logs.LogClientService_IssueClientKey_FullMethodName, codes.Unauthenticated},
+		{"UsagesDisabled", "", logs.LogClientService_GetClientUsages_FullMethodName, codes.Unauthenticated},
+		{"UsagesWrong", "other", logs.LogClientService_GetClientUsages_FullMethodName, codes.PermissionDenied},
+		{"UsagesValid", "secret", logs.LogClientService_GetClientUsages_FullMethodName, codes.OK},
+		{"GetClient", "", logs.LogClientService_GetClient_FullMethodName, codes.OK},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			_adminKey = x.adminKey
+			_, err := UnaryAuthInterceptor(ctx, &logs.LogClientQuery{}, &grpc.UnaryServerInfo{FullMethod: x.method}, handler)
+			if got := status.Code(err); got != x.want {
+				t.Errorf("UnaryAuthInterceptor(%s) = %v, want %v", x.method, got, x.want)
+			}
+		})
+	}
+}
+
+func TestRevokedKeysKeepClientClosed(t *testing.T) {
+	defer func(clientDAL dal.IClientDAL) { _clientDAL = clientDAL }(_clientDAL)
+	_clientDAL = new(memory.MemoryClientDAL)
+	_clientDAL.InsertClient(&logs.LogClient{ID: "c1"})
+
+	service := new(LogClientService)
+	issued, _ := service.IssueClientKey(context.Background(), &logs.IssueLogClientKeyCommand{ClientID: "c1"})
+	if issued.Message != "" {
+		t.Fatal(issued.Message)
+	}
+	withKey := metadata.NewIncomingContext(context.Background(), metadata.Pairs(_AUTH_HEADER, _AUTH_SCHEME+issued.Secret))
+	if err := authorize(withKey, "c1"); err != nil {
+		t.Fatalf("authorize with the issued key: %v", err)
+	}
+
+	revoked, _ := service.RevokeClientKey(context.Background(), &logs.RevokeLogClientKeyCommand{ClientID: "c1", KeyID: issued.Key.ID})
+	if revoked.Message != "" || len(revoked.Keys) != 0 {
+		t.Fatalf("RevokeClientKey = %v", revoked)
+	}
+	if got := status.Code(authorize(withKey, "c1")); got != codes.Unauthenticated {
+		t.Errorf("authorize with the revoked key = %v, want Unauthenticated", got)
+	}
+	if got := status.Code(authorize(context.Background(), "c1")); got != codes.Unauthenticated {
+		t.Errorf("authorize without a key after revoking the last one = %v, want Unauthenticated", got)
+	}
+}
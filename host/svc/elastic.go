@@ -0,0 +1,240 @@
+package svc
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ElasticDoc is a document to index, Err is set when its action or source is invalid
+type ElasticDoc struct {
+	Index  string
+	Source map[string]any
+	Err    error
+}
+
+// ElasticResult is the result of a document, in the shape of a bulk response item
+type ElasticResult struct {
+	Index string
+	// Empty when the document is not written
+	ID string
+	// 201 when written, 200 when skipped by the level of the client
+	Status int
+	// Elasticsearch error type, empty when the document is written
+	ErrType string
+	Reason  string
+}
+
+// IndexElastic writes documents to the clients their index maps to, see elasticClientID.
+// The error is a status error failing the whole request, problems of single documents are in their results.
+// It fails with NotFound when service.json has no "Elastic" section.
+func IndexElastic(ctx context.Context, docs []*ElasticDoc) ([]*ElasticResult, error) {
+	if !_elasticEnabled {
+		return nil, status.Error(codes.NotFound, "Elastic ingestion is not configured")
+	}
+
+	results := make([]*ElasticResult, len(docs))
+	clientIDs := make([]string, 0, 1)
+	groups := make(map[string][]int, 1)
+	for i, x := range docs {
+		results[i] = &ElasticResult{Index: x.Index}
+		if x.Err != nil {
+			results[i].Status, results[i].ErrType, results[i].Reason = 400, "illegal_argument_exception", x.Err.Error()
+			continue
+		}
+
+		clientID := elasticClientID(x.Index)
+		if _, ok := groups[clientID]; !ok {
+			clientIDs = append(clientIDs, clientID)
+		}
+		groups[clientID] = append(groups[clientID], i)
+	}
+
+	for _, clientID := range clientIDs {
+		err := authorize(ctx, clientID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, clientID := range clientIDs {
+		indexes := groups[clientID]
+		entries := make([]*logs.LogEntry, len(indexes))
+		for i, x := range indexes {
+			entries[i] = fromECS(docs[x].Source)
+		}
+
+		client, err := _clientDAL.GetClient(clientID)
+		if xerr.LogError(err) {
+			setElasticErr(results, indexes, 500, "exception", err.Error())
+			continue
+		} else if client == nil {
+			setElasticErr(results, indexes, 404, "index_not_found_exception", "no client for index '"+docs[indexes[0]].Index+"'")
+			continue
+		}
+
+		err = admit(client, entries)
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			// Shippers retry items rejected with 429
+			setElasticErr(results, indexes, 429, "es_rejected_execution_exception", limitErr.Msg)
+			continue
+		} else if status.Code(err) == codes.InvalidArgument {
+			// More entries than the burst, retrying cannot succeed
+			setElasticErr(results, indexes, 400, "illegal_argument_exception", status.Convert(err).Message())
+			continue
+		}
+
+		errs := store(client, entries)
+		for i, x := range indexes {
+			if xerr.LogError(errs[i]) {
+				setElasticErr(results, []int{x}, 500, "exception", errs[i].Error())
+			} else if entries[i].ID == "" {
+				// Skipped by the level of the client
+				results[x].Status = 200
+			} else {
+				results[x].ID = entries[i].ID
+				results[x].Status = 201
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func setElasticErr(results []*ElasticResult, indexes []int, status int, errType, reason string) {
+	for _, x := range indexes {
+		results[x].Status, results[x].ErrType, results[x].Reason = status, errType, reason
+	}
+}
+
+// elasticClientID maps an index to a client by Elastic.Indices of service.json, keys are index names or prefixes
+// ending with '*', e.g. {"filebeat-*": "web"}. An index without a match is the client id itself.
+func elasticClientID(index string) string {
+	if clientID, ok := _elasticIndices[index]; ok {
+		return clientID
+	}
+
+	var r, longest string
+	for pattern, clientID := range _elasticIndices {
+		prefix, ok := strings.CutSuffix(pattern, "*")
+		if ok && strings.HasPrefix(index, prefix) && len(prefix) >= len(longest) {
+			r, longest = clientID, prefix
+		}
+	}
+	if r != "" {
+		return r
+	}
+	return index
+}
+
+// fromECS maps Elastic Common Schema fields of a document to an entry, the other fields go to Payload as JSON.
+// Fields are read nested like {"log":{"level":"error"}} or dotted like {"log.level":"error"}.
+func fromECS(doc map[string]any) *logs.LogEntry {
+	r := &logs.LogEntry{
+		Level:   logs.LogLevel_Infomation,
+		Message: takeString(doc, "message", "log", "msg"),
+		User:    takeString(doc, "user.name", "user.id"),
+		TraceNo: takeString(doc, "trace.id"),
+	}
+
+	if level := takeString(doc, "log.level", "level", "severity"); level != "" {
+		r.Level = textLevel(level)
+	}
+
+	errType, errMessage := takeString(doc, "error.type"), takeString(doc, "error.message")
+	if errType != "" && errMessage != "" {
+		r.Error = errType + ": " + errMessage
+	} else {
+		r.Error = errType + errMessage
+	}
+	r.StackTrace = takeString(doc, "error.stack_trace")
+
+	r.CreatedOnUtc = time.Now().UnixMilli()
+	switch x := take(doc, "@timestamp").(type) {
+	case string:
+		if t, err := time.Parse(time.RFC3339Nano, x); err == nil {
+			r.CreatedOnUtc = t.UnixMilli()
+		}
+	case float64:
+		r.CreatedOnUtc = int64(x)
+	}
+
+	if labels, ok := take(doc, "labels").(map[string]any); ok {
+		r.Labels = make(map[string]string, len(labels))
+		for key, value := range labels {
+			if s, ok := value.(string); ok {
+				r.Labels[key] = s
+			} else {
+				jsonBytes, _ := json.Marshal(value)
+				r.Labels[key] = string(jsonBytes)
+			}
+		}
+	}
+
+	if len(doc) > 0 {
+		jsonBytes, err := json.Marshal(doc)
+		if err == nil {
+			r.Payload = string(jsonBytes)
+		}
+	}
+
+	return r
+}
+
+// takeString removes and returns the first of the fields that is a non-empty string
+func takeString(doc map[string]any, fields ...string) string {
+	for _, field := range fields {
+		if s, ok := get(doc, field).(string); ok && s != "" {
+			remove(doc, field)
+			return s
+		}
+	}
+	return ""
+}
+
+func take(doc map[string]any, field string) any {
+	r := get(doc, field)
+	remove(doc, field)
+	return r
+}
+
+// get returns a dotted or nested field
+func get(doc map[string]any, field string) any {
+	if value, ok := doc[field]; ok {
+		return value
+	}
+	parent, rest, ok := strings.Cut(field, ".")
+	if !ok {
+		return nil
+	}
+	if child, ok := doc[parent].(map[string]any); ok {
+		return get(child, rest)
+	}
+	return nil
+}
+
+// remove removes a dotted or nested field, parents left empty are removed too
+func remove(doc map[string]any, field string) {
+	if _, ok := doc[field]; ok {
+		delete(doc, field)
+		return
+	}
+	parent, rest, ok := strings.Cut(field, ".")
+	if !ok {
+		return
+	}
+	if child, ok := doc[parent].(map[string]any); ok {
+		remove(child, rest)
+		if len(child) == 0 {
+			delete(doc, parent)
+		}
+	}
+}
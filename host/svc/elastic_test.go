@@ -0,0 +1,20 @@
+package svc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestIndexElasticNotConfigured(t *testing.T) {
+	enabled := _elasticEnabled
+	_elasticEnabled = false
+	t.Cleanup(func() { _elasticEnabled = enabled })
+
+	_, err := IndexElastic(context.Background(), []*ElasticDoc{{Index: "web", Source: map[string]any{"message": "hello"}}})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("IndexElastic without an Elastic section = %v, want NotFound", err)
+	}
+}
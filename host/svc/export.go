@@ -0,0 +1,293 @@
+package svc
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/search"
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	EXPORT_NDJSON  = "ndjson"
+	EXPORT_CSV     = "csv"
+	EXPORT_PARQUET = "parquet"
+
+	COMPRESSION_GZIP = "gzip"
+	COMPRESSION_ZSTD = "zstd"
+
+	_EXPORT_ROW_GROUP_SIZE = 10000
+	_EXPORT_CHUNK_BYTES    = 64 * 1024
+)
+
+var (
+	_exportColumns = []string{"ID", "CreatedOnUtc", "Level", "User", "TraceNo", "Message", "Error", "StackTrace", "Payload", "Labels"}
+)
+
+// ValidateExport checks an export before anything is written, so that the caller can still report the error
+func ValidateExport(in *logs.ExportLogEntriesQuery) error {
+	if in == nil || in.Query == nil {
+		return xerr.New("query cannot be nil")
+	}
+	switch in.Format {
+	case "", EXPORT_NDJSON, EXPORT_CSV, EXPORT_PARQUET:
+	default:
+		return xerr.Errorf("unknown format '%s'", in.Format)
+	}
+	switch in.Compression {
+	case "", COMPRESSION_GZIP, COMPRESSION_ZSTD:
+	default:
+		return xerr.Errorf("unknown compression '%s'", in.Compression)
+	}
+	if in.MaxRows < 0 {
+		return xerr.New("MaxRows cannot be negative")
+	}
+
+	q := in.Query
+	if q.ClientID != "" && q.DBName == "" {
+		if q.StartTime == "" {
+			return xerr.New("StartTime cannot be empty when exporting by ClientID")
+		}
+	} else if q.DBName == "" || q.TableName == "" {
+		return xerr.New("DBName and TableName, or ClientID and StartTime are required")
+	}
+	_, err := search.Parse(q.Query)
+	return err
+}
+
+// Export writes all entries matching the query to w, newest first, reading chunks of Export.ChunkSize entries with keyset
+// cursors so that memory stays constant. It stops at MaxRows or Export.MaxRows, whichever is lower, and returns the
+// number of entries written.
+func Export(in *logs.ExportLogEntriesQuery, w io.Writer) (int64, error) {
+	err := ValidateExport(in)
+	if err != nil {
+		return 0, err
+	}
+
+	maxRows := _exportMaxRows
+	if in.MaxRows > 0 {
+		maxRows = min(maxRows, in.MaxRows)
+	}
+
+	var compressor io.WriteCloser
+	switch in.Compression {
+	case COMPRESSION_GZIP:
+		compressor = gzip.NewWriter(w)
+	case COMPRESSION_ZSTD:
+		compressor, err = zstd.NewWriter(w)
+		if err != nil {
+			return 0, xerr.WithStack(err)
+		}
+	}
+	if compressor != nil {
+		w = compressor
+	}
+
+	var writer exportWriter
+	switch in.Format {
+	case EXPORT_CSV:
+		writer = newCSVWriter(w)
+	case EXPORT_PARQUET:
+		writer = newParquetWriter(w)
+	default:
+		writer = &ndjsonWriter{encoder: json.NewEncoder(w)}
+	}
+
+	var count int64
+	query := proto.Clone(in.Query).(*logs.LogEntriesQuery)
+	query.PageIndex = 1
+	query.PageSize = int32(min(int64(_exportChunkSize), maxRows))
+	query.Cursor = ""
+	for count < maxRows {
+		var list []*logs.LogEntry
+		if query.ClientID != "" && query.DBName == "" {
+			list, _, err = getLogEntriesInRange(query)
+		} else {
+			list, _, err = _logDAL.GetLogEntries(query)
+		}
+		if err != nil {
+			return count, err
+		}
+
+		if int64(len(list)) > maxRows-count {
+			list = list[:maxRows-count]
+		}
+		err = writer.Write(list)
+		if err != nil {
+			return count, err
+		}
+		count += int64(len(list))
+
+		if len(list) < int(query.PageSize) {
+			break
+		}
+		last := list[len(list)-1]
+		query.Cursor = (&core.Cursor{CreatedOnUtc: last.CreatedOnUtc, ID: last.ID}).String()
+	}
+
+	err = writer.Close()
+	if err == nil && compressor != nil {
+		err = compressor.Close()
+	}
+	return count, xerr.WithStack(err)
+}
+
+// ExportLogEntries streams an export in chunks of bytes, see Export
+func (o *LogService) ExportLogEntries(in *logs.ExportLogEntriesQuery, stream grpc.ServerStreamingServer[logs.ExportChunk]) error {
+	err := ValidateExport(in)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	buffer := bufio.NewWriterSize(&chunkWriter{stream: stream}, _EXPORT_CHUNK_BYTES)
+	_, err = Export(in, buffer)
+	if err == nil {
+		err = buffer.Flush()
+	}
+	if xerr.LogError(err) {
+		return status.Error(codes.Internal, err.Error())
+	}
+	return nil
+}
+
+type chunkWriter struct {
+	stream grpc.ServerStreamingServer[logs.ExportChunk]
+}
+
+func (o *chunkWriter) Write(p []byte) (int, error) {
+	// Send may keep the message after returning, so the buffer of the caller is copied
+	err := o.stream.Send(&logs.ExportChunk{Data: append([]byte(nil), p...)})
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ************************************************************************************************
+
+type exportWriter interface {
+	Write(entries []*logs.LogEntry) error
+	// Close writes what the format needs at the end, the underlying writer is not closed
+	Close() error
+}
+
+// ndjsonWriter writes entries in the JSON shape of /api/logs, one per line
+type ndjsonWriter struct {
+	encoder *json.Encoder
+}
+
+func (o *ndjsonWriter) Write(entries []*logs.LogEntry) error {
+	for _, x := range entries {
+		err := o.encoder.Encode(x)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+	}
+	return nil
+}
+
+func (o *ndjsonWriter) Close() error {
+	return nil
+}
+
+type csvWriter struct {
+	writer *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	r := &csvWriter{writer: csv.NewWriter(w)}
+	r.writer.Write(_exportColumns)
+	return r
+}
+
+func (o *csvWriter) Write(entries []*logs.LogEntry) error {
+	for _, x := range entries {
+		var labels string
+		if len(x.Labels) > 0 {
+			jsonBytes, _ := json.Marshal(x.Labels)
+			labels = string(jsonBytes)
+		}
+		o.writer.Write([]string{
+			x.ID,
+			strconv.FormatInt(x.CreatedOnUtc, 10),
+			x.Level.String(),
+			x.User,
+			x.TraceNo,
+			x.Message,
+			x.Error,
+			x.StackTrace,
+			x.Payload,
+			labels,
+		})
+	}
+	o.writer.Flush()
+	return xerr.WithStack(o.writer.Error())
+}
+
+func (o *csvWriter) Close() error {
+	return nil
+}
+
+type parquetRow struct {
+	ID           string            `parquet:"ID"`
+	CreatedOnUtc int64             `parquet:"CreatedOnUtc,timestamp(millisecond)"`
+	Level        string            `parquet:"Level,dict"`
+	User         string            `parquet:"User"`
+	TraceNo      string            `parquet:"TraceNo"`
+	Message      string            `parquet:"Message"`
+	Error        string            `parquet:"Error"`
+	StackTrace   string            `parquet:"StackTrace"`
+	Payload      string            `parquet:"Payload"`
+	Labels       map[string]string `parquet:"Labels"`
+}
+
+// parquetWriter buffers at most one row group of _EXPORT_ROW_GROUP_SIZE rows
+type parquetWriter struct {
+	writer *parquet.GenericWriter[parquetRow]
+	rows   []parquetRow
+}
+
+func newParquetWriter(w io.Writer) *parquetWriter {
+	return &parquetWriter{
+		writer: parquet.NewGenericWriter[parquetRow](w,
+			parquet.Compression(&parquet.Snappy),
+			parquet.MaxRowsPerRowGroup(_EXPORT_ROW_GROUP_SIZE),
+		),
+	}
+}
+
+func (o *parquetWriter) Write(entries []*logs.LogEntry) error {
+	o.rows = o.rows[:0]
+	for _, x := range entries {
+		o.rows = append(o.rows, parquetRow{
+			ID:           x.ID,
+			CreatedOnUtc: x.CreatedOnUtc,
+			Level:        x.Level.String(),
+			User:         x.User,
+			TraceNo:      x.TraceNo,
+			Message:      x.Message,
+			Error:        x.Error,
+			StackTrace:   x.StackTrace,
+			Payload:      x.Payload,
+			Labels:       x.Labels,
+		})
+	}
+	_, err := o.writer.Write(o.rows)
+	return xerr.WithStack(err)
+}
+
+func (o *parquetWriter) Close() error {
+	return xerr.WithStack(o.writer.Close())
+}
@@ -0,0 +1,238 @@
+package svc
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"reflect"
+	"strconv"
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/dal/memory"
+	"github.com/klauspost/compress/zstd"
+	"github.com/parquet-go/parquet-go"
+	"google.golang.org/protobuf/proto"
+)
+
+// useExport seeds a table of entries sharing timestamps in pairs, newest first they are e06 e05 e04 e03 e02 e01 e00
+func useExport(t *testing.T, chunkSize int, maxRows int64) (*logs.ExportLogEntriesQuery, []*logs.LogEntry) {
+	logDAL := new(memory.MemoryDAL)
+	useDALs(t, logDAL)
+	chunkSizeBefore, maxRowsBefore := _exportChunkSize, _exportMaxRows
+	t.Cleanup(func() { _exportChunkSize, _exportMaxRows = chunkSizeBefore, maxRowsBefore })
+	_exportChunkSize, _exportMaxRows = chunkSize, maxRows
+
+	var entries []*logs.LogEntry
+	for i := 6; i >= 0; i-- {
+		entries = append(entries, &logs.LogEntry{
+			ID:           "e0" + strconv.Itoa(i),
+			CreatedOnUtc: 1700000000000 + int64(i/2)*1000,
+			Level:        logs.LogLevel(i % 6),
+			User:         "bob",
+			Message:      "line " + strconv.Itoa(i) + ", \"quoted\"\nnext",
+			Payload:      `{"n":` + strconv.Itoa(i) + `}`,
+			Labels:       map[string]string{"env": "prod"},
+		})
+	}
+	err := logDAL.InsertLogEntries("db1", "t1", entries)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &logs.ExportLogEntriesQuery{Query: &logs.LogEntriesQuery{DBName: "db1", TableName: "t1", Level: -1}}, entries
+}
+
+func decompress(t *testing.T, compression string, data []byte) []byte {
+	t.Helper()
+	var reader io.Reader
+	switch compression {
+	case COMPRESSION_GZIP:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		reader = r
+	case COMPRESSION_ZSTD:
+		r, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer r.Close()
+		reader = r
+	default:
+		return data
+	}
+	r, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return r
+}
+
+// readExport decodes an export into entries, the fields of each format are kept
+func readExport(t *testing.T, format string, data []byte) []*logs.LogEntry {
+	t.Helper()
+	var r []*logs.LogEntry
+	switch format {
+	case EXPORT_CSV:
+		records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) == 0 || !reflect.DeepEqual(records[0], _exportColumns) {
+			t.Fatalf("header = %v, want %v", records, _exportColumns)
+		}
+		for _, x := range records[1:] {
+			createdOnUtc, _ := strconv.ParseInt(x[1], 10, 64)
+			var labels map[string]string
+			json.Unmarshal([]byte(x[9]), &labels)
+			r = append(r, &logs.LogEntry{ID: x[0], CreatedOnUtc: createdOnUtc, Level: logs.LogLevel(logs.LogLevel_value[x[2]]),
+				User: x[3], TraceNo: x[4], Message: x[5], Error: x[6], StackTrace: x[7], Payload: x[8], Labels: labels})
+		}
+	case EXPORT_PARQUET:
+		rows, err := parquet.Read[parquetRow](bytes.NewReader(data), int64(len(data)))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, x := range rows {
+			r = append(r, &logs.LogEntry{ID: x.ID, CreatedOnUtc: x.CreatedOnUtc, Level: logs.LogLevel(logs.LogLevel_value[x.Level]),
+				User: x.User, TraceNo: x.TraceNo, Message: x.Message, Error: x.Error, StackTrace: x.StackTrace, Payload: x.Payload, Labels: x.Labels})
+		}
+	default:
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		for scanner.Scan() {
+			entry := new(logs.LogEntry)
+			err := json.Unmarshal(scanner.Bytes(), entry)
+			if err != nil {
+				t.Fatalf("line %s: %v", scanner.Text(), err)
+			}
+			r = append(r, entry)
+		}
+	}
+	return r
+}
+
+func TestExport(t *testing.T) {
+	cases := []struct {
+		format      string
+		compression string
+	}{
+		{"", ""},
+		{EXPORT_NDJSON, COMPRESSION_GZIP},
+		{EXPORT_CSV, ""},
+		{EXPORT_CSV, COMPRESSION_ZSTD},
+		{EXPORT_PARQUET, ""},
+		{EXPORT_PARQUET, COMPRESSION_GZIP},
+	}
+	for _, x := range cases {
+		t.Run(x.format+"/"+x.compression, func(t *testing.T) {
+			in, want := useExport(t, 3, 100)
+			in.Format, in.Compression = x.format, x.compression
+
+			buffer := new(bytes.Buffer)
+			count, err := Export(in, buffer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if count != int64(len(want)) {
+				t.Errorf("count = %d, want %d", count, len(want))
+			}
+
+			got := readExport(t, x.format, decompress(t, x.compression, buffer.Bytes()))
+			if len(got) != len(want) {
+				t.Fatalf("entries = %d, want %d", len(got), len(want))
+			}
+			for i := range want {
+				if !proto.Equal(got[i], want[i]) {
+					t.Errorf("entry %d = %v, want %v", i, got[i], want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExportChunks(t *testing.T) {
+	// Chunks of 2 entries end between entries sharing a timestamp, which the cursor must neither skip nor repeat
+	for _, chunkSize := range []int{1, 2, 3, 7, 8} {
+		t.Run(strconv.Itoa(chunkSize), func(t *testing.T) {
+			in, _ := useExport(t, chunkSize, 100)
+
+			buffer := new(bytes.Buffer)
+			count, err := Export(in, buffer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := readExport(t, EXPORT_NDJSON, buffer.Bytes())
+			ids := make([]string, 0, len(got))
+			for _, x := range got {
+				ids = append(ids, x.ID)
+			}
+			if count != 7 || !reflect.DeepEqual(ids, []string{"e06", "e05", "e04", "e03", "e02", "e01", "e00"}) {
+				t.Errorf("count = %d, ids = %v, want all 7 newest first", count, ids)
+			}
+		})
+	}
+}
+
+func TestExportMaxRows(t *testing.T) {
+	cases := []struct {
+		name          string
+		maxRows       int64
+		exportMaxRows int64
+		want          int64
+	}{
+		{"Request", 3, 100, 3},
+		{"Config", 0, 5, 5},
+		{"Lower", 6, 4, 4},
+		{"MoreThanStored", 20, 100, 7},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			in, want := useExport(t, 2, x.exportMaxRows)
+			in.MaxRows, in.Format = x.maxRows, EXPORT_CSV
+
+			buffer := new(bytes.Buffer)
+			count, err := Export(in, buffer)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := readExport(t, EXPORT_CSV, buffer.Bytes())
+			if count != x.want || int64(len(got)) != x.want {
+				t.Fatalf("count = %d, rows = %d, want %d", count, len(got), x.want)
+			}
+			if got[len(got)-1].ID != want[x.want-1].ID {
+				t.Errorf("last row = %s, want %s", got[len(got)-1].ID, want[x.want-1].ID)
+			}
+		})
+	}
+}
+
+func TestValidateExport(t *testing.T) {
+	table := &logs.LogEntriesQuery{DBName: "db1", TableName: "t1"}
+	cases := []struct {
+		name string
+		in   *logs.ExportLogEntriesQuery
+		ok   bool
+	}{
+		{"Table", &logs.ExportLogEntriesQuery{Query: table}, true},
+		{"Client", &logs.ExportLogEntriesQuery{Query: &logs.LogEntriesQuery{ClientID: "c1", StartTime: "2024-01-01T00:00:00Z"}}, true},
+		{"NilQuery", &logs.ExportLogEntriesQuery{}, false},
+		{"ClientWithoutStart", &logs.ExportLogEntriesQuery{Query: &logs.LogEntriesQuery{ClientID: "c1"}}, false},
+		{"NoTable", &logs.ExportLogEntriesQuery{Query: &logs.LogEntriesQuery{DBName: "db1"}}, false},
+		{"Format", &logs.ExportLogEntriesQuery{Query: table, Format: "xml"}, false},
+		{"Compression", &logs.ExportLogEntriesQuery{Query: table, Compression: "brotli"}, false},
+		{"MaxRows", &logs.ExportLogEntriesQuery{Query: table, MaxRows: -1}, false},
+		{"Search", &logs.ExportLogEntriesQuery{Query: &logs.LogEntriesQuery{DBName: "db1", TableName: "t1", Query: "user:"}}, false},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			if err := ValidateExport(x.in); (err == nil) != x.ok {
+				t.Errorf("ValidateExport = %v, want ok %v", err, x.ok)
+			}
+		})
+	}
+}
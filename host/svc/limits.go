@@ -0,0 +1,205 @@
+package svc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DreamvatLab/logs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	_RETRY_AFTER_HEADER = "retry-after"
+	_DAY_FORMAT         = "2006-01-02"
+)
+
+var (
+	// Usage counters per client id, kept in memory of this host
+	_usages = new(sync.Map)
+)
+
+// LimitError is returned when a client exceeds its rate limit or daily quota
+type LimitError struct {
+	Msg string
+	// Time until retrying can succeed
+	RetryAfter time.Duration
+}
+
+func (o *LimitError) Error() string {
+	return o.Msg
+}
+
+// GRPCStatus makes the error RESOURCE_EXHAUSTED when returned by a handler
+func (o *LimitError) GRPCStatus() *status.Status {
+	return status.New(codes.ResourceExhausted, o.Msg)
+}
+
+// Metadata carries the retry-after seconds, rounded up
+func (o *LimitError) Metadata() metadata.MD {
+	if o.RetryAfter <= 0 {
+		return metadata.MD{}
+	}
+	seconds := int64(math.Ceil(o.RetryAfter.Seconds()))
+	return metadata.Pairs(_RETRY_AFTER_HEADER, strconv.FormatInt(seconds, 10))
+}
+
+type clientUsage struct {
+	locker     sync.Mutex
+	tokens     float64
+	refilledOn time.Time
+	day        string
+	entries    int64
+	bytes      int64
+	rejected   int64
+}
+
+func getUsage(clientID string) *clientUsage {
+	if x, ok := _usages.Load(clientID); ok {
+		return x.(*clientUsage)
+	}
+	x, _ := _usages.LoadOrStore(clientID, &clientUsage{tokens: -1})
+	return x.(*clientUsage)
+}
+
+// limitStatus returns the error ending the call if err is a LimitError, setting the retry-after header,
+// or a status error like a batch larger than the burst, otherwise nil
+func limitStatus(ctx context.Context, err error) error {
+	var limitErr *LimitError
+	if errors.As(err, &limitErr) {
+		grpc.SetHeader(ctx, limitErr.Metadata())
+		return limitErr
+	}
+	if _, ok := status.FromError(err); ok && err != nil {
+		return err
+	}
+	return nil
+}
+
+// rateBurst is the most entries the client can write at once, zero when it has no rate limit
+func rateBurst(client *logs.LogClient) int64 {
+	if client.RateLimit <= 0 {
+		return 0
+	}
+	if client.RateBurst > 0 {
+		return int64(client.RateBurst)
+	}
+	return int64(client.RateLimit)
+}
+
+// admit counts the entries to be stored against the limits of the client,
+// nothing is counted but the rejection when a limit is exceeded.
+// More entries than the burst are rejected with INVALID_ARGUMENT since retrying cannot succeed.
+func admit(client *logs.LogClient, entries []*logs.LogEntry) error {
+	var count, size int64
+	for _, x := range entries {
+		if x != nil && x.Level >= client.Level {
+			count++
+			size += int64(proto.Size(x))
+		}
+	}
+	if count == 0 {
+		return nil
+	}
+
+	usage := getUsage(client.ID)
+	usage.locker.Lock()
+	defer usage.locker.Unlock()
+
+	now := time.Now()
+	if day := now.UTC().Format(_DAY_FORMAT); day != usage.day {
+		usage.day = day
+		usage.entries = 0
+		usage.bytes = 0
+		usage.rejected = 0
+	}
+
+	if burst := rateBurst(client); burst > 0 && count > burst {
+		usage.rejected += count
+		return status.Errorf(codes.InvalidArgument, "%d entries exceed the burst %d of client '%s', send smaller batches", count, burst, client.ID)
+	}
+
+	var err *LimitError
+	if client.RateLimit > 0 {
+		burst := float64(rateBurst(client))
+		// A new bucket starts full
+		if usage.tokens < 0 {
+			usage.tokens = burst
+		} else {
+			usage.tokens = min(burst, usage.tokens+now.Sub(usage.refilledOn).Seconds()*float64(client.RateLimit))
+		}
+		usage.refilledOn = now
+
+		if float64(count) > usage.tokens {
+			wait := (float64(count) - usage.tokens) / float64(client.RateLimit)
+			err = &LimitError{
+				Msg:        fmt.Sprintf("rate limit %d/s of client '%s' exceeded", client.RateLimit, client.ID),
+				RetryAfter: time.Duration(wait * float64(time.Second)),
+			}
+		}
+	}
+
+	if err == nil && client.DailyQuota > 0 && usage.bytes+size > client.DailyQuota {
+		tomorrow := now.UTC().Truncate(time.Hour*24).AddDate(0, 0, 1)
+		err = &LimitError{
+			Msg:        fmt.Sprintf("daily quota %d bytes of client '%s' exceeded", client.DailyQuota, client.ID),
+			RetryAfter: tomorrow.Sub(now),
+		}
+	}
+
+	if err != nil {
+		usage.rejected += count
+		return err
+	}
+
+	if client.RateLimit > 0 {
+		usage.tokens -= float64(count)
+	}
+	usage.entries += count
+	usage.bytes += size
+	return nil
+}
+
+// getUsages returns usages of the client, or all clients when clientID is empty
+func getUsages(clientID string) []*logs.LogClientUsage {
+	today := time.Now().UTC().Format(_DAY_FORMAT)
+	r := make([]*logs.LogClientUsage, 0)
+	_usages.Range(func(key, value any) bool {
+		id := key.(string)
+		if clientID != "" && id != clientID {
+			return true
+		}
+
+		usage := value.(*clientUsage)
+		usage.locker.Lock()
+		x := &logs.LogClientUsage{
+			ClientID: id,
+			Day:      today,
+			Tokens:   int64(max(usage.tokens, 0)),
+		}
+		if usage.day == today {
+			x.Entries = usage.entries
+			x.Bytes = usage.bytes
+			x.Rejected = usage.rejected
+		}
+		usage.locker.Unlock()
+
+		r = append(r, x)
+		return true
+	})
+
+	slices.SortFunc(r, func(a, b *logs.LogClientUsage) int {
+		return strings.Compare(a.ClientID, b.ClientID)
+	})
+	return r
+}
@@ -0,0 +1,453 @@
+package svc
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/loki"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	_LOKI_LEVEL_LABEL = "level"
+)
+
+var (
+	// Level names understood by Grafana
+	_lokiLevels = map[logs.LogLevel]string{
+		logs.LogLevel_Verbose:    "trace",
+		logs.LogLevel_Debug:      "debug",
+		logs.LogLevel_Infomation: "info",
+		logs.LogLevel_Warning:    "warning",
+		logs.LogLevel_Error:      "error",
+		logs.LogLevel_Fatal:      "critical",
+	}
+)
+
+// LokiStream is a stream of a query_range result, values are ["<unix ns>", "line"]
+type LokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// PushLoki writes pushed streams to the clients named by their Loki.ClientLabel label, default "client".
+// The level label becomes Level, the other labels become Labels and structured metadata goes to Payload.
+func PushLoki(ctx context.Context, streams []*loki.Stream) error {
+	clientIDs := make([]string, 0, 1)
+	entries := make(map[string][]*logs.LogEntry, 1)
+	for _, x := range streams {
+		clientID := x.Labels[_lokiClientLabel]
+		if clientID == "" {
+			return status.Errorf(codes.InvalidArgument, "stream label '%s' is required", _lokiClientLabel)
+		}
+		if _, ok := entries[clientID]; !ok {
+			clientIDs = append(clientIDs, clientID)
+		}
+		for _, entry := range x.Entries {
+			entries[clientID] = append(entries[clientID], fromLoki(x.Labels, entry))
+		}
+	}
+
+	// Check all clients first so that a request is not half written
+	for _, clientID := range clientIDs {
+		client, err := _clientDAL.GetClient(clientID)
+		if xerr.LogError(err) {
+			return status.Error(codes.Internal, err.Error())
+		} else if client == nil {
+			return status.Errorf(codes.InvalidArgument, "Client '%s' not found", clientID)
+		}
+		err = authorize(ctx, clientID)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, clientID := range clientIDs {
+		errs, err := write(clientID, entries[clientID])
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			return limitErr
+		} else if status.Code(err) == codes.InvalidArgument {
+			return err // More entries than the burst
+		} else if xerr.LogError(err) {
+			return status.Error(codes.Internal, err.Error())
+		}
+		for _, x := range errs {
+			if xerr.LogError(x) {
+				return status.Error(codes.Internal, x.Error())
+			}
+		}
+	}
+
+	return nil
+}
+
+func fromLoki(labels map[string]string, in *loki.Entry) *logs.LogEntry {
+	r := &logs.LogEntry{
+		Level:   logs.LogLevel_Infomation,
+		Message: in.Line,
+	}
+	if in.Timestamp.UnixNano() > 0 {
+		r.CreatedOnUtc = in.Timestamp.UnixMilli()
+	} else {
+		r.CreatedOnUtc = time.Now().UnixMilli()
+	}
+
+	for key, value := range labels {
+		switch key {
+		case _lokiClientLabel:
+		case _LOKI_LEVEL_LABEL:
+			r.Level = textLevel(value)
+		default:
+			if r.Labels == nil {
+				r.Labels = make(map[string]string, len(labels))
+			}
+			r.Labels[key] = value
+		}
+	}
+
+	if len(in.Metadata) > 0 {
+		jsonBytes, err := json.Marshal(in.Metadata)
+		if err == nil {
+			r.Payload = string(jsonBytes)
+		}
+	}
+
+	return r
+}
+
+// QueryLoki runs a LogQL log query in [start, end), at most limit entries are returned, the newest ones unless forward
+func QueryLoki(query string, start, end time.Time, limit int, forward bool) ([]*LokiStream, error) {
+	q, err := loki.ParseQuery(query)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	clientIDs, searchQuery, err := translateLoki(q)
+	if err != nil {
+		return nil, err
+	}
+
+	startMs, endMs := start.UnixMilli(), end.UnixMilli()
+	var entries []*logs.LogEntry
+	for _, clientID := range clientIDs {
+		entriesQuery := &logs.LogEntriesQuery{
+			ClientID:  clientID,
+			StartTime: start.UTC().Truncate(time.Second).Format(time.RFC3339),
+			EndTime:   end.UTC().Truncate(time.Second).Add(time.Second).Format(time.RFC3339),
+			PageSize:  int32(limit),
+			Query:     searchQuery,
+		}
+		// Keyset cursors bound the page precisely, the oldest entries from start or the newest before end
+		if forward {
+			entriesQuery.Cursor = (&core.Cursor{CreatedOnUtc: startMs - 1, Backward: true}).String()
+		} else {
+			entriesQuery.Cursor = (&core.Cursor{CreatedOnUtc: endMs}).String()
+		}
+
+		list, _, err := getLogEntriesInRange(entriesQuery)
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range list {
+			x.Labels = withClientLabels(clientID, x)
+			if x.CreatedOnUtc >= startMs && x.CreatedOnUtc < endMs {
+				entries = append(entries, x)
+			}
+		}
+	}
+
+	slices.SortFunc(entries, func(a, b *logs.LogEntry) int {
+		c := cmp.Compare(a.CreatedOnUtc, b.CreatedOnUtc)
+		if c == 0 {
+			c = cmp.Compare(a.ID, b.ID)
+		}
+		if forward {
+			return c
+		}
+		return -c
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	return toLokiStreams(entries), nil
+}
+
+// withClientLabels returns the labels of an entry with the client and level labels added
+func withClientLabels(clientID string, entry *logs.LogEntry) map[string]string {
+	r := make(map[string]string, len(entry.Labels)+2)
+	for key, value := range entry.Labels {
+		r[key] = value
+	}
+	r[_lokiClientLabel] = clientID
+	r[_LOKI_LEVEL_LABEL] = _lokiLevels[entry.Level]
+	return r
+}
+
+// toLokiStreams groups entries by their labels, the order of entries is kept in every stream
+func toLokiStreams(entries []*logs.LogEntry) []*LokiStream {
+	r := make([]*LokiStream, 0)
+	streams := make(map[string]*LokiStream)
+	for _, x := range entries {
+		keys := make([]string, 0, len(x.Labels))
+		for key := range x.Labels {
+			keys = append(keys, key)
+		}
+		slices.Sort(keys)
+		sb := new(strings.Builder)
+		for _, key := range keys {
+			sb.WriteString(strconv.Quote(key))
+			sb.WriteString(strconv.Quote(x.Labels[key]))
+		}
+
+		stream, ok := streams[sb.String()]
+		if !ok {
+			stream = &LokiStream{Stream: x.Labels}
+			streams[sb.String()] = stream
+			r = append(r, stream)
+		}
+
+		line := x.Message
+		if line == "" {
+			line = x.Error
+		}
+		ns := strconv.FormatInt(x.CreatedOnUtc*int64(time.Millisecond), 10)
+		stream.Values = append(stream.Values, [2]string{ns, line})
+	}
+	return r
+}
+
+// translateLoki returns the clients selected by the client label, and a search query of the other matchers and the
+// line filters. Regexes of labels stored in the entries must expand to a finite set of values, see loki.Values.
+func translateLoki(q *loki.Query) ([]string, string, error) {
+	clients, err := _clientDAL.GetClients(&logs.LogClientsQuery{})
+	if err != nil {
+		return nil, "", err
+	}
+	clientIDs := make([]string, 0, len(clients))
+	for _, x := range clients {
+		clientIDs = append(clientIDs, x.ID)
+	}
+	slices.Sort(clientIDs)
+
+	var terms []string
+	for _, x := range q.Matchers {
+		switch x.Name {
+		case _lokiClientLabel:
+			match, err := lokiMatcher(x)
+			if err != nil {
+				return nil, "", err
+			}
+			clientIDs = slices.DeleteFunc(clientIDs, func(id string) bool { return !match(id) })
+		case _LOKI_LEVEL_LABEL:
+			match, err := lokiMatcher(x)
+			if err != nil {
+				return nil, "", err
+			}
+			var levels []string
+			for level, name := range _lokiLevels {
+				if match(name) || match(strings.ToLower(level.String())) {
+					levels = append(levels, strconv.Itoa(int(level)))
+				}
+			}
+			if len(levels) == 0 {
+				return nil, "", nil
+			}
+			if len(levels) < len(_lokiLevels) {
+				slices.Sort(levels)
+				terms = append(terms, orTerms("level", levels))
+			}
+		default:
+			term, err := lokiTerm("labels."+x.Name, x.Op, x.Value)
+			if err != nil {
+				return nil, "", err
+			}
+			if term != "" {
+				terms = append(terms, term)
+			}
+		}
+	}
+
+	for _, x := range q.Filters {
+		if x.Value == "" {
+			continue
+		}
+		term, err := lokiTerm("", x.Op, x.Value)
+		if err != nil {
+			return nil, "", err
+		}
+		if term != "" {
+			terms = append(terms, term)
+		}
+	}
+
+	return clientIDs, strings.Join(terms, " "), nil
+}
+
+// lokiMatcher returns a function testing values against a matcher, for labels not stored in the entries
+func lokiMatcher(m *loki.Matcher) (func(string) bool, error) {
+	var match func(string) bool
+	if loki.IsRegex(m.Op) {
+		re, err := regexp.Compile("^(?:" + m.Value + ")$")
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid regex '%s'", m.Value)
+		}
+		match = re.MatchString
+	} else {
+		match = func(s string) bool { return s == m.Value }
+	}
+
+	if loki.IsNegative(m.Op) {
+		return func(s string) bool { return !match(s) }, nil
+	}
+	return match, nil
+}
+
+// lokiTerm translates a matcher of a label, or a line filter when field is empty, to a search term
+func lokiTerm(field, op, value string) (string, error) {
+	var term string
+	if loki.IsRegex(op) {
+		values, matchAll, nonEmpty, ok := loki.Values(value)
+		switch {
+		case !ok:
+			return "", status.Errorf(codes.InvalidArgument, "regex '%s' is not supported, only alternatives of literals like \"a|b\", \".*\" and \".+\" are", value)
+		case matchAll:
+			if loki.IsNegative(op) {
+				// Matches nothing
+				return "", status.Errorf(codes.InvalidArgument, "regex '%s' excludes everything", value)
+			}
+			return "", nil
+		case nonEmpty:
+			if field == "" {
+				term = `""`
+			} else {
+				term = field + ":*"
+			}
+		default:
+			quoted := make([]string, 0, len(values))
+			for _, x := range values {
+				quoted = append(quoted, quoteTerm(x))
+			}
+			term = orTerms(field, quoted)
+		}
+	} else if field == "" {
+		term = quoteTerm(value)
+	} else {
+		term = field + ":" + quoteTerm(value)
+	}
+
+	if loki.IsNegative(op) {
+		term = "NOT " + term
+	}
+	return term, nil
+}
+
+// orTerms joins field:value terms with OR, values are bare words or quoted already
+func orTerms(field string, values []string) string {
+	terms := make([]string, 0, len(values))
+	for _, x := range values {
+		if field == "" {
+			terms = append(terms, x)
+		} else {
+			terms = append(terms, field+":"+x)
+		}
+	}
+	if len(terms) == 1 {
+		return terms[0]
+	}
+	return "(" + strings.Join(terms, " OR ") + ")"
+}
+
+func quoteTerm(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// LokiLabels returns the label names of entries in the range, sampled by Loki.LabelSampleSize per client
+func LokiLabels(start, end time.Time) ([]string, error) {
+	labels, err := sampleLokiLabels(start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	r := make([]string, 0, len(labels)+2)
+	r = append(r, _lokiClientLabel, _LOKI_LEVEL_LABEL)
+	for key := range labels {
+		if key != _lokiClientLabel && key != _LOKI_LEVEL_LABEL {
+			r = append(r, key)
+		}
+	}
+	slices.Sort(r)
+	return r, nil
+}
+
+// LokiLabelValues returns the values of a label, sampled like LokiLabels unless it's the client or level label
+func LokiLabelValues(name string, start, end time.Time) ([]string, error) {
+	r := make([]string, 0)
+	switch name {
+	case _lokiClientLabel:
+		clients, err := _clientDAL.GetClients(&logs.LogClientsQuery{})
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range clients {
+			r = append(r, x.ID)
+		}
+	case _LOKI_LEVEL_LABEL:
+		for _, x := range _lokiLevels {
+			r = append(r, x)
+		}
+	default:
+		labels, err := sampleLokiLabels(start, end)
+		if err != nil {
+			return nil, err
+		}
+		for value := range labels[name] {
+			r = append(r, value)
+		}
+	}
+
+	slices.Sort(r)
+	return r, nil
+}
+
+// sampleLokiLabels collects label names and values from the newest entries of every client in the range
+func sampleLokiLabels(start, end time.Time) (map[string]map[string]bool, error) {
+	clients, err := _clientDAL.GetClients(&logs.LogClientsQuery{})
+	if err != nil {
+		return nil, err
+	}
+
+	r := make(map[string]map[string]bool)
+	for _, client := range clients {
+		list, _, err := getLogEntriesInRange(&logs.LogEntriesQuery{
+			ClientID:  client.ID,
+			StartTime: start.UTC().Truncate(time.Second).Format(time.RFC3339),
+			EndTime:   end.UTC().Truncate(time.Second).Add(time.Second).Format(time.RFC3339),
+			PageSize:  int32(_lokiSampleSize),
+			PageIndex: 1,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, x := range list {
+			for key, value := range x.Labels {
+				if r[key] == nil {
+					r[key] = make(map[string]bool)
+				}
+				r[key][value] = true
+			}
+		}
+	}
+	return r, nil
+}
@@ -0,0 +1,82 @@
+package svc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/dal/memory"
+	"github.com/DreamvatLab/logs/host/loki"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestTranslateLoki(t *testing.T) {
+	useDALs(t, new(memory.MemoryDAL), &logs.LogClient{ID: "web"}, &logs.LogClient{ID: "api-1"}, &logs.LogClient{ID: "api-2"})
+	defer func(label string) { _lokiClientLabel = label }(_lokiClientLabel)
+	_lokiClientLabel = "client"
+
+	cases := []struct {
+		query   string
+		clients []string
+		search  string
+	}{
+		{`{client="web"}`, []string{"web"}, ""},
+		{`{client!="web"}`, []string{"api-1", "api-2"}, ""},
+		{`{client=~"api-.*"}`, []string{"api-1", "api-2"}, ""},
+		{`{client="none"}`, []string{}, ""},
+		{`{env="prod"}`, []string{"api-1", "api-2", "web"}, `labels.env:"prod"`},
+		{`{env!="prod"}`, []string{"api-1", "api-2", "web"}, `NOT labels.env:"prod"`},
+		{`{client="web", env=~"prod|stage"}`, []string{"web"}, `(labels.env:"prod" OR labels.env:"stage")`},
+		{`{client="web", env=~".*"}`, []string{"web"}, ""},
+		{`{client="web", env=~".+"}`, []string{"web"}, "labels.env:*"},
+		{`{client="web", env!~".+"}`, []string{"web"}, "NOT labels.env:*"},
+		{`{client="web", level="error"}`, []string{"web"}, "level:4"},
+		{`{client="web", level=~"error|critical"}`, []string{"web"}, "(level:4 OR level:5)"},
+		{`{client="web", level=~".*"}`, []string{"web"}, ""},
+		{`{client="web"} |= "time out" != "retry"`, []string{"web"}, `"time out" NOT "retry"`},
+		{`{client="web"} |= ` + "`say \"hi\" \\ now`", []string{"web"}, `"say \"hi\" \\ now"`},
+		{`{client="web"} |~ "a|b" |= ""`, []string{"web"}, `("a" OR "b")`},
+		{`{client="web"} |~ ".+"`, []string{"web"}, `""`},
+	}
+	for _, x := range cases {
+		t.Run(x.query, func(t *testing.T) {
+			q, err := loki.ParseQuery(x.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			clients, search, err := translateLoki(q)
+			if err != nil {
+				t.Fatalf("translateLoki = %v", err)
+			}
+			if !reflect.DeepEqual(clients, x.clients) || search != x.search {
+				t.Errorf("translateLoki = %v %q, want %v %q", clients, search, x.clients, x.search)
+			}
+		})
+	}
+}
+
+func TestTranslateLokiErrors(t *testing.T) {
+	useDALs(t, new(memory.MemoryDAL), &logs.LogClient{ID: "web"})
+	defer func(label string) { _lokiClientLabel = label }(_lokiClientLabel)
+	_lokiClientLabel = "client"
+
+	cases := []string{
+		`{client=~"("}`,
+		`{client="web", env=~"prod.*"}`,
+		`{client="web", env=~"(?i)prod"}`,
+		`{client="web", env!~".*"}`,
+		`{client="web"} |~ "err[a-z]+"`,
+	}
+	for _, x := range cases {
+		t.Run(x, func(t *testing.T) {
+			q, err := loki.ParseQuery(x)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, _, err = translateLoki(q); status.Code(err) != codes.InvalidArgument {
+				t.Errorf("translateLoki = %v, want InvalidArgument", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,265 @@
+package svc
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	collogspb "go.opentelemetry.io/proto/otlp/collector/logs/v1"
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	_OTLP_EXCEPTION_TYPE       = "exception.type"
+	_OTLP_EXCEPTION_MESSAGE    = "exception.message"
+	_OTLP_EXCEPTION_STACKTRACE = "exception.stacktrace"
+	_OTLP_USER_ID              = "user.id"
+	_OTLP_ENDUSER_ID           = "enduser.id"
+)
+
+// OTLPLogsService is the OpenTelemetry logs collector, records are written to the client named by
+// the resource attribute OTLP.ClientAttribute, default "service.name"
+type OTLPLogsService struct {
+	collogspb.UnimplementedLogsServiceServer
+}
+
+func (o *OTLPLogsService) Export(ctx context.Context, in *collogspb.ExportLogsServiceRequest) (*collogspb.ExportLogsServiceResponse, error) {
+	clientIDs := make([]string, 0, 1)
+	entries := make(map[string][]*logs.LogEntry, 1)
+	for _, x := range in.ResourceLogs {
+		clientID := resourceClientID(x)
+		if _, ok := entries[clientID]; !ok {
+			clientIDs = append(clientIDs, clientID)
+		}
+		for _, scope := range x.ScopeLogs {
+			for _, record := range scope.LogRecords {
+				entries[clientID] = append(entries[clientID], fromOTLP(record))
+			}
+		}
+	}
+
+	// Authorize all clients first so that a request is not half written because of auth
+	for _, clientID := range clientIDs {
+		if clientID == "" {
+			return nil, status.Errorf(codes.InvalidArgument, "resource attribute '%s' is required", _otlpClientAttribute)
+		}
+		err := authorize(ctx, clientID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var rejected int64
+	var messages []string
+	for _, clientID := range clientIDs {
+		errs, err := write(clientID, entries[clientID])
+		if limitErr := limitStatus(ctx, err); limitErr != nil {
+			return nil, limitErr
+		} else if xerr.LogError(err) {
+			rejected += int64(len(entries[clientID]))
+			messages = append(messages, err.Error())
+			continue
+		}
+		for _, x := range errs {
+			if x != nil {
+				rejected++
+				messages = append(messages, x.Error())
+			}
+		}
+	}
+
+	r := new(collogspb.ExportLogsServiceResponse)
+	if rejected > 0 {
+		r.PartialSuccess = &collogspb.ExportLogsPartialSuccess{
+			RejectedLogRecords: rejected,
+			ErrorMessage:       strings.Join(messages, "; "),
+		}
+	}
+	return r, nil
+}
+
+func resourceClientID(in *logspb.ResourceLogs) string {
+	for _, x := range in.GetResource().GetAttributes() {
+		if x.Key == _otlpClientAttribute {
+			return x.Value.GetStringValue()
+		}
+	}
+	return ""
+}
+
+// fromOTLP maps a log record to an entry, attributes not mapped to a field go to Payload as JSON
+func fromOTLP(in *logspb.LogRecord) *logs.LogEntry {
+	r := &logs.LogEntry{
+		Level: otlpLevel(in.SeverityNumber, in.SeverityText),
+	}
+
+	switch {
+	case in.TimeUnixNano > 0:
+		r.CreatedOnUtc = int64(in.TimeUnixNano / uint64(time.Millisecond))
+	case in.ObservedTimeUnixNano > 0:
+		r.CreatedOnUtc = int64(in.ObservedTimeUnixNano / uint64(time.Millisecond))
+	default:
+		r.CreatedOnUtc = time.Now().UnixMilli()
+	}
+
+	if body := in.Body; body != nil {
+		if s, ok := body.Value.(*commonpb.AnyValue_StringValue); ok {
+			r.Message = s.StringValue
+		} else {
+			jsonBytes, _ := json.Marshal(anyValue(body))
+			r.Message = string(jsonBytes)
+		}
+	}
+
+	if len(in.TraceId) > 0 && strings.Trim(string(in.TraceId), "\x00") != "" {
+		r.TraceNo = hex.EncodeToString(in.TraceId)
+	}
+
+	var exceptionType, exceptionMessage string
+	payload := make(map[string]any)
+	for _, x := range in.Attributes {
+		switch x.Key {
+		case _OTLP_EXCEPTION_TYPE:
+			exceptionType = x.Value.GetStringValue()
+		case _OTLP_EXCEPTION_MESSAGE:
+			exceptionMessage = x.Value.GetStringValue()
+		case _OTLP_EXCEPTION_STACKTRACE:
+			r.StackTrace = x.Value.GetStringValue()
+		case _OTLP_USER_ID, _OTLP_ENDUSER_ID:
+			r.User = x.Value.GetStringValue()
+		default:
+			payload[x.Key] = anyValue(x.Value)
+		}
+	}
+	if exceptionType != "" && exceptionMessage != "" {
+		r.Error = exceptionType + ": " + exceptionMessage
+	} else {
+		r.Error = exceptionType + exceptionMessage
+	}
+
+	if len(payload) > 0 {
+		jsonBytes, err := json.Marshal(payload)
+		if err == nil {
+			r.Payload = string(jsonBytes)
+		}
+	}
+
+	return r
+}
+
+// otlpLevel maps severity ranges TRACE, DEBUG, INFO, WARN, ERROR and FATAL, the text is used when the number is unspecified
+func otlpLevel(number logspb.SeverityNumber, text string) logs.LogLevel {
+	switch {
+	case number <= logspb.SeverityNumber_SEVERITY_NUMBER_UNSPECIFIED:
+		return textLevel(text)
+	case number < logspb.SeverityNumber_SEVERITY_NUMBER_DEBUG:
+		return logs.LogLevel_Verbose
+	case number < logspb.SeverityNumber_SEVERITY_NUMBER_INFO:
+		return logs.LogLevel_Debug
+	case number < logspb.SeverityNumber_SEVERITY_NUMBER_WARN:
+		return logs.LogLevel_Infomation
+	case number < logspb.SeverityNumber_SEVERITY_NUMBER_ERROR:
+		return logs.LogLevel_Warning
+	case number < logspb.SeverityNumber_SEVERITY_NUMBER_FATAL:
+		return logs.LogLevel_Error
+	default:
+		return logs.LogLevel_Fatal
+	}
+}
+
+// textLevel maps common level names of logging libraries, unknown names are Infomation
+func textLevel(text string) logs.LogLevel {
+	switch strings.ToLower(text) {
+	case "trace", "verbose":
+		return logs.LogLevel_Verbose
+	case "debug", "dbg":
+		return logs.LogLevel_Debug
+	case "warn", "warning":
+		return logs.LogLevel_Warning
+	case "error", "err":
+		return logs.LogLevel_Error
+	case "fatal", "critical", "crit", "panic", "emerg", "alert":
+		return logs.LogLevel_Fatal
+	default:
+		return logs.LogLevel_Infomation
+	}
+}
+
+func anyValue(in *commonpb.AnyValue) any {
+	switch x := in.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return x.StringValue
+	case *commonpb.AnyValue_BoolValue:
+		return x.BoolValue
+	case *commonpb.AnyValue_IntValue:
+		return x.IntValue
+	case *commonpb.AnyValue_DoubleValue:
+		return x.DoubleValue
+	case *commonpb.AnyValue_BytesValue:
+		return x.BytesValue
+	case *commonpb.AnyValue_ArrayValue:
+		r := make([]any, 0, len(x.ArrayValue.GetValues()))
+		for _, v := range x.ArrayValue.GetValues() {
+			r = append(r, anyValue(v))
+		}
+		return r
+	case *commonpb.AnyValue_KvlistValue:
+		r := make(map[string]any, len(x.KvlistValue.GetValues()))
+		for _, v := range x.KvlistValue.GetValues() {
+			r[v.Key] = anyValue(v.Value)
+		}
+		return r
+	default:
+		return nil
+	}
+}
+
+// UnmarshalOTLPJSON decodes an OTLP/JSON request, its traceId and spanId are hex instead of the base64 of protojson
+func UnmarshalOTLPJSON(jsonBytes []byte, in *collogspb.ExportLogsServiceRequest) error {
+	var request map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(jsonBytes))
+	decoder.UseNumber() // Keeps nanoseconds exact
+	err := decoder.Decode(&request)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+
+	resourceLogs, _ := request["resourceLogs"].([]any)
+	for _, x := range resourceLogs {
+		resource, _ := x.(map[string]any)
+		scopeLogs, _ := resource["scopeLogs"].([]any)
+		for _, y := range scopeLogs {
+			scope, _ := y.(map[string]any)
+			records, _ := scope["logRecords"].([]any)
+			for _, z := range records {
+				record, _ := z.(map[string]any)
+				for _, key := range []string{"traceId", "spanId"} {
+					id, ok := record[key].(string)
+					if !ok || id == "" {
+						continue
+					}
+					idBytes, err := hex.DecodeString(id)
+					if err != nil {
+						return xerr.Errorf("invalid %s '%s'", key, id)
+					}
+					record[key] = idBytes
+				}
+			}
+		}
+	}
+
+	jsonBytes, err = json.Marshal(request)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	return xerr.WithStack(protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(jsonBytes, in))
+}
@@ -0,0 +1,207 @@
+package svc
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// Max partitions a time range query can span
+	_MAX_PARTITIONS = 1000
+	// Max partitions queried at the same time
+	_PARTITION_PARALLELISM = 8
+	// Max rows up to the end of a page, every partition returns that many rows, deeper pages use Cursor
+	_MAX_RANGE_ROWS = 10000
+)
+
+type partitionName struct {
+	DBName    string
+	TableName string
+}
+
+// floorTime returns the start of the table containing t under a DBPolicy
+func floorTime(policy int32, t time.Time) time.Time {
+	switch policy {
+	case 1: // Table per month
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case 2: // Table per day
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	case 3: // Table per hour
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), 0, 0, 0, t.Location())
+	default: // Table per year
+		return time.Date(t.Year(), 1, 1, 0, 0, 0, 0, t.Location())
+	}
+}
+
+// nextTime returns the start of the table after the one starting at t
+func nextTime(policy int32, t time.Time) time.Time {
+	switch policy {
+	case 1:
+		return t.AddDate(0, 1, 0)
+	case 2:
+		return t.AddDate(0, 0, 1)
+	case 3:
+		return time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, t.Location())
+	default:
+		return t.AddDate(1, 0, 0)
+	}
+}
+
+// partitionsInRange returns the existing partitions of a client covering the time range, newest first
+func partitionsInRange(client *logs.LogClient, start, end time.Time) ([]partitionName, error) {
+	// Partitions are named in local time, same as write
+	start = start.Local()
+	end = end.Local()
+
+	var candidates []partitionName
+	for t := floorTime(client.DBPolicy, start); !t.After(end); t = nextTime(client.DBPolicy, t) {
+		if len(candidates) >= _MAX_PARTITIONS {
+			return nil, xerr.Errorf("time range spans more than %d partitions", _MAX_PARTITIONS)
+		}
+		dbName, tableName := partition(client, t)
+		candidates = append(candidates, partitionName{DBName: dbName, TableName: tableName})
+	}
+
+	// Keep existing ones only
+	databases, err := _logDAL.GetDatabases(client.ID)
+	if err != nil {
+		return nil, err
+	}
+	tables := make(map[string][]string, len(databases))
+	for _, x := range databases {
+		tables[x] = nil
+	}
+
+	r := make([]partitionName, 0, len(candidates))
+	for i := len(candidates) - 1; i >= 0; i-- {
+		x := candidates[i]
+		dbTables, ok := tables[x.DBName]
+		if !ok {
+			continue
+		}
+		if dbTables == nil {
+			dbTables, err = _logDAL.GetTables(x.DBName)
+			if err != nil {
+				return nil, err
+			}
+			if dbTables == nil {
+				dbTables = make([]string, 0)
+			}
+			tables[x.DBName] = dbTables
+		}
+		if slices.Contains(dbTables, x.TableName) {
+			r = append(r, x)
+		}
+	}
+
+	return r, nil
+}
+
+// getLogEntriesInRange queries all partitions of query.ClientID between StartTime and EndTime,
+// results of partitions are merged in (CreatedOnUtc, ID) descending order and paginated together
+func getLogEntriesInRange(query *logs.LogEntriesQuery) ([]*logs.LogEntry, int64, error) {
+	if query.StartTime == "" {
+		return nil, 0, xerr.New("StartTime cannot be empty when querying by ClientID")
+	}
+	if query.PageSize < 1 {
+		return nil, 0, xerr.New("page size must be greater than 0")
+	}
+	if query.Cursor == "" && query.PageIndex < 1 {
+		return nil, 0, xerr.New("page index must be greater than 0")
+	}
+
+	// Every partition returns enough entries to fill the requested page on its own
+	pageIndex, pageSize := int64(query.PageIndex), int64(query.PageSize)
+	if query.Cursor != "" {
+		pageIndex = 1
+	}
+	limit := pageIndex * pageSize
+	if limit > _MAX_RANGE_ROWS {
+		return nil, 0, xerr.Errorf("pages are limited to the first %d entries, use Cursor for deeper pages", _MAX_RANGE_ROWS)
+	}
+
+	start, err := time.ParseInLocation(time.RFC3339, query.StartTime, time.UTC)
+	if err != nil {
+		return nil, 0, xerr.WithStack(err)
+	}
+	end := time.Now()
+	if query.EndTime != "" {
+		end, err = time.ParseInLocation(time.RFC3339, query.EndTime, time.UTC)
+		if err != nil {
+			return nil, 0, xerr.WithStack(err)
+		}
+	}
+
+	client, err := _clientDAL.GetClient(query.ClientID)
+	if err != nil {
+		return nil, 0, err
+	} else if client == nil {
+		return nil, 0, xerr.Errorf("Client '%s' not found", query.ClientID)
+	}
+
+	partitions, err := partitionsInRange(client, start, end)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	lists := make([][]*logs.LogEntry, len(partitions))
+	counts := make([]int64, len(partitions))
+	errs := make([]error, len(partitions))
+	semaphore := make(chan struct{}, _PARTITION_PARALLELISM)
+	wg := new(sync.WaitGroup)
+	for i, x := range partitions {
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func() {
+			defer func() {
+				<-semaphore
+				wg.Done()
+			}()
+
+			partitionQuery := proto.Clone(query).(*logs.LogEntriesQuery)
+			partitionQuery.ClientID = ""
+			partitionQuery.DBName = x.DBName
+			partitionQuery.TableName = x.TableName
+			partitionQuery.PageIndex = 1
+			partitionQuery.PageSize = int32(limit)
+			lists[i], counts[i], errs[i] = _logDAL.GetLogEntries(partitionQuery)
+		}()
+	}
+	wg.Wait()
+
+	err = errors.Join(errs...)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var totalCount int64
+	merged := make([]*logs.LogEntry, 0, limit)
+	for i := range partitions {
+		totalCount += counts[i]
+		merged = append(merged, lists[i]...)
+	}
+	slices.SortFunc(merged, func(a, b *logs.LogEntry) int {
+		if c := cmp.Compare(b.CreatedOnUtc, a.CreatedOnUtc); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.ID, a.ID)
+	})
+
+	var from, to int
+	if cursor, err := core.ParseCursor(query.Cursor); err == nil && cursor.Backward {
+		// Backward pages are the newer entries closest to the cursor
+		from, to = max(len(merged)-int(pageSize), 0), len(merged)
+	} else {
+		from, to = min(int((pageIndex-1)*pageSize), len(merged)), min(int(limit), len(merged))
+	}
+
+	return merged[from:to], totalCount, nil
+}
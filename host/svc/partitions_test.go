@@ -0,0 +1,39 @@
+package svc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/dal/memory"
+)
+
+func TestGetLogEntriesInRangeDeepPage(t *testing.T) {
+	useDALs(t, new(memory.MemoryDAL), &logs.LogClient{ID: "c1"})
+	cursor := (&core.Cursor{CreatedOnUtc: 1, ID: "e01"}).String()
+
+	cases := []struct {
+		name      string
+		pageIndex int32
+		pageSize  int32
+		cursor    string
+		ok        bool
+	}{
+		{"FirstPages", 100, 100, "", true},
+		{"Overflow", 50000, 50000, "", false},
+		{"Deep", 101, 100, "", false},
+		{"CursorPage", 50000, 100, cursor, true},
+		{"CursorLargePage", 1, 20000, cursor, false},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			_, _, err := getLogEntriesInRange(&logs.LogEntriesQuery{ClientID: "c1", Level: -1, StartTime: "2024-01-01T00:00:00Z", PageIndex: x.pageIndex, PageSize: x.pageSize, Cursor: x.cursor})
+			if x.ok && err != nil {
+				t.Errorf("getLogEntriesInRange = %v, want nil", err)
+			} else if !x.ok && (err == nil || !strings.Contains(err.Error(), "Cursor")) {
+				t.Errorf("getLogEntriesInRange = %v, want an error pointing to Cursor", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package svc
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DreamvatLab/go/xlog"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+)
+
+var (
+	// Layouts of database name suffixes per DBPolicy, see partition()
+	_dbLayouts = map[int32]string{
+		1: "2006",
+		2: "200601",
+		3: "20060102",
+	}
+
+	_retentionLocker     = new(sync.Mutex)
+	_lastRetentionReport *RetentionReport
+)
+
+type RetentionItem struct {
+	ClientID string
+	DBName   string
+	// Empty when the whole database is dropped
+	TableName string
+	Error     string `json:",omitempty"`
+}
+
+type RetentionReport struct {
+	// Nothing is dropped in dry run, Dropped lists what would be
+	DryRun        bool
+	StartedOnUtc  int64
+	FinishedOnUtc int64
+	Dropped       []*RetentionItem
+	Failed        []*RetentionItem
+}
+
+func runJanitor(interval time.Duration, dryRun bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		RunRetention(dryRun)
+		<-ticker.C
+	}
+}
+
+// LastRetentionReport returns the report of the latest run, nil if never run
+func LastRetentionReport() *RetentionReport {
+	_retentionLocker.Lock()
+	defer _retentionLocker.Unlock()
+	return _lastRetentionReport
+}
+
+// RunRetention drops the partitions of all clients older than their RetentionDays
+func RunRetention(dryRun bool) *RetentionReport {
+	_retentionLocker.Lock()
+	defer _retentionLocker.Unlock()
+
+	r := &RetentionReport{
+		DryRun:       dryRun,
+		StartedOnUtc: time.Now().UnixMilli(),
+		Dropped:      make([]*RetentionItem, 0),
+		Failed:       make([]*RetentionItem, 0),
+	}
+
+	clients, err := _clientDAL.GetClients(&logs.LogClientsQuery{})
+	if err != nil {
+		r.Failed = append(r.Failed, &RetentionItem{Error: err.Error()})
+	}
+	for _, x := range clients {
+		if x.RetentionDays > 0 {
+			cleanClient(r, x, clients)
+		}
+	}
+
+	r.FinishedOnUtc = time.Now().UnixMilli()
+	_lastRetentionReport = r
+
+	prefix := ""
+	if dryRun {
+		prefix = "[dry run] "
+	}
+	for _, x := range r.Dropped {
+		xlog.Infof("%sRetention dropped '%s' '%s' of client '%s'", prefix, x.DBName, x.TableName, x.ClientID)
+	}
+	for _, x := range r.Failed {
+		xlog.Warnf("%sRetention failed to drop '%s' '%s' of client '%s': %s", prefix, x.DBName, x.TableName, x.ClientID, x.Error)
+	}
+
+	return r
+}
+
+func cleanClient(report *RetentionReport, client *logs.LogClient, clients []*logs.LogClient) {
+	// Partitions are named in local time, same as write
+	cutoff := time.Now().AddDate(0, 0, -int(client.RetentionDays))
+
+	databases, err := _logDAL.GetDatabases(client.ID)
+	if err != nil {
+		report.Failed = append(report.Failed, &RetentionItem{ClientID: client.ID, Error: err.Error()})
+		return
+	}
+
+	for _, dbName := range databases {
+		dbStart, ok := databaseStart(client, dbName)
+		if !ok || ownedByOther(client, dbName, clients) {
+			continue
+		}
+
+		// A database of policy N covers what a table of policy N-1 does
+		if client.DBPolicy > 0 && !nextTime(client.DBPolicy-1, dbStart).After(cutoff) {
+			item := &RetentionItem{ClientID: client.ID, DBName: dbName}
+			var err error
+			if !report.DryRun {
+				err = _logDAL.DropDatabase(dbName)
+			}
+			addItem(report, item, err)
+			continue
+		}
+
+		tables, err := _logDAL.GetTables(dbName)
+		if err != nil {
+			report.Failed = append(report.Failed, &RetentionItem{ClientID: client.ID, DBName: dbName, Error: err.Error()})
+			continue
+		}
+		for _, tableName := range tables {
+			start, ok := tableStart(client, dbStart, tableName)
+			if !ok || nextTime(client.DBPolicy, start).After(cutoff) {
+				continue
+			}
+
+			item := &RetentionItem{ClientID: client.ID, DBName: dbName, TableName: tableName}
+			var err error
+			if !report.DryRun {
+				err = _logDAL.DropTable(dbName, tableName)
+			}
+			addItem(report, item, err)
+		}
+	}
+}
+
+func addItem(report *RetentionReport, item *RetentionItem, err error) {
+	if err != nil {
+		item.Error = err.Error()
+		report.Failed = append(report.Failed, item)
+	} else {
+		report.Dropped = append(report.Dropped, item)
+	}
+}
+
+// databaseStart parses the start time of a database named by partition(), ok is false for other databases
+func databaseStart(client *logs.LogClient, dbName string) (time.Time, bool) {
+	suffix, found := strings.CutPrefix(dbName, core.LOG_DB_PREFIX+client.ID)
+	if !found {
+		return time.Time{}, false
+	}
+
+	layout, ok := _dbLayouts[client.DBPolicy]
+	if !ok {
+		// Single database
+		return time.Time{}, suffix == ""
+	}
+
+	suffix, found = strings.CutPrefix(suffix, "_")
+	if !found || len(suffix) != len(layout) {
+		return time.Time{}, false
+	}
+	t, err := time.ParseInLocation(layout, suffix, time.Local)
+	return t, err == nil
+}
+
+// tableStart parses the start time of a table named by partition() in a database starting at dbStart
+func tableStart(client *logs.LogClient, dbStart time.Time, tableName string) (time.Time, bool) {
+	n, err := strconv.Atoi(tableName)
+	if err != nil || len(tableName) < 2 {
+		return time.Time{}, false
+	}
+
+	y, m, d := dbStart.Date()
+	switch client.DBPolicy {
+	case 1: // Month tables
+		if n < 1 || n > 12 {
+			return time.Time{}, false
+		}
+		return time.Date(y, time.Month(n), 1, 0, 0, 0, 0, time.Local), true
+	case 2: // Day tables
+		t := time.Date(y, m, n, 0, 0, 0, 0, time.Local)
+		return t, n >= 1 && t.Month() == m
+	case 3: // Hour tables
+		return time.Date(y, m, d, n, 0, 0, 0, time.Local), n >= 0 && n <= 23
+	default: // Year tables
+		return time.Date(n, 1, 1, 0, 0, 0, 0, time.Local), len(tableName) == 4
+	}
+}
+
+// ownedByOther reports whether a database also matches the naming of a client with a longer ID,
+// e.g. LOG_DL_2024 belongs to client DL_2024 rather than being the 2024 database of client DL
+func ownedByOther(client *logs.LogClient, dbName string, clients []*logs.LogClient) bool {
+	for _, x := range clients {
+		if len(x.ID) > len(client.ID) && strings.HasPrefix(x.ID, client.ID) {
+			if _, ok := databaseStart(x, dbName); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package svc
+
+import (
+	"encoding/json"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xlog"
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/host/core"
+	"github.com/DreamvatLab/logs/host/syslog"
+)
+
+type syslogConfig struct {
+	// Listen addresses, a listener is disabled when its address is empty
+	UDPAddr string
+	TCPAddr string
+	// Maximum bytes of a message, default 64KB
+	MaxMessageSize int
+	// App-name or hostname to client id, the app-name is looked up first
+	Clients map[string]string
+	// Client of messages not matching Clients, they are dropped when it's empty
+	DefaultClient string
+}
+
+// ServeSyslog starts the syslog listeners configured under "Syslog". Syslog has no credentials,
+// so the mapping in the configuration decides which clients a sender may write.
+func ServeSyslog() error {
+	var config syslogConfig
+	err := core.ServiceConfigProvider.GetStruct("Syslog", &config)
+	if err != nil {
+		return xerr.WithStack(err)
+	}
+	if config.UDPAddr == "" && config.TCPAddr == "" {
+		return nil
+	}
+	if config.MaxMessageSize <= 0 {
+		config.MaxMessageSize = 64 * 1024
+	}
+
+	handler := func(msg *syslog.Message, remote net.Addr) {
+		clientID := syslogClientID(&config, msg)
+		if clientID == "" {
+			xlog.Debugf("Syslog message from %s dropped, no client for app '%s' host '%s'", remote, msg.AppName, msg.Hostname)
+			return
+		}
+
+		errs, err := write(clientID, []*logs.LogEntry{fromSyslog(msg)})
+		var limitErr *LimitError
+		if errors.As(err, &limitErr) {
+			return // Counted as rejected in the usage
+		} else if !xerr.LogError(err) && len(errs) > 0 {
+			xerr.LogError(errs[0])
+		}
+	}
+
+	if config.UDPAddr != "" {
+		conn, err := net.ListenPacket("udp", config.UDPAddr)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+		xlog.Infof("Syslog UDP listen on %s", config.UDPAddr)
+		go func() {
+			xerr.LogError(syslog.ServeUDP(conn, config.MaxMessageSize, handler))
+		}()
+	}
+
+	if config.TCPAddr != "" {
+		lis, err := net.Listen("tcp", config.TCPAddr)
+		if err != nil {
+			return xerr.WithStack(err)
+		}
+		xlog.Infof("Syslog TCP listen on %s", config.TCPAddr)
+		go func() {
+			xerr.LogError(syslog.ServeTCP(lis, config.MaxMessageSize, handler))
+		}()
+	}
+
+	return nil
+}
+
+func syslogClientID(config *syslogConfig, msg *syslog.Message) string {
+	if clientID, ok := config.Clients[msg.AppName]; ok && msg.AppName != "" {
+		return clientID
+	}
+	if clientID, ok := config.Clients[msg.Hostname]; ok && msg.Hostname != "" {
+		return clientID
+	}
+	return config.DefaultClient
+}
+
+// fromSyslog maps a message to an entry, the header fields and structured data go to Payload as JSON
+func fromSyslog(msg *syslog.Message) *logs.LogEntry {
+	r := &logs.LogEntry{
+		Level:   syslogLevel(msg.Severity),
+		Message: msg.Message,
+	}
+	if msg.Timestamp.IsZero() {
+		r.CreatedOnUtc = time.Now().UnixMilli()
+	} else {
+		r.CreatedOnUtc = msg.Timestamp.UnixMilli()
+	}
+
+	payload := map[string]any{
+		"facility": msg.Facility,
+		"severity": msg.Severity,
+	}
+	for key, value := range map[string]string{
+		"hostname": msg.Hostname,
+		"appName":  msg.AppName,
+		"procId":   msg.ProcID,
+		"msgId":    msg.MsgID,
+	} {
+		if value != "" {
+			payload[key] = value
+		}
+	}
+	if len(msg.StructuredData) > 0 {
+		payload["structuredData"] = msg.StructuredData
+	}
+
+	jsonBytes, err := json.Marshal(payload)
+	if err == nil {
+		r.Payload = string(jsonBytes)
+	}
+
+	return r
+}
+
+// syslogLevel maps emergency, alert and critical to Fatal, notice to Infomation and the others to their counterparts
+func syslogLevel(severity int) logs.LogLevel {
+	switch {
+	case severity <= 2:
+		return logs.LogLevel_Fatal
+	case severity == 3:
+		return logs.LogLevel_Error
+	case severity == 4:
+		return logs.LogLevel_Warning
+	case severity <= 6:
+		return logs.LogLevel_Infomation
+	default:
+		return logs.LogLevel_Debug
+	}
+}
@@ -0,0 +1,218 @@
+// Package syslog parses RFC 5424 and RFC 3164 messages and reads them from UDP and TCP listeners.
+package syslog
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+)
+
+const (
+	_NIL_VALUE = "-"
+	_BOM       = "\xef\xbb\xbf"
+)
+
+var (
+	// Longest first, so that fractional seconds are not taken as the hostname
+	_rfc3164Layouts = []string{"Jan _2 15:04:05.000", time.Stamp}
+)
+
+// Message is a parsed syslog message, fields absent from the message are empty
+type Message struct {
+	Facility int
+	Severity int
+	// Zero when the message has no valid timestamp
+	Timestamp time.Time
+	Hostname  string
+	AppName   string
+	ProcID    string
+	MsgID     string
+	// SD-ID to parameters, RFC 5424 only
+	StructuredData map[string]map[string]string
+	Message        string
+}
+
+// Parse parses a message in RFC 5424 format, or in RFC 3164 format when there is no version after the priority
+func Parse(data []byte) (*Message, error) {
+	data = bytes.TrimRight(data, "\r\n\x00")
+	if len(data) < 3 || data[0] != '<' {
+		return nil, xerr.New("missing priority")
+	}
+
+	end := bytes.IndexByte(data[:min(len(data), 5)], '>')
+	if end < 2 {
+		return nil, xerr.New("invalid priority")
+	}
+	pri, err := strconv.Atoi(string(data[1:end]))
+	if err != nil || pri > 191 {
+		return nil, xerr.Errorf("invalid priority '%s'", data[1:end])
+	}
+
+	r := &Message{
+		Facility: pri / 8,
+		Severity: pri % 8,
+	}
+	rest := string(data[end+1:])
+	if strings.HasPrefix(rest, "1 ") {
+		err = r.parse5424(rest[2:])
+	} else {
+		r.parse3164(rest)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// parse5424 parses "TIMESTAMP HOSTNAME APP-NAME PROCID MSGID STRUCTURED-DATA [MSG]"
+func (o *Message) parse5424(s string) error {
+	var fields [5]string
+	for i := range fields {
+		var ok bool
+		fields[i], s, ok = strings.Cut(s, " ")
+		if !ok && i < len(fields)-1 {
+			return xerr.New("incomplete header")
+		}
+	}
+
+	if fields[0] != _NIL_VALUE {
+		t, err := time.Parse(time.RFC3339Nano, fields[0])
+		if err != nil {
+			return xerr.Errorf("invalid timestamp '%s'", fields[0])
+		}
+		o.Timestamp = t
+	}
+	o.Hostname = nilValue(fields[1])
+	o.AppName = nilValue(fields[2])
+	o.ProcID = nilValue(fields[3])
+	o.MsgID = nilValue(fields[4])
+
+	if s == "" {
+		return nil
+	}
+	if strings.HasPrefix(s, _NIL_VALUE) {
+		s = s[1:]
+	} else {
+		var err error
+		s, err = o.parseStructuredData(s)
+		if err != nil {
+			return err
+		}
+	}
+
+	o.Message = strings.TrimPrefix(strings.TrimPrefix(s, " "), _BOM)
+	return nil
+}
+
+// parseStructuredData parses elements like [id key="value"] and returns what follows them
+func (o *Message) parseStructuredData(s string) (string, error) {
+	o.StructuredData = make(map[string]map[string]string)
+	for strings.HasPrefix(s, "[") {
+		s = s[1:]
+		i := strings.IndexAny(s, " ]")
+		if i <= 0 {
+			return "", xerr.New("invalid structured data id")
+		}
+		params := make(map[string]string)
+		o.StructuredData[s[:i]] = params
+		s = s[i:]
+
+		for {
+			s = strings.TrimLeft(s, " ")
+			if s == "" {
+				return "", xerr.New("unterminated structured data")
+			}
+			if s[0] == ']' {
+				s = s[1:]
+				break
+			}
+
+			name, value, ok := strings.Cut(s, "=\"")
+			if !ok || name == "" || strings.ContainsAny(name, " ]") {
+				return "", xerr.New("invalid structured data parameter")
+			}
+			s = value
+
+			// The value ends at the first unescaped quote, \" \\ and \] are escapes
+			var sb strings.Builder
+			i := 0
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte(`"\]`, s[i+1]) >= 0 {
+					i++
+				}
+				sb.WriteByte(s[i])
+			}
+			if i == len(s) {
+				return "", xerr.New("unterminated structured data value")
+			}
+			params[name] = sb.String()
+			s = s[i+1:]
+		}
+	}
+
+	return s, nil
+}
+
+// parse3164 parses "TIMESTAMP HOSTNAME TAG[PID]: MSG", it never fails since RFC 3164 only describes common practice,
+// what cannot be recognized is kept in Message
+func (o *Message) parse3164(s string) {
+	if len(s) >= len(time.Stamp) {
+		for _, layout := range _rfc3164Layouts {
+			if len(s) < len(layout) || len(s) > len(layout) && s[len(layout)] != ' ' {
+				continue
+			}
+			t, err := time.ParseInLocation(layout, s[:len(layout)], time.Local)
+			if err != nil {
+				continue
+			}
+			o.Timestamp = withYear(t, time.Now())
+			s = strings.TrimLeft(s[len(layout):], " ")
+
+			// The hostname is only present after a timestamp
+			if host, rest, ok := strings.Cut(s, " "); ok && !strings.HasSuffix(host, ":") && !strings.Contains(host, "[") {
+				o.Hostname = host
+				s = rest
+			}
+			break
+		}
+	}
+
+	// The tag is alphanumeric, at most 32 characters, ended by '[' or ':'
+	i := strings.IndexAny(s, "[: ")
+	if i > 0 && i <= 32 && s[i] != ' ' {
+		tag := s[:i]
+		rest := s[i:]
+		if rest[0] == '[' {
+			if pid, after, ok := strings.Cut(rest[1:], "]"); ok {
+				o.ProcID = pid
+				rest = after
+			}
+		}
+		if strings.HasPrefix(rest, ":") {
+			o.AppName = tag
+			s = strings.TrimPrefix(rest[1:], " ")
+		}
+	}
+
+	o.Message = s
+}
+
+// withYear sets the year missing from RFC 3164 timestamps, a time more than a day ahead of now is of last year
+func withYear(t, now time.Time) time.Time {
+	r := t.AddDate(now.Year()-t.Year(), 0, 0)
+	if r.Sub(now) > time.Hour*24 {
+		r = r.AddDate(-1, 0, 0)
+	}
+	return r
+}
+
+func nilValue(s string) string {
+	if s == _NIL_VALUE {
+		return ""
+	}
+	return s
+}
@@ -0,0 +1,174 @@
+package syslog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParse5424(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+		want Message
+	}{
+		{
+			"Full",
+			`<165>1 2003-10-11T22:14:15.003Z mymachine.example.com evntslog 12 ID47 [exampleSDID@32473 iut="3" eventSource="Application"] An application event`,
+			Message{Facility: 20, Severity: 5, Timestamp: time.Date(2003, 10, 11, 22, 14, 15, 3000000, time.UTC),
+				Hostname: "mymachine.example.com", AppName: "evntslog", ProcID: "12", MsgID: "ID47",
+				StructuredData: map[string]map[string]string{"exampleSDID@32473": {"iut": "3", "eventSource": "Application"}},
+				Message:        "An application event"},
+		},
+		{
+			"NilValues",
+			"<34>1 - - - - - -",
+			Message{Facility: 4, Severity: 2},
+		},
+		{
+			"NilStructuredDataWithMessage",
+			"<34>1 2003-10-11T22:14:15+02:00 host app - - - \xef\xbb\xbf'su root' failed",
+			Message{Facility: 4, Severity: 2, Timestamp: time.Date(2003, 10, 11, 22, 14, 15, 0, time.FixedZone("", 2*3600)),
+				Hostname: "host", AppName: "app", Message: "'su root' failed"},
+		},
+		{
+			"Escapes",
+			`<13>1 - h a - - [a x="q\"b\\c\]d" y=""][b@1] msg`,
+			Message{Facility: 1, Severity: 5, Hostname: "h", AppName: "a",
+				StructuredData: map[string]map[string]string{"a": {"x": `q"b\c]d`, "y": ""}, "b@1": {}},
+				Message:        "msg"},
+		},
+		{
+			"KeptBackslash",
+			`<13>1 - - - - - [a x="c:\dir"]`,
+			Message{Facility: 1, Severity: 5, StructuredData: map[string]map[string]string{"a": {"x": `c:\dir`}}},
+		},
+		{
+			"TrailingNewline",
+			"<13>1 - - - - - - hello\r\n",
+			Message{Facility: 1, Severity: 5, Message: "hello"},
+		},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			got, err := Parse([]byte(x.data))
+			if err != nil {
+				t.Fatalf("Parse = %v", err)
+			}
+			if !got.Timestamp.Equal(x.want.Timestamp) {
+				t.Errorf("Timestamp = %v, want %v", got.Timestamp, x.want.Timestamp)
+			}
+			got.Timestamp, x.want.Timestamp = time.Time{}, time.Time{}
+			if !reflect.DeepEqual(*got, x.want) {
+				t.Errorf("Parse = %+v, want %+v", *got, x.want)
+			}
+		})
+	}
+}
+
+func TestParse3164(t *testing.T) {
+	cases := []struct {
+		name      string
+		data      string
+		timestamp string
+		want      Message
+	}{
+		{
+			"Full",
+			"<34>Oct 11 22:14:15 mymachine su[230]: 'su root' failed",
+			"Oct 11 22:14:15.000",
+			Message{Facility: 4, Severity: 2, Hostname: "mymachine", AppName: "su", ProcID: "230", Message: "'su root' failed"},
+		},
+		{
+			"PaddedDay",
+			"<13>Feb  5 17:32:18 host app: hello",
+			"Feb  5 17:32:18.000",
+			Message{Facility: 1, Severity: 5, Hostname: "host", AppName: "app", Message: "hello"},
+		},
+		{
+			"Milliseconds",
+			"<13>Feb  5 17:32:18.123 host app: hello",
+			"Feb  5 17:32:18.123",
+			Message{Facility: 1, Severity: 5, Hostname: "host", AppName: "app", Message: "hello"},
+		},
+		{
+			"NoHostname",
+			"<13>Feb  5 17:32:18 app[7]: hello",
+			"Feb  5 17:32:18.000",
+			Message{Facility: 1, Severity: 5, AppName: "app", ProcID: "7", Message: "hello"},
+		},
+		{
+			"NoTimestamp",
+			"<13>app: hello world",
+			"",
+			Message{Facility: 1, Severity: 5, AppName: "app", Message: "hello world"},
+		},
+		{
+			"Unrecognized",
+			"<13>just some text",
+			"",
+			Message{Facility: 1, Severity: 5, Message: "just some text"},
+		},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			got, err := Parse([]byte(x.data))
+			if err != nil {
+				t.Fatalf("Parse = %v", err)
+			}
+			timestamp := ""
+			if !got.Timestamp.IsZero() {
+				timestamp = got.Timestamp.Format("Jan _2 15:04:05.000")
+			}
+			if timestamp != x.timestamp {
+				t.Errorf("Timestamp = %q, want %q", timestamp, x.timestamp)
+			}
+			got.Timestamp = time.Time{}
+			if !reflect.DeepEqual(*got, x.want) {
+				t.Errorf("Parse = %+v, want %+v", *got, x.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	cases := []struct {
+		name string
+		data string
+	}{
+		{"Empty", ""},
+		{"NoPriority", "hello"},
+		{"UnterminatedPriority", "<1345 hello"},
+		{"PriorityTooLarge", "<192>1 - - - - - -"},
+		{"IncompleteHeader", "<13>1 - host app"},
+		{"InvalidTimestamp", "<13>1 yesterday - - - - -"},
+		{"UnterminatedStructuredData", `<13>1 - - - - - [a x="1"`},
+		{"UnterminatedValue", `<13>1 - - - - - [a x="1]`},
+		{"InvalidParameter", `<13>1 - - - - - [a x]`},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			if got, err := Parse([]byte(x.data)); err == nil {
+				t.Errorf("Parse(%q) = %+v, want an error", x.data, got)
+			}
+		})
+	}
+}
+
+func TestWithYear(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 30, 0, 0, time.UTC)
+	cases := []struct {
+		t    time.Time
+		want time.Time
+	}{
+		{time.Date(0, 1, 1, 0, 10, 0, 0, time.UTC), time.Date(2024, 1, 1, 0, 10, 0, 0, time.UTC)},
+		{time.Date(0, 1, 2, 0, 10, 0, 0, time.UTC), time.Date(2024, 1, 2, 0, 10, 0, 0, time.UTC)},
+		// Sent on the last day of the year before
+		{time.Date(0, 12, 31, 23, 50, 0, 0, time.UTC), time.Date(2023, 12, 31, 23, 50, 0, 0, time.UTC)},
+	}
+	for _, x := range cases {
+		if got := withYear(x.t, now); !got.Equal(x.want) {
+			t.Errorf("withYear(%v) = %v, want %v", x.t, got, x.want)
+		}
+	}
+}
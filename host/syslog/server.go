@@ -0,0 +1,129 @@
+package syslog
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/DreamvatLab/go/xerr"
+	"github.com/DreamvatLab/go/xlog"
+)
+
+const (
+	_TCP_IDLE_TIMEOUT = time.Minute * 5
+)
+
+// Handler receives the parsed messages, it's called from the goroutine of the listener or connection
+type Handler func(msg *Message, remote net.Addr)
+
+// ServeUDP reads one message per datagram until the connection is closed
+func ServeUDP(conn net.PacketConn, maxSize int, handler Handler) error {
+	buffer := make([]byte, maxSize)
+	for {
+		n, remote, err := conn.ReadFrom(buffer)
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return xerr.WithStack(err)
+		}
+		handle(buffer[:n], remote, handler)
+	}
+}
+
+// ServeTCP accepts connections until the listener is closed, see readFrames for the framing
+func ServeTCP(lis net.Listener, maxSize int, handler Handler) error {
+	for {
+		conn, err := lis.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return xerr.WithStack(err)
+		}
+
+		go func() {
+			defer conn.Close()
+			err := readFrames(&idleConn{Conn: conn}, maxSize, func(frame []byte) {
+				handle(frame, conn.RemoteAddr(), handler)
+			})
+			if err != nil {
+				xlog.Warnf("Syslog connection from %s closed: %v", conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+// readFrames splits a stream into messages by octet counting (RFC 6587 "LEN SP MSG") when a frame
+// starts with a digit, otherwise by newlines
+func readFrames(r io.Reader, maxSize int, handle func([]byte)) error {
+	reader := bufio.NewReaderSize(r, min(maxSize, 64*1024))
+	for {
+		first, err := reader.Peek(1)
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return xerr.WithStack(err)
+		}
+
+		if first[0] >= '0' && first[0] <= '9' {
+			lengthStr, err := reader.ReadString(' ')
+			if err != nil {
+				return xerr.WithStack(err)
+			}
+			length, err := strconv.Atoi(lengthStr[:len(lengthStr)-1])
+			if err != nil || length <= 0 || length > maxSize {
+				return xerr.Errorf("invalid frame length '%s'", lengthStr[:len(lengthStr)-1])
+			}
+			frame := make([]byte, length)
+			_, err = io.ReadFull(reader, frame)
+			if err != nil {
+				return xerr.WithStack(err)
+			}
+			handle(frame)
+			continue
+		}
+
+		var frame []byte
+		for {
+			line, err := reader.ReadSlice('\n')
+			frame = append(frame, line...)
+			if len(frame) > maxSize {
+				return xerr.Errorf("message exceeds %d bytes", maxSize)
+			}
+			if err == bufio.ErrBufferFull {
+				continue
+			}
+			if err != nil && err != io.EOF {
+				return xerr.WithStack(err)
+			}
+			break
+		}
+		if len(bytes.TrimSpace(frame)) > 0 {
+			handle(frame)
+		}
+	}
+}
+
+func handle(data []byte, remote net.Addr, handler Handler) {
+	msg, err := Parse(data)
+	if err != nil {
+		xlog.Debugf("Invalid syslog message from %s: %v", remote, err)
+		return
+	}
+	handler(msg, remote)
+}
+
+// idleConn closes connections idle for longer than _TCP_IDLE_TIMEOUT
+type idleConn struct {
+	net.Conn
+}
+
+func (o *idleConn) Read(b []byte) (int, error) {
+	o.Conn.SetReadDeadline(time.Now().Add(_TCP_IDLE_TIMEOUT))
+	return o.Conn.Read(b)
+}
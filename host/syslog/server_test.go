@@ -0,0 +1,47 @@
+package syslog
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestReadFrames(t *testing.T) {
+	long := strings.Repeat("x", 70000)
+	cases := []struct {
+		name    string
+		data    string
+		maxSize int
+		want    []string
+		wantErr bool
+	}{
+		{"OctetCounting", "5 <13>a11 <13>1 - - -", 100, []string{"<13>a", "<13>1 - - -"}, false},
+		{"OctetCountingKeepsNewlines", "8 <13>a\nb\n", 100, []string{"<13>a\nb\n"}, false},
+		{"Newlines", "<13>a\n<13>b\r\n<13>c", 100, []string{"<13>a\n", "<13>b\r\n", "<13>c"}, false},
+		{"BlankLines", "\n<13>a\n \n\n", 100, []string{"<13>a\n"}, false},
+		{"Mixed", "<13>a\n5 <13>b<13>c\n", 100, []string{"<13>a\n", "<13>b", "<13>c\n"}, false},
+		{"LongerThanBuffer", long + "\n", 100000, []string{long + "\n"}, false},
+		{"Empty", "", 100, nil, false},
+		{"InvalidLength", "5x <13>a", 100, nil, true},
+		{"ZeroLength", "0 <13>a", 100, nil, true},
+		{"LengthTooLarge", "101 <13>a", 100, nil, true},
+		{"Truncated", "6 <13>a", 100, nil, true},
+		{"LineTooLarge", "<13>" + strings.Repeat("x", 100) + "\n", 100, nil, true},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			var got []string
+			// One byte at a time, so that frames never arrive in a single read
+			err := readFrames(iotest.OneByteReader(strings.NewReader(x.data)), x.maxSize, func(frame []byte) {
+				got = append(got, string(frame))
+			})
+			if (err != nil) != x.wantErr {
+				t.Fatalf("readFrames = %v, want error %v", err, x.wantErr)
+			}
+			if !x.wantErr && !reflect.DeepEqual(got, x.want) {
+				t.Errorf("frames = %q, want %q", got, x.want)
+			}
+		})
+	}
+}
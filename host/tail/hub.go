@@ -0,0 +1,114 @@
+// Package tail fans out newly written log entries to live viewers.
+package tail
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/DreamvatLab/logs"
+)
+
+// Subscriber receives entries matching its query from C.
+// C is closed when the subscriber is unsubscribed or dropped for being too slow.
+type Subscriber struct {
+	C       <-chan *logs.LogEntry
+	c       chan *logs.LogEntry
+	query   *logs.TailLogEntriesQuery
+	dropped bool
+}
+
+// Dropped reports whether the subscriber was removed because its queue was full, only valid after C is closed
+func (o *Subscriber) Dropped() bool {
+	return o.dropped
+}
+
+type Hub struct {
+	locker      sync.RWMutex
+	subscribers map[*Subscriber]struct{}
+	queueSize   int
+}
+
+func NewHub(queueSize int) *Hub {
+	return &Hub{
+		subscribers: make(map[*Subscriber]struct{}),
+		queueSize:   queueSize,
+	}
+}
+
+func (o *Hub) Subscribe(query *logs.TailLogEntriesQuery) *Subscriber {
+	c := make(chan *logs.LogEntry, o.queueSize)
+	r := &Subscriber{
+		C:     c,
+		c:     c,
+		query: query,
+	}
+
+	o.locker.Lock()
+	o.subscribers[r] = struct{}{}
+	o.locker.Unlock()
+
+	return r
+}
+
+func (o *Hub) Unsubscribe(subscriber *Subscriber) {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	o.remove(subscriber, false)
+}
+
+func (o *Hub) remove(subscriber *Subscriber, dropped bool) {
+	if _, ok := o.subscribers[subscriber]; ok {
+		delete(o.subscribers, subscriber)
+		subscriber.dropped = dropped
+		close(subscriber.c)
+	}
+}
+
+// Publish never blocks, subscribers whose queue is full are dropped
+func (o *Hub) Publish(clientID string, entries []*logs.LogEntry) {
+	var slow []*Subscriber
+
+	o.locker.RLock()
+	if len(o.subscribers) == 0 {
+		o.locker.RUnlock()
+		return
+	}
+	for subscriber := range o.subscribers {
+		if !send(subscriber, clientID, entries) {
+			slow = append(slow, subscriber)
+		}
+	}
+	o.locker.RUnlock()
+
+	if len(slow) > 0 {
+		o.locker.Lock()
+		for _, x := range slow {
+			o.remove(x, true)
+		}
+		o.locker.Unlock()
+	}
+}
+
+func send(subscriber *Subscriber, clientID string, entries []*logs.LogEntry) bool {
+	for _, x := range entries {
+		if !Match(subscriber.query, clientID, x) {
+			continue
+		}
+		select {
+		case subscriber.c <- x:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Match reports whether an entry of the client matches the query.
+// Level is the minimum level, User and TraceNo match by prefix, Message matches by substring.
+func Match(query *logs.TailLogEntriesQuery, clientID string, entry *logs.LogEntry) bool {
+	return query.ClientID == clientID &&
+		entry.Level >= query.Level &&
+		strings.HasPrefix(entry.User, query.User) &&
+		strings.HasPrefix(entry.TraceNo, query.TraceNo) &&
+		strings.Contains(entry.Message, query.Message)
+}
@@ -2,7 +2,7 @@
 
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.36.9
+// 	protoc-gen-go v1.36.11
 // 	protoc        v4.22.3
 // source: logs.proto
 
@@ -105,8 +105,10 @@ type LogEntry struct {
 	CreatedOnUtc int64 `protobuf:"varint,10,opt,name=CreatedOnUtc,proto3" json:"CreatedOnUtc,omitempty" db:"CreatedOnUtc"`
 	// @gotags: db:"CreatedOnUtcISO"
 	CreatedOnUtcISO string `protobuf:"bytes,11,opt,name=CreatedOnUtcISO,proto3" json:"CreatedOnUtcISO,omitempty" db:"CreatedOnUtcISO"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	// @gotags: db:"Labels"
+	Labels        map[string]string `protobuf:"bytes,12,rep,name=Labels,proto3" json:"Labels,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value" db:"Labels"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LogEntry) Reset() {
@@ -216,10 +218,19 @@ func (x *LogEntry) GetCreatedOnUtcISO() string {
 	return ""
 }
 
+func (x *LogEntry) GetLabels() map[string]string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
 type WriteLogCommand struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	ClientID      string                 `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
-	LogEntry      *LogEntry              `protobuf:"bytes,2,opt,name=LogEntry,proto3" json:"LogEntry,omitempty"`
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ClientID string                 `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	LogEntry *LogEntry              `protobuf:"bytes,2,opt,name=LogEntry,proto3" json:"LogEntry,omitempty"`
+	// Increasing number assigned by the producer, only used by StreamLogEntries
+	Sequence      int64 `protobuf:"varint,3,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -268,6 +279,305 @@ func (x *WriteLogCommand) GetLogEntry() *LogEntry {
 	return nil
 }
 
+func (x *WriteLogCommand) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+type WriteLogEntriesCommand struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ClientID      string                 `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	LogEntries    []*LogEntry            `protobuf:"bytes,2,rep,name=LogEntries,proto3" json:"LogEntries,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteLogEntriesCommand) Reset() {
+	*x = WriteLogEntriesCommand{}
+	mi := &file_logs_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteLogEntriesCommand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteLogEntriesCommand) ProtoMessage() {}
+
+func (x *WriteLogEntriesCommand) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteLogEntriesCommand.ProtoReflect.Descriptor instead.
+func (*WriteLogEntriesCommand) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *WriteLogEntriesCommand) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *WriteLogEntriesCommand) GetLogEntries() []*LogEntry {
+	if x != nil {
+		return x.LogEntries
+	}
+	return nil
+}
+
+type WriteLogEntryResult struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Empty if the entry is not written
+	ID            string `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	Message       string `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteLogEntryResult) Reset() {
+	*x = WriteLogEntryResult{}
+	mi := &file_logs_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteLogEntryResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteLogEntryResult) ProtoMessage() {}
+
+func (x *WriteLogEntryResult) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteLogEntryResult.ProtoReflect.Descriptor instead.
+func (*WriteLogEntryResult) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *WriteLogEntryResult) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *WriteLogEntryResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type WriteLogEntriesResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	// In the same order as the entries in the command
+	Results       []*WriteLogEntryResult `protobuf:"bytes,2,rep,name=Results,proto3" json:"Results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WriteLogEntriesResult) Reset() {
+	*x = WriteLogEntriesResult{}
+	mi := &file_logs_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WriteLogEntriesResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WriteLogEntriesResult) ProtoMessage() {}
+
+func (x *WriteLogEntriesResult) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WriteLogEntriesResult.ProtoReflect.Descriptor instead.
+func (*WriteLogEntriesResult) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *WriteLogEntriesResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *WriteLogEntriesResult) GetResults() []*WriteLogEntryResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type StreamLogEntriesAck struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// All entries with a sequence number up to this one are persisted
+	Sequence int64 `protobuf:"varint,1,opt,name=Sequence,proto3" json:"Sequence,omitempty"`
+	// Set when writing fails, the stream is closed after this ack
+	Message       string `protobuf:"bytes,2,opt,name=Message,proto3" json:"Message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamLogEntriesAck) Reset() {
+	*x = StreamLogEntriesAck{}
+	mi := &file_logs_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamLogEntriesAck) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamLogEntriesAck) ProtoMessage() {}
+
+func (x *StreamLogEntriesAck) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamLogEntriesAck.ProtoReflect.Descriptor instead.
+func (*StreamLogEntriesAck) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *StreamLogEntriesAck) GetSequence() int64 {
+	if x != nil {
+		return x.Sequence
+	}
+	return 0
+}
+
+func (x *StreamLogEntriesAck) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+type TailLogEntriesQuery struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	ClientID string                 `protobuf:"bytes,1,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	// Minimum level
+	Level LogLevel `protobuf:"varint,2,opt,name=Level,proto3,enum=logs.LogLevel" json:"Level,omitempty"`
+	// Prefix match
+	User string `protobuf:"bytes,3,opt,name=User,proto3" json:"User,omitempty"`
+	// Prefix match
+	TraceNo string `protobuf:"bytes,4,opt,name=TraceNo,proto3" json:"TraceNo,omitempty"`
+	// Substring match
+	Message       string `protobuf:"bytes,5,opt,name=Message,proto3" json:"Message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TailLogEntriesQuery) Reset() {
+	*x = TailLogEntriesQuery{}
+	mi := &file_logs_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TailLogEntriesQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TailLogEntriesQuery) ProtoMessage() {}
+
+func (x *TailLogEntriesQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TailLogEntriesQuery.ProtoReflect.Descriptor instead.
+func (*TailLogEntriesQuery) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *TailLogEntriesQuery) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *TailLogEntriesQuery) GetLevel() LogLevel {
+	if x != nil {
+		return x.Level
+	}
+	return LogLevel_Verbose
+}
+
+func (x *TailLogEntriesQuery) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *TailLogEntriesQuery) GetTraceNo() string {
+	if x != nil {
+		return x.TraceNo
+	}
+	return ""
+}
+
+func (x *TailLogEntriesQuery) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
 type LogEntryQuery struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	DBName        string                 `protobuf:"bytes,1,opt,name=DBName,proto3" json:"DBName,omitempty"`
@@ -279,7 +589,7 @@ type LogEntryQuery struct {
 
 func (x *LogEntryQuery) Reset() {
 	*x = LogEntryQuery{}
-	mi := &file_logs_proto_msgTypes[2]
+	mi := &file_logs_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -291,7 +601,7 @@ func (x *LogEntryQuery) String() string {
 func (*LogEntryQuery) ProtoMessage() {}
 
 func (x *LogEntryQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_logs_proto_msgTypes[2]
+	mi := &file_logs_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -304,7 +614,7 @@ func (x *LogEntryQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogEntryQuery.ProtoReflect.Descriptor instead.
 func (*LogEntryQuery) Descriptor() ([]byte, []int) {
-	return file_logs_proto_rawDescGZIP(), []int{2}
+	return file_logs_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *LogEntryQuery) GetDBName() string {
@@ -338,7 +648,7 @@ type LogEntryResult struct {
 
 func (x *LogEntryResult) Reset() {
 	*x = LogEntryResult{}
-	mi := &file_logs_proto_msgTypes[3]
+	mi := &file_logs_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -350,7 +660,7 @@ func (x *LogEntryResult) String() string {
 func (*LogEntryResult) ProtoMessage() {}
 
 func (x *LogEntryResult) ProtoReflect() protoreflect.Message {
-	mi := &file_logs_proto_msgTypes[3]
+	mi := &file_logs_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -363,7 +673,7 @@ func (x *LogEntryResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogEntryResult.ProtoReflect.Descriptor instead.
 func (*LogEntryResult) Descriptor() ([]byte, []int) {
-	return file_logs_proto_rawDescGZIP(), []int{3}
+	return file_logs_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *LogEntryResult) GetMessage() string {
@@ -380,30 +690,99 @@ func (x *LogEntryResult) GetLogEntry() *LogEntry {
 	return nil
 }
 
+type LabelFilter struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Key   string                 `protobuf:"bytes,1,opt,name=Key,proto3" json:"Key,omitempty"`
+	Value string                 `protobuf:"bytes,2,opt,name=Value,proto3" json:"Value,omitempty"`
+	// Match Value as a prefix instead of the whole value
+	Prefix        bool `protobuf:"varint,3,opt,name=Prefix,proto3" json:"Prefix,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LabelFilter) Reset() {
+	*x = LabelFilter{}
+	mi := &file_logs_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LabelFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LabelFilter) ProtoMessage() {}
+
+func (x *LabelFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LabelFilter.ProtoReflect.Descriptor instead.
+func (*LabelFilter) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *LabelFilter) GetKey() string {
+	if x != nil {
+		return x.Key
+	}
+	return ""
+}
+
+func (x *LabelFilter) GetValue() string {
+	if x != nil {
+		return x.Value
+	}
+	return ""
+}
+
+func (x *LabelFilter) GetPrefix() bool {
+	if x != nil {
+		return x.Prefix
+	}
+	return false
+}
+
 type LogEntriesQuery struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	DBName        string                 `protobuf:"bytes,1,opt,name=DBName,proto3" json:"DBName,omitempty"`
-	TableName     string                 `protobuf:"bytes,2,opt,name=TableName,proto3" json:"TableName,omitempty"`
-	TraceNo       string                 `protobuf:"bytes,3,opt,name=TraceNo,proto3" json:"TraceNo,omitempty"`
-	User          string                 `protobuf:"bytes,4,opt,name=User,proto3" json:"User,omitempty"`
-	Message       string                 `protobuf:"bytes,5,opt,name=Message,proto3" json:"Message,omitempty"`
-	Error         string                 `protobuf:"bytes,6,opt,name=Error,proto3" json:"Error,omitempty"`
-	StackTrace    string                 `protobuf:"bytes,7,opt,name=StackTrace,proto3" json:"StackTrace,omitempty"`
-	OrderDir      string                 `protobuf:"bytes,8,opt,name=OrderDir,proto3" json:"OrderDir,omitempty"`
-	StartTime     string                 `protobuf:"bytes,9,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
-	EndTime       string                 `protobuf:"bytes,10,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
-	Level         LogLevel               `protobuf:"varint,11,opt,name=Level,proto3,enum=logs.LogLevel" json:"Level,omitempty"`
-	PageSize      int32                  `protobuf:"varint,12,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
-	PageIndex     int32                  `protobuf:"varint,13,opt,name=PageIndex,proto3" json:"PageIndex,omitempty"`
-	OrderBy       int32                  `protobuf:"varint,14,opt,name=OrderBy,proto3" json:"OrderBy,omitempty"`
-	Flags         int64                  `protobuf:"varint,15,opt,name=Flags,proto3" json:"Flags,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DBName     string                 `protobuf:"bytes,1,opt,name=DBName,proto3" json:"DBName,omitempty"`
+	TableName  string                 `protobuf:"bytes,2,opt,name=TableName,proto3" json:"TableName,omitempty"`
+	TraceNo    string                 `protobuf:"bytes,3,opt,name=TraceNo,proto3" json:"TraceNo,omitempty"`
+	User       string                 `protobuf:"bytes,4,opt,name=User,proto3" json:"User,omitempty"`
+	Message    string                 `protobuf:"bytes,5,opt,name=Message,proto3" json:"Message,omitempty"`
+	Error      string                 `protobuf:"bytes,6,opt,name=Error,proto3" json:"Error,omitempty"`
+	StackTrace string                 `protobuf:"bytes,7,opt,name=StackTrace,proto3" json:"StackTrace,omitempty"`
+	OrderDir   string                 `protobuf:"bytes,8,opt,name=OrderDir,proto3" json:"OrderDir,omitempty"`
+	StartTime  string                 `protobuf:"bytes,9,opt,name=StartTime,proto3" json:"StartTime,omitempty"`
+	EndTime    string                 `protobuf:"bytes,10,opt,name=EndTime,proto3" json:"EndTime,omitempty"`
+	Level      LogLevel               `protobuf:"varint,11,opt,name=Level,proto3,enum=logs.LogLevel" json:"Level,omitempty"`
+	PageSize   int32                  `protobuf:"varint,12,opt,name=PageSize,proto3" json:"PageSize,omitempty"`
+	PageIndex  int32                  `protobuf:"varint,13,opt,name=PageIndex,proto3" json:"PageIndex,omitempty"`
+	OrderBy    int32                  `protobuf:"varint,14,opt,name=OrderBy,proto3" json:"OrderBy,omitempty"`
+	Flags      int64                  `protobuf:"varint,15,opt,name=Flags,proto3" json:"Flags,omitempty"`
+	// All filters must match
+	Labels []*LabelFilter `protobuf:"bytes,16,rep,name=Labels,proto3" json:"Labels,omitempty"`
+	// NextCursor or PrevCursor of a previous result, PageIndex is ignored when set
+	Cursor string `protobuf:"bytes,17,opt,name=Cursor,proto3" json:"Cursor,omitempty"`
+	// Set with StartTime and EndTime instead of DBName and TableName to query all partitions of the client in the time range
+	ClientID string `protobuf:"bytes,18,opt,name=ClientID,proto3" json:"ClientID,omitempty"`
+	// Search expression ANDed with the other filters, e.g. level>=warning user:alice* "connection reset"
+	Query         string `protobuf:"bytes,19,opt,name=Query,proto3" json:"Query,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LogEntriesQuery) Reset() {
 	*x = LogEntriesQuery{}
-	mi := &file_logs_proto_msgTypes[4]
+	mi := &file_logs_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -415,7 +794,7 @@ func (x *LogEntriesQuery) String() string {
 func (*LogEntriesQuery) ProtoMessage() {}
 
 func (x *LogEntriesQuery) ProtoReflect() protoreflect.Message {
-	mi := &file_logs_proto_msgTypes[4]
+	mi := &file_logs_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -428,7 +807,7 @@ func (x *LogEntriesQuery) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogEntriesQuery.ProtoReflect.Descriptor instead.
 func (*LogEntriesQuery) Descriptor() ([]byte, []int) {
-	return file_logs_proto_rawDescGZIP(), []int{4}
+	return file_logs_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *LogEntriesQuery) GetDBName() string {
@@ -536,18 +915,221 @@ func (x *LogEntriesQuery) GetFlags() int64 {
 	return 0
 }
 
+func (x *LogEntriesQuery) GetLabels() []*LabelFilter {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *LogEntriesQuery) GetCursor() string {
+	if x != nil {
+		return x.Cursor
+	}
+	return ""
+}
+
+func (x *LogEntriesQuery) GetClientID() string {
+	if x != nil {
+		return x.ClientID
+	}
+	return ""
+}
+
+func (x *LogEntriesQuery) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+type LogHistogramQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters of the entries, paging and ordering fields are ignored
+	Query *LogEntriesQuery `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	// Bucket width in seconds
+	Interval      int64 `protobuf:"varint,2,opt,name=Interval,proto3" json:"Interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogHistogramQuery) Reset() {
+	*x = LogHistogramQuery{}
+	mi := &file_logs_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogHistogramQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogHistogramQuery) ProtoMessage() {}
+
+func (x *LogHistogramQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogHistogramQuery.ProtoReflect.Descriptor instead.
+func (*LogHistogramQuery) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *LogHistogramQuery) GetQuery() *LogEntriesQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *LogHistogramQuery) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+type LogHistogramBucket struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Start of the bucket in unix milliseconds
+	// @gotags: db:"StartTime"
+	StartTime int64 `protobuf:"varint,1,opt,name=StartTime,proto3" json:"StartTime,omitempty" db:"StartTime"`
+	// @gotags: db:"Level"
+	Level LogLevel `protobuf:"varint,2,opt,name=Level,proto3,enum=logs.LogLevel" json:"Level,omitempty" db:"Level"`
+	// @gotags: db:"Count"
+	Count         int64 `protobuf:"varint,3,opt,name=Count,proto3" json:"Count,omitempty" db:"Count"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogHistogramBucket) Reset() {
+	*x = LogHistogramBucket{}
+	mi := &file_logs_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogHistogramBucket) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogHistogramBucket) ProtoMessage() {}
+
+func (x *LogHistogramBucket) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogHistogramBucket.ProtoReflect.Descriptor instead.
+func (*LogHistogramBucket) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *LogHistogramBucket) GetStartTime() int64 {
+	if x != nil {
+		return x.StartTime
+	}
+	return 0
+}
+
+func (x *LogHistogramBucket) GetLevel() LogLevel {
+	if x != nil {
+		return x.Level
+	}
+	return LogLevel_Verbose
+}
+
+func (x *LogHistogramBucket) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+type LogHistogramResult struct {
+	state   protoimpl.MessageState `protogen:"open.v1"`
+	Message string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	// Ordered by StartTime and Level, empty buckets are omitted
+	Buckets       []*LogHistogramBucket `protobuf:"bytes,2,rep,name=Buckets,proto3" json:"Buckets,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *LogHistogramResult) Reset() {
+	*x = LogHistogramResult{}
+	mi := &file_logs_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *LogHistogramResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*LogHistogramResult) ProtoMessage() {}
+
+func (x *LogHistogramResult) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use LogHistogramResult.ProtoReflect.Descriptor instead.
+func (*LogHistogramResult) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *LogHistogramResult) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *LogHistogramResult) GetBuckets() []*LogHistogramBucket {
+	if x != nil {
+		return x.Buckets
+	}
+	return nil
+}
+
 type LogEntriesResult struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Message       string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
-	TotalCount    int64                  `protobuf:"varint,2,opt,name=TotalCount,proto3" json:"TotalCount,omitempty"`
-	LogEntries    []*LogEntry            `protobuf:"bytes,3,rep,name=LogEntries,proto3" json:"LogEntries,omitempty"`
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Message    string                 `protobuf:"bytes,1,opt,name=Message,proto3" json:"Message,omitempty"`
+	TotalCount int64                  `protobuf:"varint,2,opt,name=TotalCount,proto3" json:"TotalCount,omitempty"`
+	LogEntries []*LogEntry            `protobuf:"bytes,3,rep,name=LogEntries,proto3" json:"LogEntries,omitempty"`
+	// Empty if there are no older entries
+	NextCursor string `protobuf:"bytes,4,opt,name=NextCursor,proto3" json:"NextCursor,omitempty"`
+	// Empty if there are no newer entries
+	PrevCursor    string `protobuf:"bytes,5,opt,name=PrevCursor,proto3" json:"PrevCursor,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *LogEntriesResult) Reset() {
 	*x = LogEntriesResult{}
-	mi := &file_logs_proto_msgTypes[5]
+	mi := &file_logs_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -559,7 +1141,7 @@ func (x *LogEntriesResult) String() string {
 func (*LogEntriesResult) ProtoMessage() {}
 
 func (x *LogEntriesResult) ProtoReflect() protoreflect.Message {
-	mi := &file_logs_proto_msgTypes[5]
+	mi := &file_logs_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -572,7 +1154,7 @@ func (x *LogEntriesResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LogEntriesResult.ProtoReflect.Descriptor instead.
 func (*LogEntriesResult) Descriptor() ([]byte, []int) {
-	return file_logs_proto_rawDescGZIP(), []int{5}
+	return file_logs_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *LogEntriesResult) GetMessage() string {
@@ -596,12 +1178,143 @@ func (x *LogEntriesResult) GetLogEntries() []*LogEntry {
 	return nil
 }
 
+func (x *LogEntriesResult) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+func (x *LogEntriesResult) GetPrevCursor() string {
+	if x != nil {
+		return x.PrevCursor
+	}
+	return ""
+}
+
+type ExportLogEntriesQuery struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Filters of the entries, paging fields are ignored
+	Query *LogEntriesQuery `protobuf:"bytes,1,opt,name=Query,proto3" json:"Query,omitempty"`
+	// ndjson (default), csv or parquet
+	Format string `protobuf:"bytes,2,opt,name=Format,proto3" json:"Format,omitempty"`
+	// Empty, gzip or zstd
+	Compression string `protobuf:"bytes,3,opt,name=Compression,proto3" json:"Compression,omitempty"`
+	// Stops after this many entries, capped by Export.MaxRows of the host
+	MaxRows       int64 `protobuf:"varint,4,opt,name=MaxRows,proto3" json:"MaxRows,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportLogEntriesQuery) Reset() {
+	*x = ExportLogEntriesQuery{}
+	mi := &file_logs_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportLogEntriesQuery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportLogEntriesQuery) ProtoMessage() {}
+
+func (x *ExportLogEntriesQuery) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportLogEntriesQuery.ProtoReflect.Descriptor instead.
+func (*ExportLogEntriesQuery) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ExportLogEntriesQuery) GetQuery() *LogEntriesQuery {
+	if x != nil {
+		return x.Query
+	}
+	return nil
+}
+
+func (x *ExportLogEntriesQuery) GetFormat() string {
+	if x != nil {
+		return x.Format
+	}
+	return ""
+}
+
+func (x *ExportLogEntriesQuery) GetCompression() string {
+	if x != nil {
+		return x.Compression
+	}
+	return ""
+}
+
+func (x *ExportLogEntriesQuery) GetMaxRows() int64 {
+	if x != nil {
+		return x.MaxRows
+	}
+	return 0
+}
+
+type ExportChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Consecutive bytes of the exported file
+	Data          []byte `protobuf:"bytes,1,opt,name=Data,proto3" json:"Data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ExportChunk) Reset() {
+	*x = ExportChunk{}
+	mi := &file_logs_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ExportChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ExportChunk) ProtoMessage() {}
+
+func (x *ExportChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_logs_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ExportChunk.ProtoReflect.Descriptor instead.
+func (*ExportChunk) Descriptor() ([]byte, []int) {
+	return file_logs_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *ExportChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
 var File_logs_proto protoreflect.FileDescriptor
 
 const file_logs_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"logs.proto\x12\x04logs\"\xbc\x02\n" +
+	"logs.proto\x12\x04logs\"\xab\x03\n" +
 	"\bLogEntry\x12\x0e\n" +
 	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x18\n" +
 	"\aTraceNo\x18\x02 \x01(\tR\aTraceNo\x12\x12\n" +
@@ -616,17 +1329,46 @@ const file_logs_proto_rawDesc = "" +
 	"\x05Flags\x18\t \x01(\x03R\x05Flags\x12\"\n" +
 	"\fCreatedOnUtc\x18\n" +
 	" \x01(\x03R\fCreatedOnUtc\x12(\n" +
-	"\x0fCreatedOnUtcISO\x18\v \x01(\tR\x0fCreatedOnUtcISO\"Y\n" +
+	"\x0fCreatedOnUtcISO\x18\v \x01(\tR\x0fCreatedOnUtcISO\x122\n" +
+	"\x06Labels\x18\f \x03(\v2\x1a.logs.LogEntry.LabelsEntryR\x06Labels\x1a9\n" +
+	"\vLabelsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"u\n" +
 	"\x0fWriteLogCommand\x12\x1a\n" +
 	"\bClientID\x18\x01 \x01(\tR\bClientID\x12*\n" +
-	"\bLogEntry\x18\x02 \x01(\v2\x0e.logs.LogEntryR\bLogEntry\"U\n" +
+	"\bLogEntry\x18\x02 \x01(\v2\x0e.logs.LogEntryR\bLogEntry\x12\x1a\n" +
+	"\bSequence\x18\x03 \x01(\x03R\bSequence\"d\n" +
+	"\x16WriteLogEntriesCommand\x12\x1a\n" +
+	"\bClientID\x18\x01 \x01(\tR\bClientID\x12.\n" +
+	"\n" +
+	"LogEntries\x18\x02 \x03(\v2\x0e.logs.LogEntryR\n" +
+	"LogEntries\"?\n" +
+	"\x13WriteLogEntryResult\x12\x0e\n" +
+	"\x02ID\x18\x01 \x01(\tR\x02ID\x12\x18\n" +
+	"\aMessage\x18\x02 \x01(\tR\aMessage\"f\n" +
+	"\x15WriteLogEntriesResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x123\n" +
+	"\aResults\x18\x02 \x03(\v2\x19.logs.WriteLogEntryResultR\aResults\"K\n" +
+	"\x13StreamLogEntriesAck\x12\x1a\n" +
+	"\bSequence\x18\x01 \x01(\x03R\bSequence\x12\x18\n" +
+	"\aMessage\x18\x02 \x01(\tR\aMessage\"\x9f\x01\n" +
+	"\x13TailLogEntriesQuery\x12\x1a\n" +
+	"\bClientID\x18\x01 \x01(\tR\bClientID\x12$\n" +
+	"\x05Level\x18\x02 \x01(\x0e2\x0e.logs.LogLevelR\x05Level\x12\x12\n" +
+	"\x04User\x18\x03 \x01(\tR\x04User\x12\x18\n" +
+	"\aTraceNo\x18\x04 \x01(\tR\aTraceNo\x12\x18\n" +
+	"\aMessage\x18\x05 \x01(\tR\aMessage\"U\n" +
 	"\rLogEntryQuery\x12\x16\n" +
 	"\x06DBName\x18\x01 \x01(\tR\x06DBName\x12\x1c\n" +
 	"\tTableName\x18\x02 \x01(\tR\tTableName\x12\x0e\n" +
 	"\x02ID\x18\x03 \x01(\tR\x02ID\"V\n" +
 	"\x0eLogEntryResult\x12\x18\n" +
 	"\aMessage\x18\x01 \x01(\tR\aMessage\x12*\n" +
-	"\bLogEntry\x18\x02 \x01(\v2\x0e.logs.LogEntryR\bLogEntry\"\xa9\x03\n" +
+	"\bLogEntry\x18\x02 \x01(\v2\x0e.logs.LogEntryR\bLogEntry\"M\n" +
+	"\vLabelFilter\x12\x10\n" +
+	"\x03Key\x18\x01 \x01(\tR\x03Key\x12\x14\n" +
+	"\x05Value\x18\x02 \x01(\tR\x05Value\x12\x16\n" +
+	"\x06Prefix\x18\x03 \x01(\bR\x06Prefix\"\x9e\x04\n" +
 	"\x0fLogEntriesQuery\x12\x16\n" +
 	"\x06DBName\x18\x01 \x01(\tR\x06DBName\x12\x1c\n" +
 	"\tTableName\x18\x02 \x01(\tR\tTableName\x12\x18\n" +
@@ -645,7 +1387,21 @@ const file_logs_proto_rawDesc = "" +
 	"\bPageSize\x18\f \x01(\x05R\bPageSize\x12\x1c\n" +
 	"\tPageIndex\x18\r \x01(\x05R\tPageIndex\x12\x18\n" +
 	"\aOrderBy\x18\x0e \x01(\x05R\aOrderBy\x12\x14\n" +
-	"\x05Flags\x18\x0f \x01(\x03R\x05Flags\"|\n" +
+	"\x05Flags\x18\x0f \x01(\x03R\x05Flags\x12)\n" +
+	"\x06Labels\x18\x10 \x03(\v2\x11.logs.LabelFilterR\x06Labels\x12\x16\n" +
+	"\x06Cursor\x18\x11 \x01(\tR\x06Cursor\x12\x1a\n" +
+	"\bClientID\x18\x12 \x01(\tR\bClientID\x12\x14\n" +
+	"\x05Query\x18\x13 \x01(\tR\x05Query\"\\\n" +
+	"\x11LogHistogramQuery\x12+\n" +
+	"\x05Query\x18\x01 \x01(\v2\x15.logs.LogEntriesQueryR\x05Query\x12\x1a\n" +
+	"\bInterval\x18\x02 \x01(\x03R\bInterval\"n\n" +
+	"\x12LogHistogramBucket\x12\x1c\n" +
+	"\tStartTime\x18\x01 \x01(\x03R\tStartTime\x12$\n" +
+	"\x05Level\x18\x02 \x01(\x0e2\x0e.logs.LogLevelR\x05Level\x12\x14\n" +
+	"\x05Count\x18\x03 \x01(\x03R\x05Count\"b\n" +
+	"\x12LogHistogramResult\x12\x18\n" +
+	"\aMessage\x18\x01 \x01(\tR\aMessage\x122\n" +
+	"\aBuckets\x18\x02 \x03(\v2\x18.logs.LogHistogramBucketR\aBuckets\"\xbc\x01\n" +
 	"\x10LogEntriesResult\x12\x18\n" +
 	"\aMessage\x18\x01 \x01(\tR\aMessage\x12\x1e\n" +
 	"\n" +
@@ -653,7 +1409,20 @@ const file_logs_proto_rawDesc = "" +
 	"TotalCount\x12.\n" +
 	"\n" +
 	"LogEntries\x18\x03 \x03(\v2\x0e.logs.LogEntryR\n" +
-	"LogEntries*U\n" +
+	"LogEntries\x12\x1e\n" +
+	"\n" +
+	"NextCursor\x18\x04 \x01(\tR\n" +
+	"NextCursor\x12\x1e\n" +
+	"\n" +
+	"PrevCursor\x18\x05 \x01(\tR\n" +
+	"PrevCursor\"\x98\x01\n" +
+	"\x15ExportLogEntriesQuery\x12+\n" +
+	"\x05Query\x18\x01 \x01(\v2\x15.logs.LogEntriesQueryR\x05Query\x12\x16\n" +
+	"\x06Format\x18\x02 \x01(\tR\x06Format\x12 \n" +
+	"\vCompression\x18\x03 \x01(\tR\vCompression\x12\x18\n" +
+	"\aMaxRows\x18\x04 \x01(\x03R\aMaxRows\"!\n" +
+	"\vExportChunk\x12\x12\n" +
+	"\x04Data\x18\x01 \x01(\fR\x04Data*U\n" +
 	"\bLogLevel\x12\v\n" +
 	"\aVerbose\x10\x00\x12\t\n" +
 	"\x05Debug\x10\x01\x12\x0e\n" +
@@ -661,11 +1430,16 @@ const file_logs_proto_rawDesc = "" +
 	"Infomation\x10\x02\x12\v\n" +
 	"\aWarning\x10\x03\x12\t\n" +
 	"\x05Error\x10\x04\x12\t\n" +
-	"\x05Fatal\x10\x052\xc9\x01\n" +
+	"\x05Fatal\x10\x052\xac\x04\n" +
 	"\x0fLogEntryService\x12<\n" +
-	"\rWriteLogEntry\x12\x15.logs.WriteLogCommand\x1a\x14.logs.LogEntryResult\x128\n" +
+	"\rWriteLogEntry\x12\x15.logs.WriteLogCommand\x1a\x14.logs.LogEntryResult\x12L\n" +
+	"\x0fWriteLogEntries\x12\x1c.logs.WriteLogEntriesCommand\x1a\x1b.logs.WriteLogEntriesResult\x12H\n" +
+	"\x10StreamLogEntries\x12\x15.logs.WriteLogCommand\x1a\x19.logs.StreamLogEntriesAck(\x010\x01\x12=\n" +
+	"\x0eTailLogEntries\x12\x19.logs.TailLogEntriesQuery\x1a\x0e.logs.LogEntry0\x01\x128\n" +
 	"\vGetLogEntry\x12\x13.logs.LogEntryQuery\x1a\x14.logs.LogEntryResult\x12>\n" +
-	"\rGetLogEntries\x12\x15.logs.LogEntriesQuery\x1a\x16.logs.LogEntriesResultB\x1dZ\x1bgithub.com/DreamvatLab/logsb\x06proto3"
+	"\rGetLogEntries\x12\x15.logs.LogEntriesQuery\x1a\x16.logs.LogEntriesResult\x12D\n" +
+	"\x0fGetLogHistogram\x12\x17.logs.LogHistogramQuery\x1a\x18.logs.LogHistogramResult\x12D\n" +
+	"\x10ExportLogEntries\x12\x1b.logs.ExportLogEntriesQuery\x1a\x11.logs.ExportChunk0\x01B\x1dZ\x1bgithub.com/DreamvatLab/logsb\x06proto3"
 
 var (
 	file_logs_proto_rawDescOnce sync.Once
@@ -680,33 +1454,64 @@ func file_logs_proto_rawDescGZIP() []byte {
 }
 
 var file_logs_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_logs_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_logs_proto_msgTypes = make([]protoimpl.MessageInfo, 18)
 var file_logs_proto_goTypes = []any{
-	(LogLevel)(0),            // 0: logs.LogLevel
-	(*LogEntry)(nil),         // 1: logs.LogEntry
-	(*WriteLogCommand)(nil),  // 2: logs.WriteLogCommand
-	(*LogEntryQuery)(nil),    // 3: logs.LogEntryQuery
-	(*LogEntryResult)(nil),   // 4: logs.LogEntryResult
-	(*LogEntriesQuery)(nil),  // 5: logs.LogEntriesQuery
-	(*LogEntriesResult)(nil), // 6: logs.LogEntriesResult
+	(LogLevel)(0),                  // 0: logs.LogLevel
+	(*LogEntry)(nil),               // 1: logs.LogEntry
+	(*WriteLogCommand)(nil),        // 2: logs.WriteLogCommand
+	(*WriteLogEntriesCommand)(nil), // 3: logs.WriteLogEntriesCommand
+	(*WriteLogEntryResult)(nil),    // 4: logs.WriteLogEntryResult
+	(*WriteLogEntriesResult)(nil),  // 5: logs.WriteLogEntriesResult
+	(*StreamLogEntriesAck)(nil),    // 6: logs.StreamLogEntriesAck
+	(*TailLogEntriesQuery)(nil),    // 7: logs.TailLogEntriesQuery
+	(*LogEntryQuery)(nil),          // 8: logs.LogEntryQuery
+	(*LogEntryResult)(nil),         // 9: logs.LogEntryResult
+	(*LabelFilter)(nil),            // 10: logs.LabelFilter
+	(*LogEntriesQuery)(nil),        // 11: logs.LogEntriesQuery
+	(*LogHistogramQuery)(nil),      // 12: logs.LogHistogramQuery
+	(*LogHistogramBucket)(nil),     // 13: logs.LogHistogramBucket
+	(*LogHistogramResult)(nil),     // 14: logs.LogHistogramResult
+	(*LogEntriesResult)(nil),       // 15: logs.LogEntriesResult
+	(*ExportLogEntriesQuery)(nil),  // 16: logs.ExportLogEntriesQuery
+	(*ExportChunk)(nil),            // 17: logs.ExportChunk
+	nil,                            // 18: logs.LogEntry.LabelsEntry
 }
 var file_logs_proto_depIdxs = []int32{
-	0, // 0: logs.LogEntry.Level:type_name -> logs.LogLevel
-	1, // 1: logs.WriteLogCommand.LogEntry:type_name -> logs.LogEntry
-	1, // 2: logs.LogEntryResult.LogEntry:type_name -> logs.LogEntry
-	0, // 3: logs.LogEntriesQuery.Level:type_name -> logs.LogLevel
-	1, // 4: logs.LogEntriesResult.LogEntries:type_name -> logs.LogEntry
-	2, // 5: logs.LogEntryService.WriteLogEntry:input_type -> logs.WriteLogCommand
-	3, // 6: logs.LogEntryService.GetLogEntry:input_type -> logs.LogEntryQuery
-	5, // 7: logs.LogEntryService.GetLogEntries:input_type -> logs.LogEntriesQuery
-	4, // 8: logs.LogEntryService.WriteLogEntry:output_type -> logs.LogEntryResult
-	4, // 9: logs.LogEntryService.GetLogEntry:output_type -> logs.LogEntryResult
-	6, // 10: logs.LogEntryService.GetLogEntries:output_type -> logs.LogEntriesResult
-	8, // [8:11] is the sub-list for method output_type
-	5, // [5:8] is the sub-list for method input_type
-	5, // [5:5] is the sub-list for extension type_name
-	5, // [5:5] is the sub-list for extension extendee
-	0, // [0:5] is the sub-list for field type_name
+	0,  // 0: logs.LogEntry.Level:type_name -> logs.LogLevel
+	18, // 1: logs.LogEntry.Labels:type_name -> logs.LogEntry.LabelsEntry
+	1,  // 2: logs.WriteLogCommand.LogEntry:type_name -> logs.LogEntry
+	1,  // 3: logs.WriteLogEntriesCommand.LogEntries:type_name -> logs.LogEntry
+	4,  // 4: logs.WriteLogEntriesResult.Results:type_name -> logs.WriteLogEntryResult
+	0,  // 5: logs.TailLogEntriesQuery.Level:type_name -> logs.LogLevel
+	1,  // 6: logs.LogEntryResult.LogEntry:type_name -> logs.LogEntry
+	0,  // 7: logs.LogEntriesQuery.Level:type_name -> logs.LogLevel
+	10, // 8: logs.LogEntriesQuery.Labels:type_name -> logs.LabelFilter
+	11, // 9: logs.LogHistogramQuery.Query:type_name -> logs.LogEntriesQuery
+	0,  // 10: logs.LogHistogramBucket.Level:type_name -> logs.LogLevel
+	13, // 11: logs.LogHistogramResult.Buckets:type_name -> logs.LogHistogramBucket
+	1,  // 12: logs.LogEntriesResult.LogEntries:type_name -> logs.LogEntry
+	11, // 13: logs.ExportLogEntriesQuery.Query:type_name -> logs.LogEntriesQuery
+	2,  // 14: logs.LogEntryService.WriteLogEntry:input_type -> logs.WriteLogCommand
+	3,  // 15: logs.LogEntryService.WriteLogEntries:input_type -> logs.WriteLogEntriesCommand
+	2,  // 16: logs.LogEntryService.StreamLogEntries:input_type -> logs.WriteLogCommand
+	7,  // 17: logs.LogEntryService.TailLogEntries:input_type -> logs.TailLogEntriesQuery
+	8,  // 18: logs.LogEntryService.GetLogEntry:input_type -> logs.LogEntryQuery
+	11, // 19: logs.LogEntryService.GetLogEntries:input_type -> logs.LogEntriesQuery
+	12, // 20: logs.LogEntryService.GetLogHistogram:input_type -> logs.LogHistogramQuery
+	16, // 21: logs.LogEntryService.ExportLogEntries:input_type -> logs.ExportLogEntriesQuery
+	9,  // 22: logs.LogEntryService.WriteLogEntry:output_type -> logs.LogEntryResult
+	5,  // 23: logs.LogEntryService.WriteLogEntries:output_type -> logs.WriteLogEntriesResult
+	6,  // 24: logs.LogEntryService.StreamLogEntries:output_type -> logs.StreamLogEntriesAck
+	1,  // 25: logs.LogEntryService.TailLogEntries:output_type -> logs.LogEntry
+	9,  // 26: logs.LogEntryService.GetLogEntry:output_type -> logs.LogEntryResult
+	15, // 27: logs.LogEntryService.GetLogEntries:output_type -> logs.LogEntriesResult
+	14, // 28: logs.LogEntryService.GetLogHistogram:output_type -> logs.LogHistogramResult
+	17, // 29: logs.LogEntryService.ExportLogEntries:output_type -> logs.ExportChunk
+	22, // [22:30] is the sub-list for method output_type
+	14, // [14:22] is the sub-list for method input_type
+	14, // [14:14] is the sub-list for extension type_name
+	14, // [14:14] is the sub-list for extension extendee
+	0,  // [0:14] is the sub-list for field type_name
 }
 
 func init() { file_logs_proto_init() }
@@ -720,7 +1525,7 @@ func file_logs_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_logs_proto_rawDesc), len(file_logs_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   6,
+			NumMessages:   18,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -21,9 +21,14 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	LogEntryService_WriteLogEntry_FullMethodName = "/logs.LogEntryService/WriteLogEntry"
-	LogEntryService_GetLogEntry_FullMethodName   = "/logs.LogEntryService/GetLogEntry"
-	LogEntryService_GetLogEntries_FullMethodName = "/logs.LogEntryService/GetLogEntries"
+	LogEntryService_WriteLogEntry_FullMethodName    = "/logs.LogEntryService/WriteLogEntry"
+	LogEntryService_WriteLogEntries_FullMethodName  = "/logs.LogEntryService/WriteLogEntries"
+	LogEntryService_StreamLogEntries_FullMethodName = "/logs.LogEntryService/StreamLogEntries"
+	LogEntryService_TailLogEntries_FullMethodName   = "/logs.LogEntryService/TailLogEntries"
+	LogEntryService_GetLogEntry_FullMethodName      = "/logs.LogEntryService/GetLogEntry"
+	LogEntryService_GetLogEntries_FullMethodName    = "/logs.LogEntryService/GetLogEntries"
+	LogEntryService_GetLogHistogram_FullMethodName  = "/logs.LogEntryService/GetLogHistogram"
+	LogEntryService_ExportLogEntries_FullMethodName = "/logs.LogEntryService/ExportLogEntries"
 )
 
 // LogEntryServiceClient is the client API for LogEntryService service.
@@ -31,8 +36,13 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type LogEntryServiceClient interface {
 	WriteLogEntry(ctx context.Context, in *WriteLogCommand, opts ...grpc.CallOption) (*LogEntryResult, error)
+	WriteLogEntries(ctx context.Context, in *WriteLogEntriesCommand, opts ...grpc.CallOption) (*WriteLogEntriesResult, error)
+	StreamLogEntries(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[WriteLogCommand, StreamLogEntriesAck], error)
+	TailLogEntries(ctx context.Context, in *TailLogEntriesQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error)
 	GetLogEntry(ctx context.Context, in *LogEntryQuery, opts ...grpc.CallOption) (*LogEntryResult, error)
 	GetLogEntries(ctx context.Context, in *LogEntriesQuery, opts ...grpc.CallOption) (*LogEntriesResult, error)
+	GetLogHistogram(ctx context.Context, in *LogHistogramQuery, opts ...grpc.CallOption) (*LogHistogramResult, error)
+	ExportLogEntries(ctx context.Context, in *ExportLogEntriesQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error)
 }
 
 type logEntryServiceClient struct {
@@ -53,6 +63,48 @@ func (c *logEntryServiceClient) WriteLogEntry(ctx context.Context, in *WriteLogC
 	return out, nil
 }
 
+func (c *logEntryServiceClient) WriteLogEntries(ctx context.Context, in *WriteLogEntriesCommand, opts ...grpc.CallOption) (*WriteLogEntriesResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WriteLogEntriesResult)
+	err := c.cc.Invoke(ctx, LogEntryService_WriteLogEntries_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logEntryServiceClient) StreamLogEntries(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[WriteLogCommand, StreamLogEntriesAck], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogEntryService_ServiceDesc.Streams[0], LogEntryService_StreamLogEntries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WriteLogCommand, StreamLogEntriesAck]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogEntryService_StreamLogEntriesClient = grpc.BidiStreamingClient[WriteLogCommand, StreamLogEntriesAck]
+
+func (c *logEntryServiceClient) TailLogEntries(ctx context.Context, in *TailLogEntriesQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[LogEntry], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogEntryService_ServiceDesc.Streams[1], LogEntryService_TailLogEntries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[TailLogEntriesQuery, LogEntry]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogEntryService_TailLogEntriesClient = grpc.ServerStreamingClient[LogEntry]
+
 func (c *logEntryServiceClient) GetLogEntry(ctx context.Context, in *LogEntryQuery, opts ...grpc.CallOption) (*LogEntryResult, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(LogEntryResult)
@@ -73,13 +125,47 @@ func (c *logEntryServiceClient) GetLogEntries(ctx context.Context, in *LogEntrie
 	return out, nil
 }
 
+func (c *logEntryServiceClient) GetLogHistogram(ctx context.Context, in *LogHistogramQuery, opts ...grpc.CallOption) (*LogHistogramResult, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LogHistogramResult)
+	err := c.cc.Invoke(ctx, LogEntryService_GetLogHistogram_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *logEntryServiceClient) ExportLogEntries(ctx context.Context, in *ExportLogEntriesQuery, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ExportChunk], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &LogEntryService_ServiceDesc.Streams[2], LogEntryService_ExportLogEntries_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[ExportLogEntriesQuery, ExportChunk]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogEntryService_ExportLogEntriesClient = grpc.ServerStreamingClient[ExportChunk]
+
 // LogEntryServiceServer is the server API for LogEntryService service.
 // All implementations should embed UnimplementedLogEntryServiceServer
 // for forward compatibility.
 type LogEntryServiceServer interface {
 	WriteLogEntry(context.Context, *WriteLogCommand) (*LogEntryResult, error)
+	WriteLogEntries(context.Context, *WriteLogEntriesCommand) (*WriteLogEntriesResult, error)
+	StreamLogEntries(grpc.BidiStreamingServer[WriteLogCommand, StreamLogEntriesAck]) error
+	TailLogEntries(*TailLogEntriesQuery, grpc.ServerStreamingServer[LogEntry]) error
 	GetLogEntry(context.Context, *LogEntryQuery) (*LogEntryResult, error)
 	GetLogEntries(context.Context, *LogEntriesQuery) (*LogEntriesResult, error)
+	GetLogHistogram(context.Context, *LogHistogramQuery) (*LogHistogramResult, error)
+	ExportLogEntries(*ExportLogEntriesQuery, grpc.ServerStreamingServer[ExportChunk]) error
 }
 
 // UnimplementedLogEntryServiceServer should be embedded to have
@@ -92,12 +178,27 @@ type UnimplementedLogEntryServiceServer struct{}
 func (UnimplementedLogEntryServiceServer) WriteLogEntry(context.Context, *WriteLogCommand) (*LogEntryResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method WriteLogEntry not implemented")
 }
+func (UnimplementedLogEntryServiceServer) WriteLogEntries(context.Context, *WriteLogEntriesCommand) (*WriteLogEntriesResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteLogEntries not implemented")
+}
+func (UnimplementedLogEntryServiceServer) StreamLogEntries(grpc.BidiStreamingServer[WriteLogCommand, StreamLogEntriesAck]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamLogEntries not implemented")
+}
+func (UnimplementedLogEntryServiceServer) TailLogEntries(*TailLogEntriesQuery, grpc.ServerStreamingServer[LogEntry]) error {
+	return status.Errorf(codes.Unimplemented, "method TailLogEntries not implemented")
+}
 func (UnimplementedLogEntryServiceServer) GetLogEntry(context.Context, *LogEntryQuery) (*LogEntryResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLogEntry not implemented")
 }
 func (UnimplementedLogEntryServiceServer) GetLogEntries(context.Context, *LogEntriesQuery) (*LogEntriesResult, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLogEntries not implemented")
 }
+func (UnimplementedLogEntryServiceServer) GetLogHistogram(context.Context, *LogHistogramQuery) (*LogHistogramResult, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetLogHistogram not implemented")
+}
+func (UnimplementedLogEntryServiceServer) ExportLogEntries(*ExportLogEntriesQuery, grpc.ServerStreamingServer[ExportChunk]) error {
+	return status.Errorf(codes.Unimplemented, "method ExportLogEntries not implemented")
+}
 func (UnimplementedLogEntryServiceServer) testEmbeddedByValue() {}
 
 // UnsafeLogEntryServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -136,6 +237,42 @@ func _LogEntryService_WriteLogEntry_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogEntryService_WriteLogEntries_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WriteLogEntriesCommand)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogEntryServiceServer).WriteLogEntries(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogEntryService_WriteLogEntries_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogEntryServiceServer).WriteLogEntries(ctx, req.(*WriteLogEntriesCommand))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogEntryService_StreamLogEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(LogEntryServiceServer).StreamLogEntries(&grpc.GenericServerStream[WriteLogCommand, StreamLogEntriesAck]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogEntryService_StreamLogEntriesServer = grpc.BidiStreamingServer[WriteLogCommand, StreamLogEntriesAck]
+
+func _LogEntryService_TailLogEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailLogEntriesQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogEntryServiceServer).TailLogEntries(m, &grpc.GenericServerStream[TailLogEntriesQuery, LogEntry]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogEntryService_TailLogEntriesServer = grpc.ServerStreamingServer[LogEntry]
+
 func _LogEntryService_GetLogEntry_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(LogEntryQuery)
 	if err := dec(in); err != nil {
@@ -172,6 +309,35 @@ func _LogEntryService_GetLogEntries_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _LogEntryService_GetLogHistogram_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(LogHistogramQuery)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(LogEntryServiceServer).GetLogHistogram(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: LogEntryService_GetLogHistogram_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(LogEntryServiceServer).GetLogHistogram(ctx, req.(*LogHistogramQuery))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _LogEntryService_ExportLogEntries_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ExportLogEntriesQuery)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(LogEntryServiceServer).ExportLogEntries(m, &grpc.GenericServerStream[ExportLogEntriesQuery, ExportChunk]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type LogEntryService_ExportLogEntriesServer = grpc.ServerStreamingServer[ExportChunk]
+
 // LogEntryService_ServiceDesc is the grpc.ServiceDesc for LogEntryService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -183,6 +349,10 @@ var LogEntryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "WriteLogEntry",
 			Handler:    _LogEntryService_WriteLogEntry_Handler,
 		},
+		{
+			MethodName: "WriteLogEntries",
+			Handler:    _LogEntryService_WriteLogEntries_Handler,
+		},
 		{
 			MethodName: "GetLogEntry",
 			Handler:    _LogEntryService_GetLogEntry_Handler,
@@ -191,7 +361,28 @@ var LogEntryService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLogEntries",
 			Handler:    _LogEntryService_GetLogEntries_Handler,
 		},
+		{
+			MethodName: "GetLogHistogram",
+			Handler:    _LogEntryService_GetLogHistogram_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamLogEntries",
+			Handler:       _LogEntryService_StreamLogEntries_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "TailLogEntries",
+			Handler:       _LogEntryService_TailLogEntries_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ExportLogEntries",
+			Handler:       _LogEntryService_ExportLogEntries_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "logs.proto",
 }
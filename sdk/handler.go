@@ -0,0 +1,246 @@
+package sdk
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/DreamvatLab/logs"
+)
+
+const (
+	_ATTR_ERROR    = "error"
+	_ATTR_TRACE_NO = "trace_no"
+	_ATTR_USER     = "user"
+)
+
+// Handler is a slog.Handler writing records to a Logger.
+// Top level "error", "trace_no" and "user" attributes go to Error/StackTrace, TraceNo and User,
+// all other attributes are serialized as JSON into Payload.
+type Handler struct {
+	logger  *Logger
+	traceNo string
+	user    string
+	err     error
+	payload map[string]any
+	groups  []string
+}
+
+func NewHandler(logger *Logger) *Handler {
+	return &Handler{
+		logger:  logger,
+		payload: make(map[string]any),
+	}
+}
+
+// ToLogLevel maps a slog level to the closest LogLevel
+func ToLogLevel(level slog.Level) logs.LogLevel {
+	switch {
+	case level < slog.LevelDebug:
+		return logs.LogLevel_Verbose
+	case level < slog.LevelInfo:
+		return logs.LogLevel_Debug
+	case level < slog.LevelWarn:
+		return logs.LogLevel_Infomation
+	case level < slog.LevelError:
+		return logs.LogLevel_Warning
+	case level < slog.LevelError+4:
+		return logs.LogLevel_Error
+	default:
+		return logs.LogLevel_Fatal
+	}
+}
+
+// Enabled filters by Options.Level and the level of the client, see Logger.Enabled
+func (o *Handler) Enabled(_ context.Context, level slog.Level) bool {
+	return o.logger.Enabled(ToLogLevel(level))
+}
+
+func (o *Handler) Handle(_ context.Context, record slog.Record) error {
+	entry := &logs.LogEntry{
+		Level:   ToLogLevel(record.Level),
+		Message: record.Message,
+		TraceNo: o.traceNo,
+		User:    o.user,
+	}
+	if !record.Time.IsZero() {
+		entry.CreatedOnUtc = record.Time.UnixMilli()
+	}
+
+	err := o.err
+	payload := cloneMap(o.payload)
+	target := groupMap(payload, o.groups)
+	record.Attrs(func(attr slog.Attr) bool {
+		if len(o.groups) == 0 {
+			switch attr.Key {
+			case _ATTR_ERROR:
+				err = toError(attr.Value)
+				return true
+			case _ATTR_TRACE_NO:
+				entry.TraceNo = attr.Value.String()
+				return true
+			case _ATTR_USER:
+				entry.User = attr.Value.String()
+				return true
+			}
+		}
+		addAttr(target, attr)
+		return true
+	})
+	setError(entry, err)
+
+	payload = pruneMap(payload)
+	if len(payload) > 0 {
+		jsonBytes, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		entry.Payload = string(jsonBytes)
+	}
+
+	return o.logger.Write(entry)
+}
+
+func (o *Handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return o
+	}
+
+	r := o.clone()
+	target := groupMap(r.payload, r.groups)
+	for _, attr := range attrs {
+		if len(r.groups) == 0 {
+			switch attr.Key {
+			case _ATTR_ERROR:
+				r.err = toError(attr.Value)
+				continue
+			case _ATTR_TRACE_NO:
+				r.traceNo = attr.Value.String()
+				continue
+			case _ATTR_USER:
+				r.user = attr.Value.String()
+				continue
+			}
+		}
+		addAttr(target, attr)
+	}
+
+	return r
+}
+
+func (o *Handler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return o
+	}
+
+	r := o.clone()
+	r.groups = append(r.groups, name)
+	return r
+}
+
+func (o *Handler) clone() *Handler {
+	r := *o
+	r.payload = cloneMap(o.payload)
+	r.groups = slices.Clip(o.groups)
+	return &r
+}
+
+// ************************************************************************************************
+
+type stringError string
+
+func (o stringError) Error() string {
+	return string(o)
+}
+
+func toError(v slog.Value) error {
+	v = v.Resolve()
+	if v.Kind() == slog.KindAny {
+		if err, ok := v.Any().(error); ok {
+			return err
+		}
+	}
+
+	if s := v.String(); s != "" {
+		return stringError(s)
+	}
+	return nil
+}
+
+func addAttr(m map[string]any, attr slog.Attr) {
+	v := attr.Value.Resolve()
+
+	if v.Kind() == slog.KindGroup {
+		attrs := v.Group()
+		if len(attrs) == 0 {
+			return
+		}
+
+		// Group with empty key is inlined
+		target := m
+		if attr.Key != "" {
+			target = groupMap(m, []string{attr.Key})
+		}
+		for _, x := range attrs {
+			addAttr(target, x)
+		}
+		return
+	}
+
+	if attr.Key == "" {
+		return
+	}
+
+	switch v.Kind() {
+	case slog.KindTime:
+		m[attr.Key] = v.Time().UTC().Format(time.RFC3339Nano)
+	case slog.KindDuration:
+		m[attr.Key] = v.Duration().String()
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			m[attr.Key] = err.Error()
+		} else {
+			m[attr.Key] = v.Any()
+		}
+	default:
+		m[attr.Key] = v.Any()
+	}
+}
+
+// groupMap returns the nested map of the group path, creating missing ones
+func groupMap(m map[string]any, groups []string) map[string]any {
+	for _, x := range groups {
+		sub, ok := m[x].(map[string]any)
+		if !ok {
+			sub = make(map[string]any)
+			m[x] = sub
+		}
+		m = sub
+	}
+	return m
+}
+
+func cloneMap(m map[string]any) map[string]any {
+	r := make(map[string]any, len(m))
+	for k, v := range m {
+		if sub, ok := v.(map[string]any); ok {
+			v = cloneMap(sub)
+		}
+		r[k] = v
+	}
+	return r
+}
+
+// pruneMap removes empty groups
+func pruneMap(m map[string]any) map[string]any {
+	for k, v := range m {
+		if sub, ok := v.(map[string]any); ok {
+			if len(pruneMap(sub)) == 0 {
+				delete(m, k)
+			}
+		}
+	}
+	return m
+}
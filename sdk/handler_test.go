@@ -0,0 +1,155 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/sdk"
+)
+
+func TestHandlerClientLevel(t *testing.T) {
+	server, clients, conn := startServers(t)
+	clients.level.Store(int32(logs.LogLevel_Warning))
+	logger := sdk.NewLogger(logs.NewLogEntryServiceClient(conn), sdk.Options{
+		ClientID:      "c1",
+		Level:         logs.LogLevel_Debug,
+		ClientService: logs.NewLogClientServiceClient(conn),
+		FlushInterval: time.Hour,
+	})
+	defer logger.Close()
+
+	err := logger.RefreshLevel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := sdk.NewHandler(logger)
+	ctx := context.Background()
+	if handler.Enabled(ctx, slog.LevelInfo) || !handler.Enabled(ctx, slog.LevelWarn) {
+		t.Errorf("Enabled(info, warn) = %v, %v, want false, true under the client level", handler.Enabled(ctx, slog.LevelInfo), handler.Enabled(ctx, slog.LevelWarn))
+	}
+
+	log := slog.New(handler)
+	log.Info("dropped")
+	log.Warn("kept")
+
+	// Lowering the level of the client takes effect after a refresh, Options.Level still applies
+	clients.level.Store(int32(logs.LogLevel_Verbose))
+	err = logger.RefreshLevel()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !handler.Enabled(ctx, slog.LevelInfo) || handler.Enabled(ctx, slog.LevelDebug-4) {
+		t.Errorf("Enabled(info, verbose) = %v, %v, want true, false under Options.Level", handler.Enabled(ctx, slog.LevelInfo), handler.Enabled(ctx, slog.LevelDebug-4))
+	}
+	log.Info("info")
+
+	err = logger.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := server.entries()
+	if len(got) != 2 || got[0].Message != "kept" || got[1].Message != "info" {
+		t.Errorf("entries = %v, want kept and info", got)
+	}
+}
+
+// stackError prints a stack with %+v like errors of xerr
+type stackError string
+
+func (o stackError) Error() string {
+	return string(o)
+}
+
+func (o stackError) Format(s fmt.State, verb rune) {
+	if verb == 'v' && s.Flag('+') {
+		fmt.Fprintf(s, "%s\nmain.go:10", string(o))
+		return
+	}
+	fmt.Fprint(s, string(o))
+}
+
+func TestHandlerAttrs(t *testing.T) {
+	created := time.Date(2024, 5, 6, 7, 8, 9, 0, time.FixedZone("", 3600))
+	cases := []struct {
+		name string
+		log  func(log *slog.Logger)
+		want *logs.LogEntry
+	}{
+		{
+			"Reserved",
+			func(log *slog.Logger) {
+				log.Error("failed", "error", errors.New("timeout"), "trace_no", "t1", "user", "bob", "id", 7)
+			},
+			&logs.LogEntry{Level: logs.LogLevel_Error, Message: "failed", Error: "timeout", TraceNo: "t1", User: "bob", Payload: `{"id":7}`},
+		},
+		{
+			"StackTrace",
+			func(log *slog.Logger) { log.Error("failed", "error", stackError("boom")) },
+			&logs.LogEntry{Level: logs.LogLevel_Error, Message: "failed", Error: "boom", StackTrace: "boom\nmain.go:10"},
+		},
+		{
+			"StringError",
+			func(log *slog.Logger) { log.Warn("slow", "error", "deadline") },
+			&logs.LogEntry{Level: logs.LogLevel_Warning, Message: "slow", Error: "deadline"},
+		},
+		{
+			"WithAttrs",
+			func(log *slog.Logger) {
+				log.With("trace_no", "t1", "user", "bob", "app", "api").Info("started", "user", "erin")
+			},
+			&logs.LogEntry{Level: logs.LogLevel_Infomation, Message: "started", TraceNo: "t1", User: "erin", Payload: `{"app":"api"}`},
+		},
+		{
+			"Groups",
+			func(log *slog.Logger) {
+				// Reserved keys are only recognized at the top level
+				log.With("app", "api").WithGroup("req").With("id", 1).WithGroup("db").Info("query", "user", "bob", "rows", 3)
+			},
+			&logs.LogEntry{Level: logs.LogLevel_Infomation, Message: "query", Payload: `{"app":"api","req":{"db":{"rows":3,"user":"bob"},"id":1}}`},
+		},
+		{
+			"GroupAttrs",
+			func(log *slog.Logger) {
+				log.Info("done", slog.Group("http", "status", 200, slog.Group("empty")), slog.Group("", "inline", true), "took", time.Second, "at", created)
+			},
+			&logs.LogEntry{Level: logs.LogLevel_Infomation, Message: "done", Payload: `{"at":"2024-05-06T06:08:09Z","http":{"status":200},"inline":true,"took":"1s"}`},
+		},
+		{
+			"EmptyGroups",
+			func(log *slog.Logger) { log.WithGroup("a").WithGroup("b").Info("plain") },
+			&logs.LogEntry{Level: logs.LogLevel_Infomation, Message: "plain"},
+		},
+		{
+			"ErrorInPayload",
+			func(log *slog.Logger) { log.WithGroup("retry").Info("retrying", "error", errors.New("refused")) },
+			&logs.LogEntry{Level: logs.LogLevel_Infomation, Message: "retrying", Payload: `{"retry":{"error":"refused"}}`},
+		},
+	}
+	for _, x := range cases {
+		t.Run(x.name, func(t *testing.T) {
+			server, conn := startServer(t)
+			logger := sdk.NewLogger(logs.NewLogEntryServiceClient(conn), sdk.Options{Level: logs.LogLevel_Debug, FlushInterval: time.Hour})
+			defer logger.Close()
+
+			x.log(slog.New(sdk.NewHandler(logger)))
+			err := logger.Flush()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := server.entries()
+			if len(got) != 1 {
+				t.Fatalf("entries = %v, want 1", got)
+			}
+			if got[0].Level != x.want.Level || got[0].Message != x.want.Message || got[0].Error != x.want.Error || got[0].StackTrace != x.want.StackTrace ||
+				got[0].TraceNo != x.want.TraceNo || got[0].User != x.want.User || got[0].Payload != x.want.Payload {
+				t.Errorf("entry = %v, want %v", got[0], x.want)
+			}
+		})
+	}
+}
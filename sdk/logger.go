@@ -0,0 +1,294 @@
+// Package sdk is the client side of the logs service.
+// Entries are buffered in memory and shipped to the host in batches by a background goroutine.
+package sdk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/DreamvatLab/logs"
+	"google.golang.org/grpc/metadata"
+)
+
+var (
+	ErrClosed     = errors.New("logger is closed")
+	ErrBufferFull = errors.New("log buffer is full")
+)
+
+type Logger struct {
+	client      logs.LogEntryServiceClient
+	options     Options
+	locker      sync.Mutex
+	notFull     *sync.Cond
+	buffer      []*logs.LogEntry
+	closed      bool
+	flushLocker sync.Mutex
+	kick        chan struct{}
+	done        chan struct{}
+	wg          sync.WaitGroup
+	dropped     atomic.Int64
+	clientLevel atomic.Int32
+}
+
+// NewLogger creates a logger and starts its background flushing, call Close to release it
+func NewLogger(client logs.LogEntryServiceClient, options Options) *Logger {
+	options.normalize()
+
+	r := &Logger{
+		client:  client,
+		options: options,
+		buffer:  make([]*logs.LogEntry, 0, options.BatchSize),
+		kick:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	r.notFull = sync.NewCond(&r.locker)
+
+	r.wg.Add(1)
+	go r.run()
+	if options.ClientService != nil {
+		r.wg.Add(1)
+		go r.refreshLevels()
+	}
+
+	return r
+}
+
+func (o *Logger) run() {
+	defer o.wg.Done()
+
+	ticker := time.NewTicker(o.options.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-o.done:
+			return
+		case <-ticker.C:
+		case <-o.kick:
+		}
+
+		err := o.Flush()
+		if err != nil && o.options.ErrorHandler != nil {
+			o.options.ErrorHandler(err)
+		}
+	}
+}
+
+// refreshLevels reads the level of the client now and then every LevelRefreshInterval
+func (o *Logger) refreshLevels() {
+	defer o.wg.Done()
+
+	ticker := time.NewTicker(o.options.LevelRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		err := o.RefreshLevel()
+		if err != nil && o.options.ErrorHandler != nil {
+			o.options.ErrorHandler(err)
+		}
+
+		select {
+		case <-o.done:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RefreshLevel reads LogClient.Level from the host, the last level read is kept when it fails
+func (o *Logger) RefreshLevel() error {
+	if o.options.ClientService == nil {
+		return nil
+	}
+
+	ctx, cancel := o.newContext()
+	defer cancel()
+
+	rs, err := o.options.ClientService.GetClient(ctx, &logs.LogClientQuery{ID: o.options.ClientID})
+	if err != nil {
+		return err
+	} else if rs.Message != "" {
+		return errors.New(rs.Message)
+	} else if rs.LogClient == nil {
+		return fmt.Errorf("client '%s' not found", o.options.ClientID)
+	}
+
+	o.clientLevel.Store(int32(rs.LogClient.Level))
+	return nil
+}
+
+// Enabled reports whether entries of the level will be buffered, it must reach both Options.Level and the level of the client
+func (o *Logger) Enabled(level logs.LogLevel) bool {
+	return level >= max(o.options.Level, logs.LogLevel(o.clientLevel.Load()))
+}
+
+// Dropped returns how many entries have been discarded because the buffer was full
+func (o *Logger) Dropped() int64 {
+	return o.dropped.Load()
+}
+
+// Write buffers an entry, CreatedOnUtc and CreatedOnUtcISO are filled if empty
+func (o *Logger) Write(entry *logs.LogEntry) error {
+	if entry == nil || !o.Enabled(entry.Level) {
+		return nil
+	}
+
+	if entry.CreatedOnUtc == 0 {
+		entry.CreatedOnUtc = time.Now().UnixMilli()
+	}
+	if entry.CreatedOnUtcISO == "" {
+		entry.CreatedOnUtcISO = time.UnixMilli(entry.CreatedOnUtc).UTC().Format(time.RFC3339)
+	}
+
+	o.locker.Lock()
+	for !o.closed && len(o.buffer) >= o.options.BufferSize {
+		switch o.options.DropPolicy {
+		case DropOldest:
+			o.buffer[0] = nil
+			o.buffer = o.buffer[1:]
+			o.dropped.Add(1)
+		case Block:
+			o.notFull.Wait()
+		default:
+			o.locker.Unlock()
+			o.dropped.Add(1)
+			return ErrBufferFull
+		}
+	}
+	if o.closed {
+		o.locker.Unlock()
+		return ErrClosed
+	}
+	o.buffer = append(o.buffer, entry)
+	count := len(o.buffer)
+	o.locker.Unlock()
+
+	if count >= o.options.BatchSize {
+		// Wake up the flushing goroutine
+		select {
+		case o.kick <- struct{}{}:
+		default:
+		}
+	}
+
+	return nil
+}
+
+func (o *Logger) Log(level logs.LogLevel, msg string, err error) {
+	entry := &logs.LogEntry{
+		Level:   level,
+		Message: msg,
+	}
+	setError(entry, err)
+
+	o.Write(entry)
+}
+
+func setError(entry *logs.LogEntry, err error) {
+	if err == nil {
+		return
+	}
+
+	entry.Error = err.Error()
+	// Errors carrying a stack print it with %+v
+	if stackTrace := fmt.Sprintf("%+v", err); stackTrace != entry.Error {
+		entry.StackTrace = stackTrace
+	}
+}
+
+func (o *Logger) Debug(msg string) {
+	o.Log(logs.LogLevel_Debug, msg, nil)
+}
+func (o *Logger) Info(msg string) {
+	o.Log(logs.LogLevel_Infomation, msg, nil)
+}
+func (o *Logger) Warn(msg string) {
+	o.Log(logs.LogLevel_Warning, msg, nil)
+}
+func (o *Logger) Error(msg string, err error) {
+	o.Log(logs.LogLevel_Error, msg, err)
+}
+func (o *Logger) Fatal(msg string, err error) {
+	o.Log(logs.LogLevel_Fatal, msg, err)
+}
+
+func (o *Logger) take() []*logs.LogEntry {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+
+	r := o.buffer
+	o.buffer = make([]*logs.LogEntry, 0, o.options.BatchSize)
+	o.notFull.Broadcast()
+
+	return r
+}
+
+// Flush sends all buffered entries, entries failed to send are not retried
+func (o *Logger) Flush() error {
+	o.flushLocker.Lock()
+	defer o.flushLocker.Unlock()
+
+	entries := o.take()
+
+	var errs []error
+	for start := 0; start < len(entries); start += o.options.BatchSize {
+		end := min(start+o.options.BatchSize, len(entries))
+		errs = append(errs, o.send(entries[start:end]))
+	}
+
+	return errors.Join(errs...)
+}
+
+// newContext limits a call to Timeout and carries the API key
+func (o *Logger) newContext() (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(context.Background(), o.options.Timeout)
+	if o.options.APIKey != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+o.options.APIKey)
+	}
+	return ctx, cancel
+}
+
+func (o *Logger) send(batch []*logs.LogEntry) error {
+	ctx, cancel := o.newContext()
+	defer cancel()
+
+	rs, err := o.client.WriteLogEntries(ctx, &logs.WriteLogEntriesCommand{
+		ClientID:   o.options.ClientID,
+		LogEntries: batch,
+	})
+	if err != nil {
+		return err
+	} else if rs.Message != "" {
+		return errors.New(rs.Message)
+	}
+
+	var errs []error
+	for _, x := range rs.Results {
+		if x.Message != "" {
+			errs = append(errs, errors.New(x.Message))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Close stops background flushing and sends the remaining entries, writes after Close return ErrClosed
+func (o *Logger) Close() error {
+	o.locker.Lock()
+	if o.closed {
+		o.locker.Unlock()
+		return nil
+	}
+	o.closed = true
+	o.notFull.Broadcast()
+	o.locker.Unlock()
+
+	close(o.done)
+	o.wg.Wait()
+
+	return o.Flush()
+}
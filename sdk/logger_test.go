@@ -0,0 +1,209 @@
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DreamvatLab/logs"
+	"github.com/DreamvatLab/logs/sdk"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// logServer records the batches written to it
+type logServer struct {
+	logs.UnimplementedLogEntryServiceServer
+	locker         sync.Mutex
+	batches        [][]*logs.LogEntry
+	clientIDs      []string
+	authorizations []string
+}
+
+func (o *logServer) WriteLogEntries(ctx context.Context, in *logs.WriteLogEntriesCommand) (*logs.WriteLogEntriesResult, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	o.batches = append(o.batches, in.LogEntries)
+	o.clientIDs = append(o.clientIDs, in.ClientID)
+	o.authorizations = append(o.authorizations, md.Get("authorization")...)
+
+	r := &logs.WriteLogEntriesResult{Results: make([]*logs.WriteLogEntryResult, 0, len(in.LogEntries))}
+	for i := range in.LogEntries {
+		r.Results = append(r.Results, &logs.WriteLogEntryResult{ID: strconv.Itoa(i)})
+	}
+	return r, nil
+}
+
+func (o *logServer) entries() []*logs.LogEntry {
+	o.locker.Lock()
+	defer o.locker.Unlock()
+	var r []*logs.LogEntry
+	for _, x := range o.batches {
+		r = append(r, x...)
+	}
+	return r
+}
+
+// clientServer serves a client with a level
+type clientServer struct {
+	logs.UnimplementedLogClientServiceServer
+	level atomic.Int32
+}
+
+func (o *clientServer) GetClient(ctx context.Context, in *logs.LogClientQuery) (*logs.LogClientResult, error) {
+	return &logs.LogClientResult{LogClient: &logs.LogClient{ID: in.ID, Level: logs.LogLevel(o.level.Load())}}, nil
+}
+
+// startServer serves a logServer and a clientServer in process, the connection and the server stop with the test
+func startServer(t *testing.T) (*logServer, *grpc.ClientConn) {
+	r, _, conn := startServers(t)
+	return r, conn
+}
+
+func startServers(t *testing.T) (*logServer, *clientServer, *grpc.ClientConn) {
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	r := new(logServer)
+	logs.RegisterLogEntryServiceServer(server, r)
+	clients := new(clientServer)
+	logs.RegisterLogClientServiceServer(server, clients)
+	go server.Serve(listener)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		conn.Close()
+		server.Stop()
+	})
+
+	return r, clients, conn
+}
+
+func TestLoggerFlush(t *testing.T) {
+	server, conn := startServer(t)
+	logger := sdk.NewLogger(logs.NewLogEntryServiceClient(conn), sdk.Options{ClientID: "c1", APIKey: "k1", Level: logs.LogLevel_Infomation, FlushInterval: time.Hour})
+	defer logger.Close()
+
+	logger.Debug("skipped")
+	logger.Info("started")
+	logger.Error("failed", errors.New("timeout"))
+	err := logger.Write(&logs.LogEntry{Level: logs.LogLevel_Warning, Message: "slow", CreatedOnUtc: 1700000000000})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := server.entries(); len(got) != 0 {
+		t.Fatalf("entries before Flush = %d, want 0", len(got))
+	}
+
+	err = logger.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := server.entries()
+	if len(got) != 3 {
+		t.Fatalf("entries = %v, want 3", got)
+	}
+	if got[0].Message != "started" || got[1].Message != "failed" || got[1].Error != "timeout" || got[1].Level != logs.LogLevel_Error {
+		t.Errorf("entries = %v, want started and failed", got[:2])
+	}
+	for _, x := range got[:2] {
+		if x.CreatedOnUtc == 0 || x.CreatedOnUtcISO != time.UnixMilli(x.CreatedOnUtc).UTC().Format(time.RFC3339) {
+			t.Errorf("CreatedOnUtc = %d, CreatedOnUtcISO = %q, want both filled", x.CreatedOnUtc, x.CreatedOnUtcISO)
+		}
+	}
+	if got[2].CreatedOnUtc != 1700000000000 || got[2].CreatedOnUtcISO != "2023-11-14T22:13:20Z" {
+		t.Errorf("CreatedOnUtc = %d, CreatedOnUtcISO = %q, want the given time kept", got[2].CreatedOnUtc, got[2].CreatedOnUtcISO)
+	}
+	if server.clientIDs[0] != "c1" || server.authorizations[0] != "Bearer k1" {
+		t.Errorf("ClientID = %q, authorization = %v, want c1 and Bearer k1", server.clientIDs[0], server.authorizations)
+	}
+}
+
+func TestLoggerBatching(t *testing.T) {
+	server, conn := startServer(t)
+	logger := sdk.NewLogger(logs.NewLogEntryServiceClient(conn), sdk.Options{BatchSize: 2, FlushInterval: time.Hour})
+	defer logger.Close()
+
+	for i := range 5 {
+		logger.Info(strconv.Itoa(i))
+	}
+
+	// A full batch wakes up flushing without waiting for the interval
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.entries()) < 2 {
+		if time.Now().After(deadline) {
+			t.Fatal("no batch was sent after BatchSize entries")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	err := logger.Flush()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := server.entries(); len(got) != 5 {
+		t.Fatalf("entries = %d, want 5", len(got))
+	}
+	server.locker.Lock()
+	defer server.locker.Unlock()
+	for _, x := range server.batches {
+		if len(x) > 2 {
+			t.Errorf("batch of %d entries, want at most 2", len(x))
+		}
+	}
+}
+
+func TestLoggerInterval(t *testing.T) {
+	server, conn := startServer(t)
+	logger := sdk.NewLogger(logs.NewLogEntryServiceClient(conn), sdk.Options{FlushInterval: 50 * time.Millisecond})
+	defer logger.Close()
+
+	logger.Warn("queued")
+
+	deadline := time.Now().Add(5 * time.Second)
+	for len(server.entries()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("the entry was not sent after FlushInterval")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestLoggerClose(t *testing.T) {
+	server, conn := startServer(t)
+	logger := sdk.NewLogger(logs.NewLogEntryServiceClient(conn), sdk.Options{FlushInterval: time.Hour})
+
+	logger.Info("a")
+	logger.Info("b")
+	err := logger.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := server.entries(); len(got) != 2 {
+		t.Fatalf("entries after Close = %d, want 2", len(got))
+	}
+
+	if err = logger.Write(&logs.LogEntry{Message: "late"}); !errors.Is(err, sdk.ErrClosed) {
+		t.Errorf("Write after Close = %v, want ErrClosed", err)
+	}
+	if err = logger.Close(); err != nil {
+		t.Errorf("second Close = %v, want nil", err)
+	}
+}
@@ -0,0 +1,72 @@
+package sdk
+
+import (
+	"time"
+
+	"github.com/DreamvatLab/logs"
+)
+
+// DropPolicy decides what happens when an entry is written to a full buffer.
+type DropPolicy int32
+
+const (
+	// DropNewest discards the incoming entry
+	DropNewest DropPolicy = iota
+	// DropOldest discards the oldest buffered entry to make room for the incoming one
+	DropOldest
+	// Block waits until the buffer has room
+	Block
+)
+
+const (
+	_DEFAULT_BUFFER_SIZE    = 10000
+	_DEFAULT_BATCH_SIZE     = 100
+	_DEFAULT_FLUSH_INTERVAL = time.Second * 3
+	_DEFAULT_TIMEOUT        = time.Second * 10
+	_DEFAULT_LEVEL_REFRESH  = time.Minute
+)
+
+type Options struct {
+	// Client id registered in the logs host
+	ClientID string
+	// Key issued to the client, sent as "authorization: Bearer <APIKey>", empty to send none
+	APIKey string
+	// Entries lower than this level are discarded without being buffered
+	Level logs.LogLevel
+	// Reads LogClient.Level of ClientID, entries lower than it are discarded too, nil to use Level only
+	ClientService logs.LogClientServiceClient
+	// How often the level of the client is read again, default 1m
+	LevelRefreshInterval time.Duration
+	// Max entries kept in memory, default 10000
+	BufferSize int
+	// Entries count that triggers a flush, default 100
+	BatchSize int
+	// Max time an entry stays in buffer, default 3s
+	FlushInterval time.Duration
+	// Timeout of each rpc call, default 10s
+	Timeout    time.Duration
+	DropPolicy DropPolicy
+	// Called when background flushing fails, errors are discarded if nil
+	ErrorHandler func(error)
+}
+
+func (o *Options) normalize() {
+	if o.BufferSize <= 0 {
+		o.BufferSize = _DEFAULT_BUFFER_SIZE
+	}
+	if o.BatchSize <= 0 {
+		o.BatchSize = _DEFAULT_BATCH_SIZE
+	}
+	if o.BatchSize > o.BufferSize {
+		o.BatchSize = o.BufferSize
+	}
+	if o.FlushInterval <= 0 {
+		o.FlushInterval = _DEFAULT_FLUSH_INTERVAL
+	}
+	if o.Timeout <= 0 {
+		o.Timeout = _DEFAULT_TIMEOUT
+	}
+	if o.LevelRefreshInterval <= 0 {
+		o.LevelRefreshInterval = _DEFAULT_LEVEL_REFRESH
+	}
+}